@@ -53,6 +53,10 @@ func main() {
 	container.ResultPro.StartResultProcessor()
 	// start alert service
 	container.AlertSvc.Run()
+	// start latency rollups
+	go container.Rollup.Run()
+	// start retention cleaner
+	go container.Retention.Run()
 
 	// all heroes are initialized
 	log.Info().Msg("all heroes initialized")
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"project-k/pkg/httpclient"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -51,6 +52,37 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("scheduler.interval", "10s")
 	v.SetDefault("scheduler.batch_size", 10)
 
+	v.SetDefault("executor.region", "default")
+
+	v.SetDefault("alert.max_attempts", 3)
+	v.SetDefault("alert.retry_backoff", "2s")
+	v.SetDefault("alert.smtp.port", 587)
+	v.SetDefault("alert.smtp.tls", "starttls")
+	v.SetDefault("alert.smtp.timeout", "10s")
+	v.SetDefault("alert.webhook_timeout", "10s")
+	v.SetDefault("alert.allow_private_webhooks", false)
+	v.SetDefault("alert.pagerduty_url", "https://events.pagerduty.com")
+	v.SetDefault("alert.pagerduty_eu_url", "https://events.eu.pagerduty.com")
+	v.SetDefault("alert.opsgenie_url", "https://api.opsgenie.com")
+	v.SetDefault("alert.opsgenie_eu_url", "https://api.eu.opsgenie.com")
+
+	v.SetDefault("result_processor.history_batch_size", 500)
+	v.SetDefault("result_processor.history_flush_interval", "1s")
+	v.SetDefault("result_processor.history_channel_size", 1000)
+
+	v.SetDefault("rollup.interval", "1m")
+	v.SetDefault("rollup.settle_delay", "30s")
+
+	v.SetDefault("retention.interval", "1h")
+	v.SetDefault("retention.batch_size", 5000)
+	v.SetDefault("retention.batch_pause", "100ms")
+	v.SetDefault("retention.free.raw_days", 7)
+	v.SetDefault("retention.free.minute_rollup_days", 7)
+	v.SetDefault("retention.free.rollup_months", 3)
+	v.SetDefault("retention.paid.raw_days", 30)
+	v.SetDefault("retention.paid.minute_rollup_days", 30)
+	v.SetDefault("retention.paid.rollup_months", 24)
+
 	v.SetDefault("redis.dial_timeout", "5s")
 	v.SetDefault("redis.read_timeout", "3s")
 	v.SetDefault("redis.write_timeout", "3s")
@@ -77,6 +109,22 @@ func validateConfig(cfg *Config) error {
 		}
 		return err
 	}
+
+	// a paid plan never keeps less than the free one
+	free, paid := cfg.Retention.Free, cfg.Retention.Paid
+	if paid.RawDays < free.RawDays || paid.MinuteRollupDays < free.MinuteRollupDays || paid.RollupMonths < free.RollupMonths {
+		return fmt.Errorf("config validation failed:\n- field 'Config.Retention.Paid': must not be shorter than 'Config.Retention.Free'")
+	}
+
+	if cfg.Alert.SMTP.Username != "" && cfg.Alert.SMTP.TLS == "none" {
+		return fmt.Errorf("config validation failed:\n- field 'Config.Alert.SMTP.TLS': credentials are never sent over plain smtp, use starttls or tls")
+	}
+
+	if cfg.Executor.Proxy != "" {
+		if _, err := httpclient.ParseProxyURL(cfg.Executor.Proxy); err != nil {
+			return fmt.Errorf("config validation failed:\n- field 'Config.Executor.Proxy': %v", err)
+		}
+	}
 	return nil
 }
 
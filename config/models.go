@@ -6,6 +6,8 @@ type AuthConfig struct {
 	Secret string `mapstructure:"secret" validate:"required"`
 	// it parsed as minutes
 	TokenTTL time.Duration `mapstructure:"token_ttl" validate:"gt=0"`
+	// base64 of 32 random bytes, seals monitor credentials in db and cache, changing it makes stored ones unreadable
+	EncryptionKey string `mapstructure:"encryption_key" validate:"required,base64"`
 }
 
 type AppConfig struct {
@@ -28,12 +30,38 @@ type ReclaimerConfig struct {
 type ExecutorConfig struct {
 	WorkerCount  int `mapstructure:"worker_count" validate:"gte=5,lte=200"`
 	HTTPSemCount int `mapstructure:"http_semaphore_count" validate:"gte=5,lte=6000"`
+	// default proxy of http, transaction and websocket checks (http, https, socks5), empty for direct
+	Proxy string `mapstructure:"proxy" validate:"omitempty,url"`
+	// region this instance checks from, stored with every check result
+	Region string `mapstructure:"region" validate:"required,max=64"`
 }
 
 type AlertConfig struct {
 	WorkerCount int    `mapstructure:"worker_count" validate:"gte=5"`
-	OwnerEmail  string `mapstructure:"owner_email" validate:"required"`
-	AccessKey   string `mapstructure:"access_key" validate:"required"`
+	OwnerEmail  string `mapstructure:"owner_email" validate:"required,email"` // sender (From) of alert emails
+	AccessKey   string `mapstructure:"access_key"`                            // smtp password, empty for a local catch-all
+	// an email is tried up to max attempts, backoff doubles after each failed one
+	MaxAttempts  int           `mapstructure:"max_attempts" validate:"gte=1,lte=10"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff" validate:"gt=0"`
+	SMTP         SMTPConfig    `mapstructure:"smtp" validate:"required"`
+	// of one post to a slack, discord, teams, generic webhook, pagerduty or opsgenie
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout" validate:"gt=0"`
+	// let slack, discord, teams and webhook urls reach private, loopback and link-local addresses, for local setups only
+	AllowPrivateWebhooks bool `mapstructure:"allow_private_webhooks"`
+	// api base urls, of us and eu accounts (region of channel), point them at a local stand-in to test
+	PagerDutyURL   string `mapstructure:"pagerduty_url" validate:"required,url"` // events api v2
+	PagerDutyEUURL string `mapstructure:"pagerduty_eu_url" validate:"required,url"`
+	OpsgenieURL    string `mapstructure:"opsgenie_url" validate:"required,url"`
+	OpsgenieEUURL  string `mapstructure:"opsgenie_eu_url" validate:"required,url"`
+}
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host" validate:"required,hostname|ip"`
+	Port     int    `mapstructure:"port" validate:"gte=1,lte=65535"`
+	Username string `mapstructure:"username"` // no auth if empty
+	// none (plain, for local catch-alls), starttls (upgrade after connect) or tls (implicit, usually port 465)
+	TLS     string        `mapstructure:"tls" validate:"oneof=none starttls tls"`
+	Timeout time.Duration `mapstructure:"timeout" validate:"gt=0"` // of one send, connect to QUIT
 }
 
 type ResultProcessorConfig struct {
@@ -41,6 +69,37 @@ type ResultProcessorConfig struct {
 	SuccessChannelSize int `mapstructure:"success_channel_size" validate:"gte=5"`
 	FailureWorkerCount int `mapstructure:"failure_worker_count" validate:"gte=5"`
 	FailureChannelSize int `mapstructure:"failure_channel_size" validate:"gte=5"`
+	// check results are written to DB in batches, of up to batch size or every flush interval
+	HistoryBatchSize     int           `mapstructure:"history_batch_size" validate:"gte=1,lte=10000"`
+	HistoryFlushInterval time.Duration `mapstructure:"history_flush_interval" validate:"gt=0"`
+	HistoryChannelSize   int           `mapstructure:"history_channel_size" validate:"gte=5"`
+}
+
+type RollupConfig struct {
+	// how often latencies are rolled up
+	Interval time.Duration `mapstructure:"interval" validate:"gt=0"`
+	// raw results younger than this are left for next run, keep it above result_processor.history_flush_interval
+	SettleDelay time.Duration `mapstructure:"settle_delay" validate:"gte=0"`
+}
+
+type RetentionConfig struct {
+	// how often expired history is deleted
+	Interval time.Duration `mapstructure:"interval" validate:"gt=0"`
+	// rows deleted per statement, small batches keep row locks and WAL bursts short
+	BatchSize int `mapstructure:"batch_size" validate:"gte=100,lte=100000"`
+	// pause between batches, so deletes do not starve the check pipeline
+	BatchPause time.Duration         `mapstructure:"batch_pause" validate:"gte=0"`
+	Free       RetentionPolicyConfig `mapstructure:"free" validate:"required"`
+	Paid       RetentionPolicyConfig `mapstructure:"paid" validate:"required"` // users with is_paid_user
+}
+
+// RetentionPolicyConfig is how long history of a plan is kept
+type RetentionPolicyConfig struct {
+	RawDays int `mapstructure:"raw_days" validate:"gte=1,lte=3650"`
+	// hour rollups are merged from minute ones of last 2 days, so they must live at least that long
+	MinuteRollupDays int `mapstructure:"minute_rollup_days" validate:"gte=2,lte=3650"`
+	// hour and day rollups
+	RollupMonths int `mapstructure:"rollup_months" validate:"gte=1,lte=120"`
 }
 
 type RedisConfig struct {
@@ -75,6 +134,8 @@ type Config struct {
 	Executor        ExecutorConfig        `mapstructure:"executor" validate:"required"`
 	Alert           AlertConfig           `mapstructure:"alert" validate:"required"`
 	ResultProcessor ResultProcessorConfig `mapstructure:"result_processor" validate:"required"`
+	Rollup          RollupConfig          `mapstructure:"rollup" validate:"required"`
+	Retention       RetentionConfig       `mapstructure:"retention" validate:"required"`
 	Redis           RedisConfig           `mapstructure:"redis" validate:"required"`
 	DB              DBConfig              `mapstructure:"db" validate:"required"`
 }
@@ -5,9 +5,12 @@ import (
 	"project-k/config"
 	middle "project-k/internals/middleware"
 	"project-k/internals/modules/alert"
+	"project-k/internals/modules/channel"
 	"project-k/internals/modules/executor"
 	"project-k/internals/modules/monitor"
 	"project-k/internals/modules/result"
+	"project-k/internals/modules/retention"
+	"project-k/internals/modules/rollup"
 	"project-k/internals/modules/scheduler"
 	"project-k/internals/modules/user"
 	"project-k/internals/security"
@@ -26,12 +29,18 @@ type Container struct {
 	userSvc        *user.Service
 	userHandler    *user.Handler
 	monitorHandler *monitor.Handler
+	resultHandler  *result.Handler
+	rollupHandler  *rollup.Handler
+	channelHandler *channel.Handler
+	alertHandler   *alert.Handler
 	authMW         *middle.AuthMiddleware
 	Reclaimer      *scheduler.Reclaimer
 	Scheduler      *scheduler.Scheduler
 	Executor       *executor.Executor
 	ResultPro      *result.ResultProcessor
 	AlertSvc       *alert.AlertService
+	Rollup         *rollup.Aggregator
+	Retention      *retention.Cleaner
 	JobChan        chan scheduler.JobPayload
 	ResultChan     chan executor.HTTPResult
 	AlertChan      chan alert.AlertEvent
@@ -44,30 +53,70 @@ func NewContainer(ctx context.Context, db *pgxpool.Pool, cfg *config.Config, log
 		return nil, err
 	}
 	tokenSvc := security.NewTokenService(&cfg.Auth)
+	cipher, err := security.NewCipher(&cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
 
 	jobChan := make(chan scheduler.JobPayload, cfg.App.JobChannelSize)      // specify channel size in config
 	resultChan := make(chan executor.HTTPResult, cfg.App.ResultChannelSize) // specify channel size in config
 	alertChan := make(chan alert.AlertEvent, cfg.App.AlertChannelSize)      // specify channel size in config
 
 	validator := validator.New()
+	if err := monitor.RegisterValidations(validator); err != nil {
+		return nil, err
+	}
 
-	monitorRepo := monitor.NewRepository(db, logger)
+	monitorRepo := monitor.NewRepository(db, cipher, logger)
 	incidentRepo := result.NewMonitorIncidentRepo(db, logger)
+	historyRepo := result.NewCheckResultRepo(db, logger)
+	rollupRepo := rollup.NewRepository(db, logger)
+	retentionRepo := retention.NewRepository(db, logger)
+	alertRepo := alert.NewRepository(db, logger)
+	channelRepo := channel.NewRepository(db, cipher, logger)
 	userRepo := user.NewRepository(db, logger)
 
+	// monitors created before auth was sealed still hold plaintext credentials
+	sealed, err := monitorRepo.SealPlainAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if sealed > 0 {
+		logger.Info().Int("monitors", sealed).Msg("sealed plaintext monitor auth")
+	}
+	// and channels created before their secrets were sealed
+	sealed, err = channelRepo.SealPlainConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if sealed > 0 {
+		logger.Info().Int("channels", sealed).Msg("sealed plaintext channel config")
+	}
+
 	httpClient := httpclient.NewHttpClient()
+	mailer := alert.NewSMTPMailer(&cfg.Alert)
+	notifiers := alert.NewNotifiers(&cfg.Alert, mailer)
 
 	userService := user.NewService(userRepo, tokenSvc)
 	monitorSvc := monitor.NewService(monitorRepo, redisClient, userService, logger)
+	resultSvc := result.NewService(incidentRepo, historyRepo, monitorSvc)
+	rollupSvc := rollup.NewService(rollupRepo, monitorSvc)
+	channelSvc := channel.NewService(channelRepo, monitorSvc)
 
 	reclaimer := scheduler.NewReclaimer(ctx, &cfg.Reclaimer, redisClient, logger)
 	sch := scheduler.NewScheduler(ctx, &cfg.Scheduler, jobChan, redisClient, logger)
-	exec := executor.NewExecutor(ctx, &cfg.Executor, jobChan, resultChan, monitorSvc, httpClient, logger)
-	resultPro := result.NewResultProcessor(ctx, &cfg.ResultProcessor, redisClient, resultChan, incidentRepo, monitorSvc, alertChan, logger)
-	alertSvc := alert.NewAlertService(&cfg.Alert, alertChan, logger)
+	exec := executor.NewExecutor(ctx, &cfg.Executor, jobChan, resultChan, monitorSvc, httpClient, redisClient, logger)
+	resultPro := result.NewResultProcessor(ctx, &cfg.ResultProcessor, redisClient, resultChan, incidentRepo, historyRepo, monitorSvc, alertChan, logger)
+	alertSvc := alert.NewAlertService(&cfg.Alert, alertChan, alertRepo, notifiers, monitorSvc, channelSvc, logger)
+	aggregator := rollup.NewAggregator(ctx, &cfg.Rollup, rollupRepo, redisClient, logger)
+	cleaner := retention.NewCleaner(ctx, &cfg.Retention, retentionRepo, redisClient, logger)
 
 	monitorHandler := monitor.NewHandler(monitorSvc, validator, logger)
 	userHandler := user.NewHandler(userService, validator, logger)
+	resultHandler := result.NewHandler(resultSvc, logger)
+	rollupHandler := rollup.NewHandler(rollupSvc, logger)
+	channelHandler := channel.NewHandler(channelSvc, validator, logger)
+	alertHandler := alert.NewHandler(alertSvc, logger)
 
 	authMW := middle.NewAuthMiddleware(tokenSvc)
 
@@ -79,11 +128,17 @@ func NewContainer(ctx context.Context, db *pgxpool.Pool, cfg *config.Config, log
 		userHandler:    userHandler,
 		authMW:         authMW,
 		monitorHandler: monitorHandler,
+		resultHandler:  resultHandler,
+		rollupHandler:  rollupHandler,
+		channelHandler: channelHandler,
+		alertHandler:   alertHandler,
 		Reclaimer:      reclaimer,
 		Scheduler:      sch,
 		Executor:       exec,
 		ResultPro:      resultPro,
 		AlertSvc:       alertSvc,
+		Rollup:         aggregator,
+		Retention:      cleaner,
 		JobChan:        jobChan,
 		ResultChan:     resultChan,
 		AlertChan:      alertChan,
@@ -2,7 +2,11 @@ package app
 
 import (
 	middle "project-k/internals/middleware"
+	"project-k/internals/modules/alert"
+	"project-k/internals/modules/channel"
 	"project-k/internals/modules/monitor"
+	"project-k/internals/modules/result"
+	"project-k/internals/modules/rollup"
 	"project-k/internals/modules/user"
 	"time"
 
@@ -24,6 +28,16 @@ func RegisterRoutes(c *Container) chi.Router {
 		v1.Mount("/users", user.Routes(c.userHandler, c.authMW))
 
 		v1.With(c.authMW.Handle).Mount("/monitors", monitor.Routes(c.monitorHandler))
+		v1.With(c.authMW.Handle).Mount("/monitors/{monitorID}/incidents", result.IncidentRoutes(c.resultHandler))
+		v1.With(c.authMW.Handle).Mount("/monitors/{monitorID}/checks", result.HistoryRoutes(c.resultHandler))
+		v1.With(c.authMW.Handle).Mount("/monitors/{monitorID}/uptime", result.MonitorUptimeRoutes(c.resultHandler))
+		v1.With(c.authMW.Handle).Mount("/uptime", result.UptimeRoutes(c.resultHandler))
+		v1.With(c.authMW.Handle).Mount("/monitors/{monitorID}/latency", rollup.Routes(c.rollupHandler))
+		v1.With(c.authMW.Handle).Mount("/monitors/{monitorID}/channels", channel.MonitorRoutes(c.channelHandler))
+		v1.With(c.authMW.Handle).Mount("/channels", channel.Routes(c.channelHandler))
+		v1.With(c.authMW.Handle).Mount("/channels/{channelID}/deliveries", alert.DeliveryRoutes(c.alertHandler))
+
+		v1.Mount("/heartbeat", monitor.HeartbeatRoutes(c.monitorHandler))
 
 		// if you want to apply to some specific routes , then pass it with handler
 		//  like this
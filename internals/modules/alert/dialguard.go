@@ -0,0 +1,80 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var errPrivateAddress = errors.New("webhook host resolves to a private, loopback or link-local address")
+
+// not public, but not covered by netip.Addr helpers
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // this network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier grade nat
+	netip.MustParsePrefix("192.0.0.0/24"),   // ietf protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // nat64, embeds an ipv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local nat64
+	netip.MustParsePrefix("2001:db8::/32"),  // documentation
+}
+
+// isPublicAddr reports whether ip is routable on the internet, so not loopback, private (rfc1918, fc00::/7),
+// link-local (169.254.0.0/16 with cloud metadata, fe80::/10), multicast or reserved
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, p := range reservedPrefixes {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// publicOnly is a dialer control rejecting connections to non public addresses, it runs on the resolved ip
+// of every dial, so a host re-resolving to an internal address (dns rebinding) is caught as well
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(ip) {
+		// the url of a channel does not change between retries
+		return &permanentError{err: fmt.Errorf("%w (%s)", errPrivateAddress, ip)}
+	}
+	return nil
+}
+
+// newWebhookClient returns the client posting to user given urls (slack, discord, teams, webhook),
+// it only connects to public addresses unless allowPrivate, and never through a proxy (it would dial for us)
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = publicOnly
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// a webhook url is the endpoint itself, a redirect is a misconfigured channel
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"project-k/internals/modules/channel"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"8.8.8.8", true},
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false}, // cloud metadata
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},       // ipv4 mapped
+		{"::ffff:169.254.169.254", false}, // ipv4 mapped
+		{"64:ff9b::a9fe:a9fe", false},     // nat64 of 169.254.169.254
+	}
+
+	for _, tt := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(tt.ip)); got != tt.public {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", tt.ip, got, tt.public)
+		}
+	}
+}
+
+func TestWebhookClientRejectsPrivateAddress(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+
+	// by ip and by a name resolving to loopback
+	urls := []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)}
+	for _, u := range urls {
+		d := &Delivery{
+			Config:       channelConfig(u),
+			Notification: testNotification(),
+		}
+		n := &webhookNotifier{client: newWebhookClient(5*time.Second, false), payload: webhookPayload, generic: true}
+
+		_, err := n.Notify(context.Background(), d)
+		if !errors.Is(err, errPrivateAddress) {
+			t.Fatalf("%s: err = %v, want private address rejection", u, err)
+		}
+		if !isPermanent(err) {
+			t.Errorf("%s: private address rejection is not permanent, it would be retried", u)
+		}
+	}
+	if hits != 0 {
+		t.Errorf("server got %d requests, want none", hits)
+	}
+
+	// allowed for local setups
+	n := &webhookNotifier{client: newWebhookClient(5*time.Second, true), payload: webhookPayload, generic: true}
+	if _, err := n.Notify(context.Background(), &Delivery{Config: channelConfig(srv.URL), Notification: testNotification()}); err != nil {
+		t.Fatalf("allowed private delivery: %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("server got %d requests, want 1", hits)
+	}
+}
+
+func channelConfig(url string) channel.Config {
+	return channel.Config{URL: url, Secret: "whsec_test"}
+}
+
+func testNotification() *Notification {
+	return &Notification{
+		Event: AlertEvent{
+			MonitorID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			Type:      AlertDown,
+			Message:   "TIMEOUT",
+		},
+		Monitor: MonitorInfo{
+			ID:   uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			URL:  "https://example.com",
+			Type: "http",
+		},
+		At: time.Unix(1700000000, 0),
+	}
+}
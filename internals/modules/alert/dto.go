@@ -0,0 +1,83 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GetDeliveriesResponse struct {
+	ChannelID  string             `json:"channel_id"`
+	Deliveries []DeliveryResponse `json:"deliveries"`
+}
+
+type DeliveryResponse struct {
+	ID         string     `json:"id"`
+	AlertType  string     `json:"alert_type"`
+	MonitorID  string     `json:"monitor_id"`
+	IncidentID string     `json:"incident_id,omitempty"`
+	Status     string     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"last_error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	SentAt     *time.Time `json:"sent_at,omitempty"` // nil until sent
+	// false for deliveries stored before notifications were kept
+	Redeliverable bool              `json:"redeliverable"`
+	AttemptLog    []AttemptResponse `json:"attempt_log"`
+}
+
+type AttemptResponse struct {
+	Attempt    int       `json:"attempt"`
+	Success    bool      `json:"success"`
+	StatusCode int       `json:"status_code,omitempty"` // missing if there was no http response
+	LatencyMs  int64     `json:"latency_ms"`
+	Response   string    `json:"response,omitempty"` // first 512 bytes of response body
+	Error      string    `json:"error,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+type RedeliverResponse struct {
+	DeliveryID string `json:"delivery_id"`
+	Status     string `json:"status"`
+}
+
+func toDeliveryResponse(a *Alert) DeliveryResponse {
+	res := DeliveryResponse{
+		ID:            a.ID.String(),
+		AlertType:     string(a.Type),
+		MonitorID:     a.MonitorID.String(),
+		Status:        a.Status,
+		Attempts:      a.Attempts,
+		LastError:     a.LastError,
+		CreatedAt:     a.CreatedAt,
+		Redeliverable: a.Notification != nil,
+		AttemptLog:    make([]AttemptResponse, 0, len(a.AttemptLog)),
+	}
+	if a.IncidentID != uuid.Nil {
+		res.IncidentID = a.IncidentID.String()
+	}
+	if !a.SentAt.IsZero() {
+		t := a.SentAt
+		res.SentAt = &t
+	}
+	for _, at := range a.AttemptLog {
+		res.AttemptLog = append(res.AttemptLog, AttemptResponse{
+			Attempt:    at.Attempt,
+			Success:    at.Success,
+			StatusCode: at.StatusCode,
+			LatencyMs:  at.Latency.Milliseconds(),
+			Response:   at.Response,
+			Error:      at.Error,
+			At:         at.At,
+		})
+	}
+	return res
+}
+
+func toDeliveryResponses(alerts []Alert) []DeliveryResponse {
+	res := make([]DeliveryResponse, 0, len(alerts))
+	for i := range alerts {
+		res = append(res, toDeliveryResponse(&alerts[i]))
+	}
+	return res
+}
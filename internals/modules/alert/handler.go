@@ -0,0 +1,136 @@
+package alert
+
+import (
+	"net/http"
+	middle "project-k/internals/middleware"
+	"project-k/pkg/apperror"
+	"project-k/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// default and max page size of deliveries
+const (
+	defaultPageSize int32 = 50
+	maxPageSize     int32 = 200
+)
+
+type Handler struct {
+	service *AlertService
+	logger  *zerolog.Logger
+}
+
+func NewHandler(service *AlertService, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// /channels/{channelID}/deliveries?offset=0&limit=50
+func (h *Handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.get_deliveries"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+	limit, offset, err := pageParams(r, defaultPageSize, maxPageSize)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	alerts, err := h.service.ListDeliveries(ctx, reqClaims.UserID, channelID, limit, offset)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving deliveries error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "deliveries retrieved successfully", GetDeliveriesResponse{
+		ChannelID:  channelID.String(),
+		Deliveries: toDeliveryResponses(alerts),
+	})
+}
+
+func (h *Handler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.redeliver"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	a, err := h.service.Redeliver(ctx, reqClaims.UserID, channelID, deliveryID)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("redeliver error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, reqID, "redelivery started", RedeliverResponse{
+		DeliveryID: a.ID.String(),
+		Status:     a.Status,
+	})
+}
+
+// pageParams parses optional limit and offset of query,
+// limit falls back to def when missing, non positive or above maxLimit
+func pageParams(r *http.Request, def, maxLimit int32) (int32, int32, error) {
+	var limit, offset int64
+	var err error
+	if s := r.URL.Query().Get("limit"); s != "" {
+		if limit, err = strconv.ParseInt(s, 10, 32); err != nil {
+			return 0, 0, err
+		}
+	}
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if offset, err = strconv.ParseInt(s, 10, 32); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if limit <= 0 || limit > int64(maxLimit) {
+		limit = int64(def)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return int32(limit), int32(offset), nil
+}
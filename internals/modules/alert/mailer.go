@@ -0,0 +1,155 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"project-k/config"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Email is a plain text email
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers an email
+type Mailer interface {
+	Send(ctx context.Context, e Email) error
+}
+
+// SMTPMailer sends emails over SMTP, a new connection per email (alerts are rare, so no pool)
+type SMTPMailer struct {
+	host     string
+	addr     string
+	from     string
+	username string
+	password string
+	tlsMode  string
+	timeout  time.Duration
+	rootCAs  *x509.CertPool // nil for system roots
+}
+
+func NewSMTPMailer(alertConfig *config.AlertConfig) *SMTPMailer {
+	smtpCfg := alertConfig.SMTP
+	return &SMTPMailer{
+		host:     smtpCfg.Host,
+		addr:     net.JoinHostPort(smtpCfg.Host, strconv.Itoa(smtpCfg.Port)),
+		from:     alertConfig.OwnerEmail,
+		username: smtpCfg.Username,
+		password: alertConfig.AccessKey,
+		tlsMode:  smtpCfg.TLS,
+		timeout:  smtpCfg.Timeout,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, e Email) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	conn, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("connect %s: %w", m.addr, err)
+	}
+	// smtp client has no ctx, deadline of conn bounds every command
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp greeting: %w", err)
+	}
+	defer c.Close()
+
+	if m.tlsMode == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := c.StartTLS(m.tlsConfig()); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(m.from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(e.To); err != nil {
+		return fmt.Errorf("smtp RCPT TO: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if _, err := w.Write(m.message(e)); err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	return c.Quit()
+}
+
+func (m *SMTPMailer) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{}
+	if m.tlsMode == "tls" {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: m.tlsConfig()}
+		return tlsDialer.DialContext(ctx, "tcp", m.addr)
+	}
+	return dialer.DialContext(ctx, "tcp", m.addr)
+}
+
+func (m *SMTPMailer) tlsConfig() *tls.Config {
+	return &tls.Config{ServerName: m.host, RootCAs: m.rootCAs}
+}
+
+// message renders e as a RFC 5322 message, body is quoted-printable utf-8
+func (m *SMTPMailer) message(e Email) []byte {
+	domain := "monit.local"
+	if at := strings.LastIndexByte(m.from, '@'); at >= 0 {
+		domain = m.from[at+1:]
+	}
+
+	var buf bytes.Buffer
+	header := func(k, v string) {
+		// values come from user input (monitor url), a newline would start a new header
+		v = strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, v)
+	}
+	header("From", m.from)
+	header("To", e.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), domain))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	qp.Write([]byte(strings.ReplaceAll(e.Body, "\n", "\r\n")))
+	qp.Close()
+
+	return buf.Bytes()
+}
@@ -0,0 +1,323 @@
+package alert
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// smtpStandIn is an in process smtp server, it speaks plain, STARTTLS or implicit tls (mode)
+// and answers RCPT TO with rcptReply
+type smtpStandIn struct {
+	addr      string
+	mode      string
+	tlsConfig *tls.Config
+	rcptReply string
+
+	mu   sync.Mutex
+	mail receivedMail
+}
+
+// receivedMail is what one session of the stand-in got
+type receivedMail struct {
+	tls  bool   // data was sent over tls
+	auth string // decoded AUTH PLAIN response
+	from string
+	to   string
+	data []byte
+}
+
+func newSMTPStandIn(t *testing.T, mode, rcptReply string) (*smtpStandIn, *x509.CertPool) {
+	cert, pool := testCert(t)
+	s := &smtpStandIn{
+		mode:      mode,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		rcptReply: rcptReply,
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode == "tls" {
+		ln = tls.NewListener(ln, s.tlsConfig)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s.addr = ln.Addr().String()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, pool
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, secure := conn.(*tls.Conn)
+	tc := textproto.NewConn(conn)
+	tc.PrintfLine("220 stand-in ESMTP")
+
+	var m receivedMail
+	for {
+		line, err := tc.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			ext := []string{"stand-in"}
+			if s.mode == "starttls" && !secure {
+				ext = append(ext, "STARTTLS")
+			}
+			ext = append(ext, "AUTH PLAIN")
+			for i, e := range ext {
+				sep := "-"
+				if i == len(ext)-1 {
+					sep = " "
+				}
+				tc.PrintfLine("250%s%s", sep, e)
+			}
+		case "STARTTLS":
+			tc.PrintfLine("220 go ahead")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, secure = tlsConn, true
+			tc = textproto.NewConn(conn)
+		case "AUTH":
+			_, resp, _ := strings.Cut(arg, " ")
+			b, _ := base64.StdEncoding.DecodeString(resp)
+			m.auth = string(b)
+			tc.PrintfLine("235 accepted")
+		case "MAIL":
+			m.from = strings.TrimSuffix(strings.TrimPrefix(arg, "FROM:<"), ">")
+			tc.PrintfLine("250 ok")
+		case "RCPT":
+			m.to = strings.TrimSuffix(strings.TrimPrefix(arg, "TO:<"), ">")
+			tc.PrintfLine("%s", s.rcptReply)
+		case "DATA":
+			tc.PrintfLine("354 go ahead")
+			data, err := tc.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			m.data, m.tls = data, secure
+			s.mu.Lock()
+			s.mail = m
+			s.mu.Unlock()
+			tc.PrintfLine("250 queued")
+		case "QUIT":
+			tc.PrintfLine("221 bye")
+			return
+		default:
+			tc.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func (s *smtpStandIn) received() receivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mail
+}
+
+func (s *smtpStandIn) mailer(pool *x509.CertPool, username string) *SMTPMailer {
+	host, _, _ := net.SplitHostPort(s.addr)
+	return &SMTPMailer{
+		host:     host,
+		addr:     s.addr,
+		from:     "alerts@monit.example",
+		username: username,
+		password: "s3cret",
+		tlsMode:  s.mode,
+		timeout:  5 * time.Second,
+		rootCAs:  pool,
+	}
+}
+
+// testCert is a self signed certificate of 127.0.0.1, and a pool trusting it
+func testCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "smtp stand-in"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	tests := []struct {
+		mode     string
+		username string
+		tls      bool
+	}{
+		{mode: "none"},                    // local catch-all
+		{mode: "none", username: "monit"}, // net/smtp allows auth in plain only to localhost
+		{mode: "starttls", username: "monit", tls: true},
+		{mode: "tls", username: "monit", tls: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+"/"+tt.username, func(t *testing.T) {
+			srv, pool := newSMTPStandIn(t, tt.mode, "250 ok")
+			m := srv.mailer(pool, tt.username)
+
+			e := Email{To: "ops@example.com", Subject: "[Monit] DOWN: https://example.com/ü", Body: "Your monitor is DOWN.\n\nReason: TIMEOUT\n"}
+			if err := m.Send(context.Background(), e); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+
+			got := srv.received()
+			if got.tls != tt.tls {
+				t.Errorf("sent over tls = %v, want %v", got.tls, tt.tls)
+			}
+			if got.from != "alerts@monit.example" || got.to != "ops@example.com" {
+				t.Errorf("envelope = %s -> %s", got.from, got.to)
+			}
+			if want := "\x00monit\x00s3cret"; tt.username != "" && got.auth != want {
+				t.Errorf("auth = %q, want %q", got.auth, want)
+			}
+			if tt.username == "" && got.auth != "" {
+				t.Errorf("auth sent without username: %q", got.auth)
+			}
+
+			msg, err := mail.ReadMessage(bytes.NewReader(got.data))
+			if err != nil {
+				t.Fatalf("message: %v", err)
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+			if err != nil || subject != e.Subject {
+				t.Errorf("subject = %q (%v), want %q", subject, err, e.Subject)
+			}
+			for k, want := range map[string]string{
+				"From":                      "alerts@monit.example",
+				"To":                        "ops@example.com",
+				"Content-Type":              "text/plain; charset=utf-8",
+				"Content-Transfer-Encoding": "quoted-printable",
+			} {
+				if v := msg.Header.Get(k); v != want {
+					t.Errorf("%s = %q, want %q", k, v, want)
+				}
+			}
+			if id := msg.Header.Get("Message-ID"); !strings.HasSuffix(id, "@monit.example>") {
+				t.Errorf("Message-ID = %q", id)
+			}
+			if _, err := msg.Header.Date(); err != nil {
+				t.Errorf("Date: %v", err)
+			}
+			body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+			// crlf line ends are read back as \n by the stand-in
+			if string(body) != e.Body {
+				t.Errorf("body = %q, want %q", body, e.Body)
+			}
+		})
+	}
+}
+
+func TestSMTPMailerStartTLSMissing(t *testing.T) {
+	// server offers no STARTTLS, credentials must not go out in plain
+	srv, pool := newSMTPStandIn(t, "none", "250 ok")
+	m := srv.mailer(pool, "monit")
+	m.tlsMode = "starttls"
+
+	err := m.Send(context.Background(), Email{To: "ops@example.com", Subject: "s", Body: "b"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("Send err = %v, want STARTTLS not supported", err)
+	}
+	if got := srv.received(); got.auth != "" || got.data != nil {
+		t.Errorf("sent without tls: %+v", got)
+	}
+}
+
+func TestSMTPMailerReplies(t *testing.T) {
+	tests := []struct {
+		reply     string
+		permanent bool
+	}{
+		{"550 no such user", true},
+		{"553 mailbox name not allowed", true},
+		{"450 mailbox busy", false},
+		{"421 service not available", false},
+	}
+
+	for _, tt := range tests {
+		srv, pool := newSMTPStandIn(t, "none", tt.reply)
+		err := srv.mailer(pool, "").Send(context.Background(), Email{To: "ops@example.com", Subject: "s", Body: "b"})
+		if err == nil {
+			t.Fatalf("%s: Send err = nil", tt.reply)
+		}
+		if isPermanent(err) != tt.permanent {
+			t.Errorf("%s: permanent = %v, want %v (%v)", tt.reply, isPermanent(err), tt.permanent, err)
+		}
+	}
+}
+
+func TestSMTPMailerMessageHeaderInjection(t *testing.T) {
+	m := &SMTPMailer{from: "alerts@monit.example"}
+	tests := []Email{
+		{To: "ops@example.com\r\nBcc: evil@example.com", Subject: "s", Body: "b"},
+		{To: "ops@example.com", Subject: "DOWN: https://x.example\r\nBcc: evil@example.com", Body: "b"},
+		{To: "ops@example.com", Subject: "DOWN: https://x.example\nBcc: evil@example.com", Body: "b"},
+	}
+
+	for _, e := range tests {
+		msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(m.message(e))))
+		if err != nil {
+			t.Fatalf("%q: message: %v", e.Subject, err)
+		}
+		if bcc := msg.Header.Get("Bcc"); bcc != "" {
+			t.Errorf("%q / %q: injected Bcc %q", e.To, e.Subject, bcc)
+		}
+		body, _ := io.ReadAll(quotedprintable.NewReader(msg.Body))
+		if string(body) != "b" {
+			t.Errorf("%q: body = %q, want b", e.Subject, body)
+		}
+	}
+}
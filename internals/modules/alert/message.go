@@ -0,0 +1,139 @@
+package alert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is an alert event with its monitor, as sent to every channel of the monitor,
+// its json is stored with each delivery, so a delivery can be sent again as it was
+type Notification struct {
+	Event   AlertEvent  `json:"event"`
+	Monitor MonitorInfo `json:"monitor"`
+	At      time.Time   `json:"at"`
+}
+
+// MonitorInfo is what a notification tells of its monitor
+type MonitorInfo struct {
+	ID   uuid.UUID `json:"id"`
+	URL  string    `json:"url"`
+	Type string    `json:"type"`
+}
+
+// field is a labelled value of a notification
+type field struct {
+	label string
+	value string
+}
+
+// headline is the one line summary, subject of an email or title of a chat message
+func (n *Notification) headline() string {
+	switch n.Event.Type {
+	case AlertDown:
+		return fmt.Sprintf("DOWN: %s", n.Monitor.URL)
+	case AlertRecovered:
+		return fmt.Sprintf("RECOVERED: %s", n.Monitor.URL)
+	case AlertCertExpiring:
+		return fmt.Sprintf("Certificate expiring: %s", n.Monitor.URL)
+	case AlertCertRenewed:
+		return fmt.Sprintf("Certificate renewed: %s", n.Monitor.URL)
+	default:
+		return fmt.Sprintf("%s: %s", n.Event.Type, n.Monitor.URL)
+	}
+}
+
+func (n *Notification) intro() string {
+	switch n.Event.Type {
+	case AlertDown:
+		return "Your monitor is DOWN."
+	case AlertRecovered:
+		return "Your monitor is back UP."
+	case AlertCertExpiring:
+		return "TLS certificate of your monitor expires soon."
+	case AlertCertRenewed:
+		return "TLS certificate of your monitor was renewed."
+	default:
+		return ""
+	}
+}
+
+func (n *Notification) fields() []field {
+	ev := &n.Event
+	fields := []field{
+		{"Monitor", n.Monitor.URL},
+		{"Type", n.Monitor.Type},
+	}
+	if ev.Message != "" {
+		fields = append(fields, field{"Reason", ev.Message})
+	}
+	if inc := ev.Incident; inc != nil {
+		if !inc.StartedAt.IsZero() {
+			fields = append(fields, field{"Down since", inc.StartedAt.UTC().Format(time.RFC1123)})
+		}
+		if !inc.ResolvedAt.IsZero() {
+			fields = append(fields, field{"Resolved at", inc.ResolvedAt.UTC().Format(time.RFC1123)})
+		}
+		if inc.Duration > 0 {
+			fields = append(fields, field{"Downtime", inc.Duration.Round(time.Second).String()})
+		}
+		if ev.Type == AlertRecovered && inc.LastReason != "" {
+			fields = append(fields, field{"Last reason", inc.LastReason})
+		}
+	}
+	fields = append(fields,
+		field{"Time", n.At.UTC().Format(time.RFC1123)},
+		field{"Monitor ID", n.Monitor.ID.String()},
+	)
+	if ev.IncidentID != uuid.Nil {
+		fields = append(fields, field{"Incident ID", ev.IncidentID.String()})
+	}
+	return fields
+}
+
+// dedupKey identifies the incident n is about in pagerduty and opsgenie, DOWN and RECOVERED of an incident share it,
+// so RECOVERED resolves what DOWN opened. cert alerts of a monitor fold into one, resolved by CERT_RENEWED
+func (n *Notification) dedupKey() string {
+	ev := &n.Event
+	switch {
+	case ev.Type == AlertCertExpiring || ev.Type == AlertCertRenewed:
+		return "monit-cert-" + ev.MonitorID.String()
+	case ev.IncidentID != uuid.Nil:
+		return "monit-incident-" + ev.IncidentID.String()
+	default: // incident was not stored, DOWN and RECOVERED both go without its id
+		return "monit-monitor-" + ev.MonitorID.String()
+	}
+}
+
+// resolves reports whether n closes what an earlier alert of the same dedup key opened
+func (n *Notification) resolves() bool {
+	return n.Event.Type == AlertRecovered || n.Event.Type == AlertCertRenewed
+}
+
+// source is what the alert is about, url of monitor
+func (n *Notification) source() string {
+	if n.Monitor.URL != "" {
+		return n.Monitor.URL
+	}
+	return "monitor " + n.Monitor.ID.String()
+}
+
+// renderEmail builds the email of n to address to
+func renderEmail(n *Notification, to string) Email {
+	var b strings.Builder
+	if intro := n.intro(); intro != "" {
+		b.WriteString(intro + "\n\n")
+	}
+	for _, f := range n.fields() {
+		fmt.Fprintf(&b, "%-12s %s\n", f.label+":", f.value)
+	}
+	b.WriteString("\n-- \nSent by Monit\n")
+
+	return Email{
+		To:      to,
+		Subject: "[Monit] " + n.headline(),
+		Body:    b.String(),
+	}
+}
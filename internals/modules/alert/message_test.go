@@ -0,0 +1,36 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDedupKey(t *testing.T) {
+	monitorID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	incidentID := uuid.MustParse("00000000-0000-0000-0000-0000000000d1")
+
+	tests := []struct {
+		typ        AlertType
+		incidentID uuid.UUID
+		key        string
+		resolves   bool
+	}{
+		{AlertDown, incidentID, "monit-incident-" + incidentID.String(), false},
+		{AlertRecovered, incidentID, "monit-incident-" + incidentID.String(), true},
+		{AlertDown, uuid.Nil, "monit-monitor-" + monitorID.String(), false},
+		{AlertRecovered, uuid.Nil, "monit-monitor-" + monitorID.String(), true},
+		{AlertCertExpiring, uuid.Nil, "monit-cert-" + monitorID.String(), false},
+		{AlertCertRenewed, uuid.Nil, "monit-cert-" + monitorID.String(), true},
+	}
+
+	for _, tt := range tests {
+		n := &Notification{Event: AlertEvent{MonitorID: monitorID, IncidentID: tt.incidentID, Type: tt.typ}}
+		if got := n.dedupKey(); got != tt.key {
+			t.Errorf("%s with incident %s: dedupKey = %s, want %s", tt.typ, tt.incidentID, got, tt.key)
+		}
+		if got := n.resolves(); got != tt.resolves {
+			t.Errorf("%s: resolves = %v, want %v", tt.typ, got, tt.resolves)
+		}
+	}
+}
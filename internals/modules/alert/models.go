@@ -1,7 +1,73 @@
 package alert
 
-import "github.com/google/uuid"
+import (
+	"time"
 
+	"github.com/google/uuid"
+)
+
+type AlertType string
+
+const (
+	// monitor is down, sustained failures crossed threshold
+	AlertDown AlertType = "DOWN"
+	// monitor is back up, its alerted incident is closed
+	AlertRecovered AlertType = "RECOVERED"
+	// certificate expires soon, monitor is still up
+	AlertCertExpiring AlertType = "CERT_EXPIRING"
+	// certificate alerted as expiring was replaced by a later expiring one
+	AlertCertRenewed AlertType = "CERT_RENEWED"
+)
+
+// json of events is stored with their deliveries, to send them again
 type AlertEvent struct {
-	MonitorID uuid.UUID
-}
\ No newline at end of file
+	MonitorID  uuid.UUID        `json:"monitor_id"`
+	IncidentID uuid.UUID        `json:"incident_id"` // zero for alerts not tied to an incident (cert expiry)
+	Type       AlertType        `json:"type"`
+	Message    string           `json:"message,omitempty"`
+	Incident   *IncidentDetails `json:"incident,omitempty"` // set on DOWN and RECOVERED
+}
+
+// IncidentDetails is the incident an alert is about
+type IncidentDetails struct {
+	StartedAt  time.Time     `json:"started_at"`
+	ResolvedAt time.Time     `json:"resolved_at"` // zero while still down
+	Duration   time.Duration `json:"duration"`    // zero while still down
+	LastReason string        `json:"last_reason"` // reason of last failed check
+}
+
+// delivery status of an alert, in alerts table
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+)
+
+// Alert is a delivery of an alert event to one channel, as stored in alerts table
+type Alert struct {
+	ID           uuid.UUID
+	MonitorID    uuid.UUID
+	IncidentID   uuid.UUID
+	ChannelID    uuid.UUID
+	ChannelType  string
+	Type         AlertType
+	Status       string
+	Attempts     int
+	LastError    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	SentAt       time.Time     // zero until sent
+	Notification *Notification // nil for alerts stored before notifications were kept
+	AttemptLog   []AttemptRecord
+}
+
+// AttemptRecord is one try of a delivery, as stored in alert_attempts table
+type AttemptRecord struct {
+	Attempt    int
+	Success    bool
+	StatusCode int // 0 if there was no http response
+	Latency    time.Duration
+	Response   string // excerpt of response body
+	Error      string
+	At         time.Time
+}
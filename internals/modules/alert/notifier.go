@@ -0,0 +1,86 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/textproto"
+	"project-k/config"
+	"project-k/internals/modules/channel"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notifier makes one attempt of a delivery, what it got back is returned even if it failed
+type Notifier interface {
+	Notify(ctx context.Context, d *Delivery) (Attempt, error)
+}
+
+// Delivery is a notification on its way to one channel
+type Delivery struct {
+	ID           uuid.UUID // alert row of delivery, zero if it could not be stored
+	Config       channel.Config
+	Notification *Notification
+}
+
+// Attempt is what one try of a delivery got back
+type Attempt struct {
+	StatusCode int // 0 if there was no http response (email, network error)
+	Latency    time.Duration
+	Response   string // excerpt of response body
+}
+
+// NewNotifiers returns a notifier per channel type
+func NewNotifiers(alertConfig *config.AlertConfig, mailer Mailer) map[string]Notifier {
+	// urls of chat and webhook channels are user given, so their client only reaches public addresses
+	webhookClient := newWebhookClient(alertConfig.WebhookTimeout, alertConfig.AllowPrivateWebhooks)
+	// pagerduty and opsgenie post to api urls of config
+	client := &http.Client{
+		Timeout: alertConfig.WebhookTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return map[string]Notifier{
+		channel.TypeEmail:   &emailNotifier{mailer: mailer},
+		channel.TypeSlack:   &webhookNotifier{client: webhookClient, payload: slackPayload},
+		channel.TypeDiscord: &webhookNotifier{client: webhookClient, payload: discordPayload},
+		channel.TypeTeams:   &webhookNotifier{client: webhookClient, payload: teamsPayload},
+		channel.TypeWebhook: &webhookNotifier{client: webhookClient, payload: webhookPayload, generic: true},
+
+		channel.TypePagerDuty: newPagerDutyNotifier(client, alertConfig.PagerDutyURL, alertConfig.PagerDutyEUURL),
+		channel.TypeOpsgenie:  newOpsgenieNotifier(client, alertConfig.OpsgenieURL, alertConfig.OpsgenieEUURL),
+	}
+}
+
+// permanentError is a rejection retrying never fixes (unknown address, revoked webhook)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// isPermanent reports whether err is a permanent rejection, or a permanent (5xx) smtp reply
+func isPermanent(err error) bool {
+	var pErr *permanentError
+	if errors.As(err, &pErr) {
+		return true
+	}
+	var tpErr *textproto.Error
+	return errors.As(err, &tpErr) && tpErr.Code >= 500
+}
+
+// emailNotifier mails notification to address of channel
+type emailNotifier struct {
+	mailer Mailer
+}
+
+func (e *emailNotifier) Notify(ctx context.Context, d *Delivery) (Attempt, error) {
+	start := time.Now()
+	err := e.mailer.Send(ctx, renderEmail(d.Notification, d.Config.Email))
+	return Attempt{Latency: time.Since(start)}, err
+}
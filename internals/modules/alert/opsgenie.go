@@ -0,0 +1,115 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"project-k/internals/modules/channel"
+	"strings"
+)
+
+// limits of an opsgenie alert
+const (
+	opsgenieMessageLen     = 130
+	opsgenieDescriptionLen = 15000
+)
+
+// opsgenieNotifier creates an opsgenie alert on DOWN (and CERT_EXPIRING) and closes it on RECOVERED (and CERT_RENEWED),
+// alert alias is the dedup key, so both refer to the same alert
+type opsgenieNotifier struct {
+	client    *http.Client
+	baseURL   string
+	euBaseURL string // for eu accounts
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"` // P1 (critical) to P5
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+func newOpsgenieNotifier(client *http.Client, baseURL, euBaseURL string) *opsgenieNotifier {
+	return &opsgenieNotifier{
+		client:    client,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		euBaseURL: strings.TrimRight(euBaseURL, "/"),
+	}
+}
+
+func (o *opsgenieNotifier) Notify(ctx context.Context, d *Delivery) (Attempt, error) {
+	n := d.Notification
+	alias := n.dedupKey()
+	baseURL := o.baseURL
+	if d.Config.Region == channel.RegionEU {
+		baseURL = o.euBaseURL
+	}
+
+	var endpoint string
+	var payload any
+	if n.resolves() {
+		endpoint = baseURL + "/v2/alerts/" + url.PathEscape(alias) + "/close?identifierType=alias"
+		payload = opsgenieClose{Source: "Monit", Note: n.intro()}
+	} else {
+		var desc strings.Builder
+		if intro := n.intro(); intro != "" {
+			desc.WriteString(intro + "\n\n")
+		}
+		details := make(map[string]string)
+		for _, f := range n.fields() {
+			desc.WriteString(f.label + ": " + f.value + "\n")
+			details[f.label] = f.value
+		}
+
+		priority := "P1"
+		if n.Event.Type == AlertCertExpiring {
+			priority = "P3"
+		}
+
+		payload = opsgenieAlert{
+			Message:     truncate(n.headline(), opsgenieMessageLen),
+			Alias:       alias,
+			Description: truncate(desc.String(), opsgenieDescriptionLen),
+			Details:     details,
+			Entity:      n.source(),
+			Source:      "Monit",
+			Priority:    priority,
+			Tags:        []string{"monit", strings.ToLower(string(n.Event.Type))},
+		}
+		endpoint = baseURL + "/v2/alerts"
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Attempt{}, &permanentError{err: err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Attempt{}, &permanentError{err: errors.New("invalid opsgenie url")}
+	}
+	req.Header.Set("Authorization", "GenieKey "+d.Config.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Monit-Alerts/1.0")
+
+	return post(o.client, req, "opsgenie")
+}
+
+// truncate cuts s to at most n bytes, on a rune boundary
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,125 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"project-k/internals/modules/channel"
+	"strings"
+	"testing"
+)
+
+func TestOpsgenieNotify(t *testing.T) {
+	api := newAPIStandIn(t)
+	o := newOpsgenieNotifier(http.DefaultClient, api.URL+"/us/", api.URL+"/eu")
+	const alias = "monit-incident-00000000-0000-0000-0000-0000000000d1"
+	const certAlias = "monit-cert-00000000-0000-0000-0000-000000000001"
+
+	tests := []struct {
+		name     string
+		typ      AlertType
+		region   string
+		path     string
+		alias    string // of created alert, empty on close
+		priority string
+	}{
+		{"create on down", AlertDown, "", "/us/v2/alerts", alias, "P1"},
+		{"close on recovered", AlertRecovered, channel.RegionUS, "/us/v2/alerts/" + alias + "/close?identifierType=alias", "", ""},
+		{"eu account", AlertDown, channel.RegionEU, "/eu/v2/alerts", alias, "P1"},
+		{"eu close", AlertRecovered, channel.RegionEU, "/eu/v2/alerts/" + alias + "/close?identifierType=alias", "", ""},
+		{"cert expiring is lower priority", AlertCertExpiring, "", "/us/v2/alerts", certAlias, "P3"},
+		{"cert renewed closes it", AlertCertRenewed, "", "/us/v2/alerts/" + certAlias + "/close?identifierType=alias", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Delivery{
+				Config:       channel.Config{APIKey: "0p5-k3y-1234", Region: tt.region},
+				Notification: incidentNotification(tt.typ),
+			}
+			at, err := o.Notify(context.Background(), d)
+			if err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+			if at.StatusCode != http.StatusAccepted {
+				t.Errorf("status = %d, want 202", at.StatusCode)
+			}
+
+			req := api.last(t)
+			if req.path != tt.path {
+				t.Errorf("path = %s, want %s", req.path, tt.path)
+			}
+			if got := req.header.Get("Authorization"); got != "GenieKey 0p5-k3y-1234" {
+				t.Errorf("authorization = %q", got)
+			}
+
+			if tt.alias == "" {
+				var c opsgenieClose
+				if err := json.Unmarshal(req.body, &c); err != nil || c.Source != "Monit" || c.Note == "" {
+					t.Errorf("close body = %s (%v)", req.body, err)
+				}
+				return
+			}
+			var a opsgenieAlert
+			if err := json.Unmarshal(req.body, &a); err != nil {
+				t.Fatalf("body %s: %v", req.body, err)
+			}
+			if a.Alias != tt.alias || a.Priority != tt.priority || a.Entity != "https://example.com" || a.Source != "Monit" {
+				t.Errorf("alert = %+v", a)
+			}
+			if !strings.Contains(a.Description, "Monitor ID: 00000000-0000-0000-0000-000000000001") {
+				t.Errorf("description = %q", a.Description)
+			}
+		})
+	}
+}
+
+func TestOpsgenieNotifyErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusUnauthorized, true}, // revoked api key
+		{http.StatusUnprocessableEntity, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		api := newAPIStandIn(t)
+		api.status = tt.status
+		o := newOpsgenieNotifier(http.DefaultClient, api.URL, api.URL)
+
+		at, err := o.Notify(context.Background(), &Delivery{
+			Config:       channel.Config{APIKey: "0p5-k3y-1234"},
+			Notification: incidentNotification(AlertDown),
+		})
+		if err == nil {
+			t.Fatalf("%d: Notify err = nil", tt.status)
+		}
+		if isPermanent(err) != tt.permanent {
+			t.Errorf("%d: permanent = %v, want %v (%v)", tt.status, isPermanent(err), tt.permanent, err)
+		}
+		if at.StatusCode != tt.status {
+			t.Errorf("%d: attempt status = %d", tt.status, at.StatusCode)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"cut here", 3, "cut"},
+		{"héllo", 2, "h"}, // é is 2 bytes, half of it is dropped
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-k/internals/modules/channel"
+	"strings"
+	"time"
+)
+
+// max length of summary of a pagerduty event
+const pagerDutySummaryLen = 1024
+
+// pagerDutyNotifier triggers a pagerduty incident on DOWN (and CERT_EXPIRING) and resolves it on RECOVERED (and CERT_RENEWED),
+// through events api v2 of the service routing key of channel belongs to
+type pagerDutyNotifier struct {
+	client *http.Client
+	url    string // of enqueue endpoint
+	euURL  string // of enqueue endpoint, for eu accounts
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // trigger or resolve
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"` // only on trigger
+	Client      string            `json:"client,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"` // critical, error, warning or info
+	Timestamp     time.Time         `json:"timestamp"`
+	Component     string            `json:"component,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+func newPagerDutyNotifier(client *http.Client, baseURL, euBaseURL string) *pagerDutyNotifier {
+	return &pagerDutyNotifier{
+		client: client,
+		url:    strings.TrimRight(baseURL, "/") + "/v2/enqueue",
+		euURL:  strings.TrimRight(euBaseURL, "/") + "/v2/enqueue",
+	}
+}
+
+func (p *pagerDutyNotifier) Notify(ctx context.Context, d *Delivery) (Attempt, error) {
+	n := d.Notification
+	ev := pagerDutyEvent{
+		RoutingKey:  d.Config.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    n.dedupKey(),
+		Client:      "Monit",
+	}
+
+	if n.resolves() {
+		ev.EventAction = "resolve"
+	} else {
+		summary := n.headline()
+		if n.Event.Message != "" {
+			summary += " - " + n.Event.Message
+		}
+
+		severity := "critical"
+		if n.Event.Type == AlertCertExpiring {
+			severity = "warning"
+		}
+
+		details := make(map[string]string)
+		for _, f := range n.fields() {
+			details[f.label] = f.value
+		}
+
+		ev.Payload = &pagerDutyPayload{
+			Summary:       truncate(summary, pagerDutySummaryLen),
+			Source:        n.source(),
+			Severity:      severity,
+			Timestamp:     n.At.UTC(),
+			Component:     n.Monitor.Type,
+			Class:         string(n.Event.Type),
+			CustomDetails: details,
+		}
+	}
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return Attempt{}, &permanentError{err: err}
+	}
+	endpoint := p.url
+	if d.Config.Region == channel.RegionEU {
+		endpoint = p.euURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Attempt{}, &permanentError{err: errors.New("invalid pagerduty url")}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Monit-Alerts/1.0")
+
+	return post(p.client, req, "pagerduty")
+}
@@ -0,0 +1,167 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"project-k/internals/modules/channel"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiStandIn is a local pagerduty / opsgenie, us api under /us and eu api under /eu,
+// it records requests and answers them with status
+type apiStandIn struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []apiRequest
+}
+
+type apiRequest struct {
+	path   string // with query
+	header http.Header
+	body   []byte
+}
+
+func newAPIStandIn(t *testing.T) *apiStandIn {
+	s := &apiStandIn{status: http.StatusAccepted}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, apiRequest{path: r.URL.RequestURI(), header: r.Header.Clone(), body: body})
+		status := s.status
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		io.WriteString(w, `{"status":"stand-in"}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *apiStandIn) last(t *testing.T) apiRequest {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		t.Fatal("no request was sent")
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+func incidentNotification(typ AlertType) *Notification {
+	n := testNotification()
+	n.Event.Type = typ
+	n.Event.IncidentID = uuid.MustParse("00000000-0000-0000-0000-0000000000d1")
+	return n
+}
+
+func TestPagerDutyNotify(t *testing.T) {
+	api := newAPIStandIn(t)
+	p := newPagerDutyNotifier(http.DefaultClient, api.URL+"/us/", api.URL+"/eu")
+	const dedup = "monit-incident-00000000-0000-0000-0000-0000000000d1"
+
+	tests := []struct {
+		name     string
+		typ      AlertType
+		region   string
+		path     string
+		action   string
+		severity string // empty when event has no payload
+		dedupKey string
+	}{
+		{"trigger on down", AlertDown, "", "/us/v2/enqueue", "trigger", "critical", dedup},
+		{"resolve on recovered", AlertRecovered, channel.RegionUS, "/us/v2/enqueue", "resolve", "", dedup},
+		{"eu account", AlertDown, channel.RegionEU, "/eu/v2/enqueue", "trigger", "critical", dedup},
+		{"eu resolve", AlertRecovered, channel.RegionEU, "/eu/v2/enqueue", "resolve", "", dedup},
+		{"cert expiring is a warning", AlertCertExpiring, "", "/us/v2/enqueue", "trigger", "warning", "monit-cert-00000000-0000-0000-0000-000000000001"},
+		{"cert renewed resolves it", AlertCertRenewed, "", "/us/v2/enqueue", "resolve", "", "monit-cert-00000000-0000-0000-0000-000000000001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Delivery{
+				Config:       channel.Config{RoutingKey: "R0UT1NGK3Y", Region: tt.region},
+				Notification: incidentNotification(tt.typ),
+			}
+			at, err := p.Notify(context.Background(), d)
+			if err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+			if at.StatusCode != http.StatusAccepted || at.Response != `{"status":"stand-in"}` {
+				t.Errorf("attempt = %+v, want 202 with response", at)
+			}
+
+			req := api.last(t)
+			if req.path != tt.path {
+				t.Errorf("path = %s, want %s", req.path, tt.path)
+			}
+			if ct := req.header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("content type = %s", ct)
+			}
+
+			var ev pagerDutyEvent
+			if err := json.Unmarshal(req.body, &ev); err != nil {
+				t.Fatalf("body %s: %v", req.body, err)
+			}
+			if ev.RoutingKey != "R0UT1NGK3Y" || ev.EventAction != tt.action || ev.DedupKey != tt.dedupKey {
+				t.Errorf("event = %s %s %s, want R0UT1NGK3Y %s %s", ev.RoutingKey, ev.EventAction, ev.DedupKey, tt.action, tt.dedupKey)
+			}
+			if tt.severity == "" {
+				if ev.Payload != nil {
+					t.Errorf("resolve carries payload %+v", ev.Payload)
+				}
+				return
+			}
+			if ev.Payload == nil {
+				t.Fatal("trigger without payload")
+			}
+			pl := ev.Payload
+			if pl.Severity != tt.severity || pl.Source != "https://example.com" || pl.Class != string(tt.typ) ||
+				!pl.Timestamp.Equal(time.Unix(1700000000, 0)) || pl.Summary == "" {
+				t.Errorf("payload = %+v", pl)
+			}
+			if pl.CustomDetails["Monitor ID"] != "00000000-0000-0000-0000-000000000001" {
+				t.Errorf("custom details = %v", pl.CustomDetails)
+			}
+		})
+	}
+}
+
+func TestPagerDutyNotifyErrors(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusBadRequest, true}, // invalid event or routing key
+		{http.StatusForbidden, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, false},
+	}
+
+	for _, tt := range tests {
+		api := newAPIStandIn(t)
+		api.status = tt.status
+		p := newPagerDutyNotifier(http.DefaultClient, api.URL, api.URL)
+
+		at, err := p.Notify(context.Background(), &Delivery{
+			Config:       channel.Config{RoutingKey: "R0UT1NGK3Y"},
+			Notification: incidentNotification(AlertDown),
+		})
+		if err == nil {
+			t.Fatalf("%d: Notify err = nil", tt.status)
+		}
+		if isPermanent(err) != tt.permanent {
+			t.Errorf("%d: permanent = %v, want %v (%v)", tt.status, isPermanent(err), tt.permanent, err)
+		}
+		if at.StatusCode != tt.status {
+			t.Errorf("%d: attempt status = %d", tt.status, at.StatusCode)
+		}
+	}
+}
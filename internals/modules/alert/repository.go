@@ -0,0 +1,182 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"project-k/pkg/apperror"
+	"project-k/pkg/db"
+	"project-k/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// Create stores a pending delivery of n to target t, returns its id
+func (r *Repository) Create(ctx context.Context, n *Notification, t target) (uuid.UUID, error) {
+	const op string = "repo.alert.create"
+
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return uuid.UUID{}, apperror.New(apperror.Internal, op, err)
+	}
+
+	ev := &n.Event
+	id, err := r.querier.CreateAlert(ctx, db.CreateAlertParams{
+		IncidentID:  pgtype.UUID{Bytes: ev.IncidentID, Valid: ev.IncidentID != uuid.Nil},
+		MonitorID:   utils.ToPgUUID(ev.MonitorID),
+		AlertType:   string(ev.Type),
+		AlertEmail:  utils.ToPgText(t.config.Email),
+		ChannelID:   pgtype.UUID{Bytes: t.channelID, Valid: t.channelID != uuid.Nil},
+		ChannelType: t.typ,
+		Payload:     payload,
+	})
+	if err != nil {
+		return uuid.UUID{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return utils.FromPgUUID(id), nil
+}
+
+// UpdateStatus records outcome of delivery of alert id, lastErr is empty once sent
+func (r *Repository) UpdateStatus(ctx context.Context, id uuid.UUID, status string, attempts int, lastErr string) error {
+	const op string = "repo.alert.update_status"
+
+	_, err := r.querier.UpdateAlertStatus(ctx, db.UpdateAlertStatusParams{
+		ID:        utils.ToPgUUID(id),
+		Status:    status,
+		Attempts:  int32(attempts),
+		LastError: utils.ToPgText(lastErr),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return nil
+}
+
+// MarkPending marks alert id pending before it is sent again, returns false if it is being delivered now
+func (r *Repository) MarkPending(ctx context.Context, id uuid.UUID) (bool, error) {
+	const op string = "repo.alert.mark_pending"
+
+	rows, err := r.querier.MarkAlertPending(ctx, utils.ToPgUUID(id))
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return rows > 0, nil
+}
+
+// CreateAttempt records attempt n of delivery of alert id, errMsg is empty if it succeeded
+func (r *Repository) CreateAttempt(ctx context.Context, id uuid.UUID, n int, at Attempt, errMsg string) error {
+	const op string = "repo.alert.create_attempt"
+
+	err := r.querier.CreateAlertAttempt(ctx, db.CreateAlertAttemptParams{
+		AlertID:         utils.ToPgUUID(id),
+		Attempt:         int32(n),
+		Success:         errMsg == "",
+		StatusCode:      pgtype.Int4{Int32: int32(at.StatusCode), Valid: at.StatusCode != 0},
+		LatencyMs:       int32(at.Latency.Milliseconds()),
+		ResponseExcerpt: utils.ToPgText(at.Response),
+		Error:           utils.ToPgText(errMsg),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return nil
+}
+
+// GetForChannel returns alert id delivered to channel of user, without its attempts
+func (r *Repository) GetForChannel(ctx context.Context, userID, channelID, id uuid.UUID) (Alert, error) {
+	const op string = "repo.alert.get_for_channel"
+
+	row, err := r.querier.GetChannelAlert(ctx, db.GetChannelAlertParams{
+		ID:        utils.ToPgUUID(id),
+		ChannelID: utils.ToPgUUID(channelID),
+		UserID:    utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		return Alert{}, utils.WrapRepoError(op, err, true, r.logger)
+	}
+	return toAlert(&row), nil
+}
+
+// ListForChannel returns alerts delivered to a channel, newest first, with their attempts
+func (r *Repository) ListForChannel(ctx context.Context, channelID uuid.UUID, limit, offset int32) ([]Alert, error) {
+	const op string = "repo.alert.list_for_channel"
+
+	rows, err := r.querier.ListChannelAlerts(ctx, db.ListChannelAlertsParams{
+		ChannelID: utils.ToPgUUID(channelID),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return []Alert{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	if len(rows) == 0 {
+		return []Alert{}, nil
+	}
+
+	alerts := make([]Alert, 0, len(rows))
+	ids := make([]pgtype.UUID, 0, len(rows))
+	index := make(map[uuid.UUID]int, len(rows))
+	for i := range rows {
+		a := toAlert(&rows[i])
+		index[a.ID] = len(alerts)
+		alerts = append(alerts, a)
+		ids = append(ids, rows[i].ID)
+	}
+
+	attempts, err := r.querier.ListAlertAttempts(ctx, ids)
+	if err != nil {
+		return []Alert{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	for i := range attempts {
+		at := &attempts[i]
+		a := &alerts[index[utils.FromPgUUID(at.AlertID)]]
+		a.AttemptLog = append(a.AttemptLog, AttemptRecord{
+			Attempt:    int(at.Attempt),
+			Success:    at.Success,
+			StatusCode: int(utils.FromPgInt32(at.StatusCode)),
+			Latency:    time.Duration(at.LatencyMs) * time.Millisecond,
+			Response:   utils.FromPgText(at.ResponseExcerpt),
+			Error:      utils.FromPgText(at.Error),
+			At:         utils.FromPgTimestamptz(at.CreatedAt),
+		})
+	}
+	return alerts, nil
+}
+
+func toAlert(row *db.Alert) Alert {
+	a := Alert{
+		ID:          utils.FromPgUUID(row.ID),
+		MonitorID:   utils.FromPgUUID(row.MonitorID),
+		IncidentID:  utils.FromPgUUID(row.IncidentID),
+		ChannelID:   utils.FromPgUUID(row.ChannelID),
+		ChannelType: row.ChannelType,
+		Type:        AlertType(row.AlertType),
+		Status:      row.Status,
+		Attempts:    int(row.Attempts),
+		LastError:   utils.FromPgText(row.LastError),
+		CreatedAt:   utils.FromPgTimestamptz(row.CreatedAt),
+		UpdatedAt:   utils.FromPgTimestamptz(row.UpdatedAt),
+		SentAt:      utils.FromPgTimestamptz(row.SentAt),
+	}
+	if len(row.Payload) > 0 {
+		var n Notification
+		if err := json.Unmarshal(row.Payload, &n); err == nil {
+			a.Notification = &n
+		}
+	}
+	return a
+}
@@ -0,0 +1,26 @@
+package alert
+
+import "github.com/go-chi/chi/v5"
+
+// DeliveryRoutes are mounted under /channels/{channelID}/deliveries
+func DeliveryRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.GetDeliveries)
+	r.Post("/{deliveryID}/redeliver", h.Redeliver)
+
+	return r
+}
+
+/*
+- GET: /channels/{channelID}/deliveries?offset={}&limit={} -> alerts delivered to a channel, newest first, with every attempt
+	req auth : true
+	body : nil
+	resp : GetDeliveriesResponse
+
+- POST: /channels/{channelID}/deliveries/{deliveryID}/redeliver -> send a delivery again, as it was first sent,
+  to current config of channel (freshly signed for a webhook), it runs in background with retries
+	req auth : true
+	body : nil
+	resp : RedeliverResponse
+*/
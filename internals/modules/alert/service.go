@@ -1,30 +1,91 @@
 package alert
 
 import (
-	"log"
+	"context"
+	"math/rand"
 	"project-k/config"
+	"project-k/internals/modules/channel"
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/apperror"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
+// max time a DB read or write of an alert may take, it is not bound to app ctx, so alerts draining on shutdown are still recorded
+const alertWriteTimeout = 5 * time.Second
+
+type MonitorService interface {
+	LoadMonitor(context.Context, uuid.UUID) (monitor.Monitor, error)
+}
+
+// ChannelService returns enabled channels linked to a monitor, and a channel of a user (NotFound if not owned),
+// their config is sealed until opened to send
+type ChannelService interface {
+	ChannelsOfMonitor(context.Context, uuid.UUID) ([]channel.Channel, error)
+	GetChannel(context.Context, uuid.UUID, uuid.UUID) (channel.Channel, error)
+	OpenConfig(channel.Config) (channel.Config, error)
+}
+
+// alertStore keeps deliveries and their attempts, see Repository
+type alertStore interface {
+	Create(ctx context.Context, n *Notification, t target) (uuid.UUID, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string, attempts int, lastErr string) error
+	MarkPending(ctx context.Context, id uuid.UUID) (bool, error)
+	CreateAttempt(ctx context.Context, id uuid.UUID, n int, at Attempt, errMsg string) error
+	GetForChannel(ctx context.Context, userID, channelID, id uuid.UUID) (Alert, error)
+	ListForChannel(ctx context.Context, channelID uuid.UUID, limit, offset int32) ([]Alert, error)
+}
+
+// target is one place an alert is delivered to, a channel of monitor, or its alert email
+type target struct {
+	channelID uuid.UUID // zero for alert email of monitor
+	typ       string
+	config    channel.Config
+}
+
 type AlertService struct {
 	// lifecycle
-	workerCount int
-	workerWG    sync.WaitGroup
+	workerCount  int
+	workerWG     sync.WaitGroup
+	redeliverWG  sync.WaitGroup // manual redeliveries, they run outside workers
+	maxAttempts  int
+	retryBackoff time.Duration
 
 	// channels
 	alertChan chan AlertEvent
 
+	// services
+	repo       alertStore
+	notifiers  map[string]Notifier // by channel type
+	monitorSvc MonitorService
+	channelSvc ChannelService
+
 	// misc
 	logger *zerolog.Logger
 }
 
-func NewAlertService(alertConfig *config.AlertConfig, alertChan chan AlertEvent, logger *zerolog.Logger) *AlertService {
+func NewAlertService(
+	alertConfig *config.AlertConfig,
+	alertChan chan AlertEvent,
+	repo alertStore,
+	notifiers map[string]Notifier,
+	monitorSvc MonitorService,
+	channelSvc ChannelService,
+	logger *zerolog.Logger,
+) *AlertService {
 	return &AlertService{
-		workerCount: alertConfig.WorkerCount,
-		alertChan:   alertChan,
-		logger:      logger,
+		workerCount:  alertConfig.WorkerCount,
+		maxAttempts:  alertConfig.MaxAttempts,
+		retryBackoff: alertConfig.RetryBackoff,
+		alertChan:    alertChan,
+		repo:         repo,
+		notifiers:    notifiers,
+		monitorSvc:   monitorSvc,
+		channelSvc:   channelSvc,
+		logger:       logger,
 	}
 }
 
@@ -43,12 +104,233 @@ func (s *AlertService) handleAlerts() {
 	defer s.workerWG.Done()
 
 	for alert := range s.alertChan {
-		s.logger.Info().Msg("Alert Recieved")
-		log.Print(alert.MonitorID)
+		s.logger.Info().Str("alert_type", string(alert.Type)).Str("monitor_id", alert.MonitorID.String()).Msg("Alert Recieved")
+		s.handleAlert(alert)
+	}
+}
+
+// handleAlert fans ev out to every enabled channel of its monitor, and to its alert email,
+// each delivery is tracked in alerts table as pending -> sent / failed
+func (s *AlertService) handleAlert(ev AlertEvent) {
+	log := s.logger.With().Str("alert_type", string(ev.Type)).Str("monitor_id", ev.MonitorID.String()).Logger()
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertWriteTimeout)
+	m, err := s.monitorSvc.LoadMonitor(ctx, ev.MonitorID)
+	cancel()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load monitor of alert, alert dropped")
+		return
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), alertWriteTimeout)
+	channels, err := s.channelSvc.ChannelsOfMonitor(ctx, ev.MonitorID)
+	cancel()
+	if err != nil {
+		// alert email does not need DB, it still goes out
+		log.Error().Err(err).Msg("failed to load channels of monitor, sending to alert email only")
+	}
+
+	targets := targetsOf(m, channels)
+	if len(targets) == 0 {
+		log.Info().Msg("monitor has no alert email or channel, alert dropped")
+		return
+	}
+
+	n := &Notification{
+		Event:   ev,
+		Monitor: MonitorInfo{ID: m.ID, URL: m.Url, Type: m.Type},
+		At:      time.Now(),
+	}
+
+	// a slow or retrying channel must not hold back the others
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(n, t, log)
+		}()
+	}
+	wg.Wait()
+}
+
+// targetsOf returns channels, plus alert email of m unless an email channel already has that address
+func targetsOf(m monitor.Monitor, channels []channel.Channel) []target {
+	targets := make([]target, 0, len(channels)+1)
+	emailCovered := false
+	for _, c := range channels {
+		targets = append(targets, target{channelID: c.ID, typ: c.Type, config: c.Config})
+		if c.Type == channel.TypeEmail && c.Config.Email == m.AlertEmail {
+			emailCovered = true
+		}
+	}
+	if m.AlertEmail != "" && !emailCovered {
+		targets = append(targets, target{typ: channel.TypeEmail, config: channel.Config{Email: m.AlertEmail}})
+	}
+	return targets
+}
+
+// deliver sends n to t with retries, and records every attempt and the outcome
+func (s *AlertService) deliver(n *Notification, t target, log zerolog.Logger) {
+	log = log.With().Str("channel_type", t.typ).Str("channel_id", t.channelID.String()).Logger()
+
+	notifier, ok := s.notifiers[t.typ]
+	if !ok {
+		log.Error().Msg("no notifier for channel type, alert dropped")
+		return
+	}
+
+	// row is best effort, a DB failure must not keep user from knowing the monitor is down
+	ctx, cancel := context.WithTimeout(context.Background(), alertWriteTimeout)
+	alertID, err := s.repo.Create(ctx, n, t)
+	cancel()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to store alert, sending it untracked")
+	}
+
+	s.run(notifier, &Delivery{ID: alertID, Config: t.config, Notification: n}, 0, log)
+}
+
+// run sends d with retries, numbering attempts after prior ones (of earlier runs), and records them and the outcome
+func (s *AlertService) run(notifier Notifier, d *Delivery, prior int, log zerolog.Logger) {
+	// secrets of channel are opened only for the send, never kept or logged
+	var attempts int
+	cfg, err := s.channelSvc.OpenConfig(d.Config)
+	if err == nil {
+		open := *d
+		open.Config = cfg
+		attempts, err = s.send(func(attempt int) error {
+			at, err := notifier.Notify(context.Background(), &open)
+			s.recordAttempt(d.ID, prior+attempt, at, err, log)
+			return err
+		}, log)
+	}
+
+	status, lastErr := StatusSent, ""
+	if err != nil {
+		status, lastErr = StatusFailed, err.Error()
+		log.Error().Err(err).Int("attempts", attempts).Msg("failed to send alert")
+	} else {
+		log.Info().Int("attempts", attempts).Msg("alert sent")
 	}
+
+	if d.ID == uuid.Nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), alertWriteTimeout)
+	defer cancel()
+	if err := s.repo.UpdateStatus(ctx, d.ID, status, prior+attempts, lastErr); err != nil {
+		log.Error().Err(err).Str("alert_id", d.ID.String()).Msg("failed to update alert status")
+	}
+}
+
+func (s *AlertService) recordAttempt(alertID uuid.UUID, n int, at Attempt, err error, log zerolog.Logger) {
+	if alertID == uuid.Nil {
+		return
+	}
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertWriteTimeout)
+	defer cancel()
+	if err := s.repo.CreateAttempt(ctx, alertID, n, at, errMsg); err != nil {
+		log.Error().Err(err).Str("alert_id", alertID.String()).Int("attempt", n).Msg("failed to record alert attempt")
+	}
+}
+
+// send tries notify up to maxAttempts times, backoff doubles after each failed attempt,
+// permanent rejections are not retried. returns attempts made and last error
+func (s *AlertService) send(notify func(attempt int) error, log zerolog.Logger) (int, error) {
+	backoff := s.retryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = notify(attempt); err == nil {
+			return attempt, nil
+		}
+		if attempt >= s.maxAttempts || isPermanent(err) {
+			return attempt, err
+		}
+
+		// half of backoff is random, so retries of alerts failed together do not hit a receiver at once
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		log.Warn().Err(err).Int("attempt", attempt).Dur("backoff", wait).Msg("alert delivery failed, retrying")
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
+
+// ListDeliveries returns alerts delivered to channel of user, newest first, with their attempts
+func (s *AlertService) ListDeliveries(ctx context.Context, userID, channelID uuid.UUID, limit, offset int32) ([]Alert, error) {
+	if _, err := s.channelSvc.GetChannel(ctx, userID, channelID); err != nil {
+		return []Alert{}, err
+	}
+	return s.repo.ListForChannel(ctx, channelID, limit, offset)
+}
+
+// Redeliver sends alert id of channel of user again, as it was first sent, to current config of channel.
+// it runs in background with retries, returned alert is pending
+func (s *AlertService) Redeliver(ctx context.Context, userID, channelID, id uuid.UUID) (Alert, error) {
+	const op string = "service.alert.redeliver"
+
+	c, err := s.channelSvc.GetChannel(ctx, userID, channelID)
+	if err != nil {
+		return Alert{}, err
+	}
+	a, err := s.repo.GetForChannel(ctx, userID, channelID, id)
+	if err != nil {
+		return Alert{}, err
+	}
+	if a.Notification == nil {
+		return Alert{}, &apperror.Error{
+			Kind:    apperror.InvalidInput,
+			Op:      op,
+			Message: "delivery was stored without its notification, it can not be sent again",
+		}
+	}
+	notifier, ok := s.notifiers[c.Type]
+	if !ok {
+		return Alert{}, &apperror.Error{
+			Kind:    apperror.InvalidInput,
+			Op:      op,
+			Message: "channel type can not be delivered to",
+		}
+	}
+
+	marked, err := s.repo.MarkPending(ctx, a.ID)
+	if err != nil {
+		return Alert{}, err
+	}
+	if !marked {
+		return Alert{}, &apperror.Error{
+			Kind:    apperror.Conflict,
+			Op:      op,
+			Message: "delivery is in progress",
+		}
+	}
+	a.Status = StatusPending
+
+	log := s.logger.With().
+		Str("alert_type", string(a.Type)).
+		Str("monitor_id", a.MonitorID.String()).
+		Str("channel_type", c.Type).
+		Str("channel_id", c.ID.String()).
+		Str("alert_id", a.ID.String()).
+		Logger()
+	log.Info().Msg("redelivering alert")
+
+	d := &Delivery{ID: a.ID, Config: c.Config, Notification: a.Notification}
+	s.redeliverWG.Add(1)
+	go func() {
+		defer s.redeliverWG.Done()
+		s.run(notifier, d, a.Attempts, log)
+	}()
+	return a, nil
 }
 
-// WorkerClosingWait waits for alert workers to complete
+// WorkerClosingWait waits for alert workers, and redeliveries, to complete
 func (s *AlertService) WorkerClosingWait() {
 	s.workerWG.Wait()
+	s.redeliverWG.Wait()
 }
@@ -0,0 +1,305 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"project-k/internals/modules/channel"
+	"project-k/pkg/apperror"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+var (
+	testUserID     = uuid.MustParse("00000000-0000-0000-0000-0000000000aa")
+	testChannelID  = uuid.MustParse("00000000-0000-0000-0000-0000000000bb")
+	testDeliveryID = uuid.MustParse("00000000-0000-0000-0000-0000000000cc")
+)
+
+// fakeStore is an in memory alertStore holding one delivery
+type fakeStore struct {
+	mu       sync.Mutex
+	alert    Alert
+	getErr   error
+	busy     bool // delivery is already pending
+	attempts []int
+	statuses []string
+	total    int
+	lastErr  string
+}
+
+func (f *fakeStore) Create(context.Context, *Notification, target) (uuid.UUID, error) {
+	return testDeliveryID, nil
+}
+
+func (f *fakeStore) UpdateStatus(_ context.Context, id uuid.UUID, status string, attempts int, lastErr string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses = append(f.statuses, status)
+	f.total, f.lastErr = attempts, lastErr
+	return nil
+}
+
+func (f *fakeStore) MarkPending(context.Context, uuid.UUID) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.busy {
+		return false, nil
+	}
+	f.busy = true
+	return true, nil
+}
+
+func (f *fakeStore) CreateAttempt(_ context.Context, _ uuid.UUID, n int, _ Attempt, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts = append(f.attempts, n)
+	return nil
+}
+
+func (f *fakeStore) GetForChannel(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) (Alert, error) {
+	return f.alert, f.getErr
+}
+
+func (f *fakeStore) ListForChannel(context.Context, uuid.UUID, int32, int32) ([]Alert, error) {
+	return []Alert{f.alert}, nil
+}
+
+// fakeChannels owns one channel of testUserID, its config is "sealed" by a sealed: prefix
+type fakeChannels struct {
+	ch channel.Channel
+}
+
+func (f *fakeChannels) OpenConfig(cfg channel.Config) (channel.Config, error) {
+	for _, v := range []*string{&cfg.URL, &cfg.Secret} {
+		plain, ok := strings.CutPrefix(*v, "sealed:")
+		if !ok && *v != "" {
+			return channel.Config{}, errors.New("config is not sealed")
+		}
+		*v = plain
+	}
+	return cfg, nil
+}
+
+func (f *fakeChannels) ChannelsOfMonitor(context.Context, uuid.UUID) ([]channel.Channel, error) {
+	return []channel.Channel{f.ch}, nil
+}
+
+func (f *fakeChannels) GetChannel(_ context.Context, userID, channelID uuid.UUID) (channel.Channel, error) {
+	if userID != testUserID || channelID != f.ch.ID {
+		return channel.Channel{}, &apperror.Error{Kind: apperror.NotFound, Message: "resource not found"}
+	}
+	return f.ch, nil
+}
+
+// fakeNotifier returns its errors in order, then succeeds
+type fakeNotifier struct {
+	mu         sync.Mutex
+	errs       []error
+	deliveries []Delivery
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, d *Delivery) (Attempt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deliveries = append(f.deliveries, *d)
+	if i := len(f.deliveries) - 1; i < len(f.errs) {
+		return Attempt{StatusCode: http.StatusBadGateway}, f.errs[i]
+	}
+	return Attempt{StatusCode: http.StatusOK}, nil
+}
+
+func newTestAlertService(store *fakeStore, notifier *fakeNotifier) *AlertService {
+	nop := zerolog.Nop()
+	return &AlertService{
+		maxAttempts:  3,
+		retryBackoff: time.Millisecond,
+		repo:         store,
+		notifiers:    map[string]Notifier{channel.TypeWebhook: notifier},
+		channelSvc: &fakeChannels{ch: channel.Channel{
+			ID:     testChannelID,
+			UserID: testUserID,
+			Type:   channel.TypeWebhook,
+			// current config, url changed since the first delivery
+			Config: channel.Config{URL: "sealed:https://hooks.example.com/new", Secret: "sealed:whsec_new"},
+		}},
+		logger: &nop,
+	}
+}
+
+func failedAlert() Alert {
+	return Alert{
+		ID:           testDeliveryID,
+		ChannelID:    testChannelID,
+		ChannelType:  channel.TypeWebhook,
+		Type:         AlertDown,
+		Status:       StatusFailed,
+		Attempts:     3,
+		LastError:    "webhook responded 502 Bad Gateway",
+		Notification: testNotification(),
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	store := &fakeStore{alert: failedAlert()}
+	notifier := &fakeNotifier{errs: []error{errors.New("webhook responded 502 Bad Gateway")}}
+	s := newTestAlertService(store, notifier)
+
+	a, err := s.Redeliver(context.Background(), testUserID, testChannelID, testDeliveryID)
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if a.Status != StatusPending {
+		t.Errorf("returned status = %s, want pending", a.Status)
+	}
+	s.WorkerClosingWait()
+
+	// sent as it was first sent, to current config of channel
+	if len(notifier.deliveries) != 2 {
+		t.Fatalf("notify calls = %d, want 2 (one retry)", len(notifier.deliveries))
+	}
+	d := notifier.deliveries[0]
+	if d.ID != testDeliveryID || d.Notification != store.alert.Notification {
+		t.Errorf("delivery = %+v, want stored alert and notification", d)
+	}
+	if d.Config.URL != "https://hooks.example.com/new" || d.Config.Secret != "whsec_new" {
+		t.Errorf("delivery config = %+v, want current channel config, opened", d.Config)
+	}
+
+	// attempts are numbered after the 3 of the first run
+	if want := []int{4, 5}; !equalInts(store.attempts, want) {
+		t.Errorf("recorded attempts = %v, want %v", store.attempts, want)
+	}
+	if len(store.statuses) != 1 || store.statuses[0] != StatusSent || store.total != 5 || store.lastErr != "" {
+		t.Errorf("final status = %v, attempts %d, error %q, want sent, 5, none", store.statuses, store.total, store.lastErr)
+	}
+}
+
+func TestRedeliverFailsPermanently(t *testing.T) {
+	store := &fakeStore{alert: failedAlert()}
+	notifier := &fakeNotifier{errs: []error{
+		errors.New("webhook responded 503 Service Unavailable"),
+		&permanentError{err: errors.New("webhook responded 404 Not Found")},
+	}}
+	s := newTestAlertService(store, notifier)
+
+	if _, err := s.Redeliver(context.Background(), testUserID, testChannelID, testDeliveryID); err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	s.WorkerClosingWait()
+
+	// a permanent rejection is not retried, even with attempts left
+	if len(notifier.deliveries) != 2 {
+		t.Errorf("notify calls = %d, want 2", len(notifier.deliveries))
+	}
+	if want := []int{4, 5}; !equalInts(store.attempts, want) {
+		t.Errorf("recorded attempts = %v, want %v", store.attempts, want)
+	}
+	if len(store.statuses) != 1 || store.statuses[0] != StatusFailed || store.total != 5 {
+		t.Errorf("final status = %v, attempts %d, want failed, 5", store.statuses, store.total)
+	}
+	if store.lastErr != "webhook responded 404 Not Found" {
+		t.Errorf("last error = %q", store.lastErr)
+	}
+}
+
+func TestRedeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	store := &fakeStore{alert: failedAlert()}
+	transient := errors.New("webhook responded 503 Service Unavailable")
+	notifier := &fakeNotifier{errs: []error{transient, transient, transient, transient}}
+	s := newTestAlertService(store, notifier)
+
+	if _, err := s.Redeliver(context.Background(), testUserID, testChannelID, testDeliveryID); err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	s.WorkerClosingWait()
+
+	if len(notifier.deliveries) != s.maxAttempts {
+		t.Errorf("notify calls = %d, want %d", len(notifier.deliveries), s.maxAttempts)
+	}
+	if len(store.statuses) != 1 || store.statuses[0] != StatusFailed || store.total != 6 {
+		t.Errorf("final status = %v, attempts %d, want failed, 6", store.statuses, store.total)
+	}
+}
+
+func TestRedeliverRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		store     *fakeStore
+		notifiers map[string]Notifier
+		kind      apperror.Kind
+	}{
+		{
+			name:   "channel of another user",
+			userID: uuid.New(),
+			store:  &fakeStore{alert: failedAlert()},
+			kind:   apperror.NotFound,
+		},
+		{
+			name:   "delivery not found",
+			userID: testUserID,
+			store:  &fakeStore{getErr: &apperror.Error{Kind: apperror.NotFound, Message: "resource not found"}},
+			kind:   apperror.NotFound,
+		},
+		{
+			name:   "stored without notification",
+			userID: testUserID,
+			store: &fakeStore{alert: func() Alert {
+				a := failedAlert()
+				a.Notification = nil
+				return a
+			}()},
+			kind: apperror.InvalidInput,
+		},
+		{
+			name:      "no notifier for channel type",
+			userID:    testUserID,
+			store:     &fakeStore{alert: failedAlert()},
+			notifiers: map[string]Notifier{},
+			kind:      apperror.InvalidInput,
+		},
+		{
+			name:   "already in progress",
+			userID: testUserID,
+			store:  &fakeStore{alert: failedAlert(), busy: true},
+			kind:   apperror.Conflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &fakeNotifier{}
+			s := newTestAlertService(tt.store, notifier)
+			if tt.notifiers != nil {
+				s.notifiers = tt.notifiers
+			}
+
+			_, err := s.Redeliver(context.Background(), tt.userID, testChannelID, testDeliveryID)
+			if !apperror.IsKind(err, tt.kind) {
+				t.Fatalf("Redeliver err = %v, want kind %s", err, tt.kind)
+			}
+			s.WorkerClosingWait()
+			if len(notifier.deliveries) != 0 || len(tt.store.attempts) != 0 || len(tt.store.statuses) != 0 {
+				t.Errorf("rejected redelivery was sent or recorded")
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,272 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// max response body bytes kept of a webhook response, in attempt log and errors
+const webhookResponseBytes = 512
+
+// headers of a generic webhook delivery, receivers verify them (see SignWebhook)
+const (
+	HeaderDelivery  = "X-Monit-Delivery"
+	HeaderEvent     = "X-Monit-Event"
+	HeaderTimestamp = "X-Monit-Timestamp"
+	HeaderSignature = "X-Monit-Signature"
+)
+
+// webhookNotifier posts notification as json, shaped by payload, to url of channel
+type webhookNotifier struct {
+	client  *http.Client
+	payload func(n *Notification) any
+	// generic webhook, sends headers of channel config, and signs the delivery
+	generic bool
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, d *Delivery) (Attempt, error) {
+	cfg := &d.Config
+	body, err := json.Marshal(w.payload(d.Notification))
+	if err != nil {
+		return Attempt{}, &permanentError{err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return Attempt{}, &permanentError{err: errors.New("invalid webhook url")}
+	}
+	if w.generic {
+		for k, v := range cfg.Headers {
+			req.Header.Set(k, v)
+		}
+		// set after custom headers, so those can not spoof them
+		if d.ID != uuid.Nil {
+			req.Header.Set(HeaderDelivery, d.ID.String())
+		}
+		req.Header.Set(HeaderEvent, string(d.Notification.Event.Type))
+		// channels created before signing have no secret, they stay unsigned till it is rotated
+		if cfg.Secret != "" {
+			ts := time.Now().Unix()
+			req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+			req.Header.Set(HeaderSignature, SignWebhook(cfg.Secret, ts, body))
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Monit-Alerts/1.0")
+
+	return post(w.client, req, "webhook")
+}
+
+// post sends req with client, what came back is kept in the attempt. 2xx is success, 429 and 5xx may pass
+// on retry, any other status (revoked, not found, bad payload) will not. service names the receiver in errors
+func post(client *http.Client, req *http.Request, service string) (Attempt, error) {
+	start := time.Now()
+	resp, err := client.Do(req)
+	at := Attempt{Latency: time.Since(start)}
+	if err != nil {
+		// url of a webhook is its secret, keep it out of errors (they are logged and stored)
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactWebhookURL(urlErr.URL)
+		}
+		return at, err
+	}
+	defer resp.Body.Close()
+
+	at.StatusCode = resp.StatusCode
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBytes))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // drain, so connection is reused
+	// stored as text, drop invalid bytes (binary body or a rune cut at the limit)
+	at.Response = string(bytes.ToValidUTF8(excerpt, nil))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return at, nil
+	}
+
+	err = fmt.Errorf("%s responded %s: %s", service, resp.Status, strings.TrimSpace(at.Response))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return at, err
+	}
+	return at, &permanentError{err: err}
+}
+
+// SignWebhook returns X-Monit-Signature of body sent at ts (unix seconds, X-Monit-Timestamp),
+// "v1=" + hex of HMAC-SHA256 of "<ts>.<body>" keyed with signing secret of channel.
+// receivers recompute it over the raw body, compare in constant time, and reject old timestamps (replays)
+func SignWebhook(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func redactWebhookURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host + "/…"
+}
+
+// color of a notification, in chat messages
+func (n *Notification) color() int {
+	switch n.Event.Type {
+	case AlertDown:
+		return 0xD93F3F // red
+	case AlertRecovered, AlertCertRenewed:
+		return 0x2EB67D // green
+	default:
+		return 0xECB22E // amber
+	}
+}
+
+// slackPayload is a slack incoming webhook message, in mrkdwn
+func slackPayload(n *Notification) any {
+	esc := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*\n%s\n", esc.Replace(n.headline()), n.intro())
+	for _, f := range n.fields() {
+		fmt.Fprintf(&b, "*%s:* %s\n", f.label, esc.Replace(f.value))
+	}
+
+	return map[string]any{
+		"text": n.headline(), // shown in notifications
+		"attachments": []map[string]any{{
+			"color":     fmt.Sprintf("#%06X", n.color()),
+			"mrkdwn_in": []string{"text"},
+			"text":      b.String(),
+		}},
+	}
+}
+
+// discordPayload is a discord webhook message with one embed
+func discordPayload(n *Notification) any {
+	fields := make([]map[string]any, 0)
+	for _, f := range n.fields() {
+		fields = append(fields, map[string]any{"name": f.label, "value": f.value, "inline": false})
+	}
+
+	return map[string]any{
+		"content": n.headline(),
+		"embeds": []map[string]any{{
+			"title":       n.headline(),
+			"description": n.intro(),
+			"color":       n.color(),
+			"fields":      fields,
+			"timestamp":   n.At.UTC().Format(time.RFC3339),
+		}},
+		// mentions in monitor urls or reasons must not ping anyone
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	}
+}
+
+// teamsPayload is an adaptive card, as accepted by teams workflow (incoming) webhooks
+func teamsPayload(n *Notification) any {
+	style := "warning"
+	switch n.Event.Type {
+	case AlertDown:
+		style = "attention"
+	case AlertRecovered, AlertCertRenewed:
+		style = "good"
+	}
+
+	facts := make([]map[string]any, 0)
+	for _, f := range n.fields() {
+		facts = append(facts, map[string]any{"title": f.label, "value": f.value})
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body": []map[string]any{
+					{"type": "TextBlock", "text": n.headline(), "weight": "Bolder", "size": "Medium", "color": style, "wrap": true},
+					{"type": "TextBlock", "text": n.intro(), "wrap": true},
+					{"type": "FactSet", "facts": facts},
+				},
+			},
+		}},
+	}
+}
+
+// WebhookPayload is the body of a generic webhook
+type WebhookPayload struct {
+	Event    AlertType        `json:"event"`
+	SentAt   time.Time        `json:"sent_at"`
+	Message  string           `json:"message,omitempty"`
+	Monitor  WebhookMonitor   `json:"monitor"`
+	Incident *WebhookIncident `json:"incident,omitempty"`
+	Summary  string           `json:"summary"`
+}
+
+type WebhookMonitor struct {
+	ID   string `json:"id"`
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+type WebhookIncident struct {
+	ID          string     `json:"id,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	DurationSec int64      `json:"duration_sec,omitempty"`
+	LastReason  string     `json:"last_reason,omitempty"`
+}
+
+func webhookPayload(n *Notification) any {
+	ev := &n.Event
+	p := WebhookPayload{
+		Event:   ev.Type,
+		SentAt:  n.At.UTC(),
+		Message: ev.Message,
+		Monitor: WebhookMonitor{
+			ID:   n.Monitor.ID.String(),
+			URL:  n.Monitor.URL,
+			Type: n.Monitor.Type,
+		},
+		Summary: n.headline(),
+	}
+
+	if inc := ev.Incident; inc != nil {
+		wi := &WebhookIncident{
+			DurationSec: int64(inc.Duration.Seconds()),
+			LastReason:  inc.LastReason,
+		}
+		if !inc.StartedAt.IsZero() {
+			t := inc.StartedAt.UTC()
+			wi.StartedAt = &t
+		}
+		if !inc.ResolvedAt.IsZero() {
+			t := inc.ResolvedAt.UTC()
+			wi.ResolvedAt = &t
+		}
+		p.Incident = wi
+	}
+	if ev.IncidentID != uuid.Nil {
+		if p.Incident == nil {
+			p.Incident = &WebhookIncident{}
+		}
+		p.Incident.ID = ev.IncidentID.String()
+	}
+	return p
+}
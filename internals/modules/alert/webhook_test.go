@@ -0,0 +1,99 @@
+package alert
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// computed independently: printf '%s' '1700000000.{"event":"DOWN"}' | openssl dgst -sha256 -hmac whsec_test
+	const want = "v1=497c8de027fede4052ee088bac759bca4bf6378a24babd6555270b7959f9d04f"
+
+	if got := SignWebhook("whsec_test", 1700000000, []byte(`{"event":"DOWN"}`)); got != want {
+		t.Errorf("SignWebhook = %s, want %s", got, want)
+	}
+
+	// timestamp, body and key are all covered
+	for name, got := range map[string]string{
+		"timestamp": SignWebhook("whsec_test", 1700000001, []byte(`{"event":"DOWN"}`)),
+		"body":      SignWebhook("whsec_test", 1700000000, []byte(`{"event":"UP"}`)),
+		"key":       SignWebhook("whsec_other", 1700000000, []byte(`{"event":"DOWN"}`)),
+	} {
+		if got == want {
+			t.Errorf("signature unchanged with other %s", name)
+		}
+	}
+}
+
+func TestWebhookNotifySigned(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	cfg := channelConfig(srv.URL)
+	cfg.Headers = map[string]string{
+		"X-Team":        "ops",
+		HeaderSignature: "v1=spoofed",
+		HeaderTimestamp: "1",
+		HeaderEvent:     "RECOVERED",
+	}
+	d := &Delivery{ID: testDeliveryID, Config: cfg, Notification: testNotification()}
+	n := &webhookNotifier{client: newWebhookClient(5*time.Second, true), payload: webhookPayload, generic: true}
+
+	start := time.Now().Unix()
+	at, err := n.Notify(context.Background(), d)
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if at.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", at.StatusCode)
+	}
+
+	ts, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil || ts < start || ts > time.Now().Unix() {
+		t.Fatalf("%s = %q, want current unix time", HeaderTimestamp, header.Get(HeaderTimestamp))
+	}
+	// what a receiver does, recompute over the raw body
+	if got, want := header.Get(HeaderSignature), SignWebhook(cfg.Secret, ts, body); got != want {
+		t.Errorf("%s = %s, want %s", HeaderSignature, got, want)
+	}
+	if got := header.Get(HeaderEvent); got != string(AlertDown) {
+		t.Errorf("%s = %s, want DOWN", HeaderEvent, got)
+	}
+	if got := header.Get(HeaderDelivery); got != testDeliveryID.String() {
+		t.Errorf("%s = %s, want %s", HeaderDelivery, got, testDeliveryID)
+	}
+	if got := header.Get("X-Team"); got != "ops" {
+		t.Errorf("custom header X-Team = %q, want ops", got)
+	}
+}
+
+func TestWebhookNotifyUnsigned(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	// channels created before signing have no secret
+	cfg := channelConfig(srv.URL)
+	cfg.Secret = ""
+	n := &webhookNotifier{client: newWebhookClient(5*time.Second, true), payload: webhookPayload, generic: true}
+	if _, err := n.Notify(context.Background(), &Delivery{Config: cfg, Notification: testNotification()}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if header.Get(HeaderSignature) != "" || header.Get(HeaderTimestamp) != "" {
+		t.Errorf("unsigned channel sent signature headers: %v", header)
+	}
+}
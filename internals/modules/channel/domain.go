@@ -0,0 +1,69 @@
+package channel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// channel types
+const (
+	TypeEmail   = "email"
+	TypeSlack   = "slack"   // slack incoming webhook
+	TypeDiscord = "discord" // discord channel webhook
+	TypeTeams   = "teams"   // microsoft teams workflow (incoming) webhook
+	TypeWebhook = "webhook" // generic json webhook
+
+	TypePagerDuty = "pagerduty" // pagerduty service, events api v2 integration
+	TypeOpsgenie  = "opsgenie"  // opsgenie api integration
+)
+
+// Config is where a channel delivers, Email for email channels, RoutingKey for pagerduty, APIKey for opsgenie
+// (both with Region of the account), URL (and Headers) for the rest. webhook urls carry their own secret, never return or log it, nor the keys.
+// once stored, url, secret, keys and header values are sealed, open the config only to send (Service.OpenConfig)
+type Config struct {
+	Email      string            `json:"email,omitempty"`
+	URL        string            `json:"url,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"` // extra request headers, generic webhook only
+	Secret     string            `json:"secret,omitempty"`  // key deliveries are signed with, generic webhook only
+	RoutingKey string            `json:"routing_key,omitempty"`
+	APIKey     string            `json:"api_key,omitempty"`
+	Region     string            `json:"region,omitempty"` // us (if empty) or eu, pagerduty and opsgenie only
+	// not secret, what responses show of sealed url and key
+	URLHint string `json:"url_hint,omitempty"`
+	KeyHint string `json:"key_hint,omitempty"`
+}
+
+// regions of pagerduty and opsgenie accounts
+const (
+	RegionUS = "us"
+	RegionEU = "eu"
+)
+
+type Channel struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Name      string
+	Type      string
+	Config    Config
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CreateChannelCmd struct {
+	UserID uuid.UUID
+	Name   string
+	Type   string
+	Config Config
+}
+
+// UpdateChannelCmd changes only fields which are set, type of a channel never changes
+type UpdateChannelCmd struct {
+	UserID       uuid.UUID
+	ChannelID    uuid.UUID
+	Name         *string
+	Config       *Config // secret is kept, it changes only by RotateSecret
+	Enabled      *bool
+	RotateSecret bool
+}
@@ -0,0 +1,119 @@
+package channel
+
+import (
+	"sort"
+	"time"
+)
+
+type CreateChannelRequest struct {
+	Name   string               `json:"name" validate:"required,max=100"`
+	Type   string               `json:"type" validate:"required,oneof=email slack discord teams webhook pagerduty opsgenie"`
+	Config ChannelConfigRequest `json:"config"`
+}
+
+// ChannelConfigRequest is config of a channel, email for email channels, routing_key (integration key) for pagerduty,
+// api_key for opsgenie (region of the account, us or eu), url for the rest, headers are extra request headers of a generic webhook
+type ChannelConfigRequest struct {
+	Email      string            `json:"email" validate:"omitempty,email,max=320"`
+	URL        string            `json:"url" validate:"omitempty,url,max=2048"`
+	Headers    map[string]string `json:"headers" validate:"omitempty,lte=20,dive,keys,required,max=256,endkeys,max=4096"`
+	RoutingKey string            `json:"routing_key" validate:"omitempty,max=128"`
+	APIKey     string            `json:"api_key" validate:"omitempty,max=128"`
+	Region     string            `json:"region" validate:"omitempty,oneof=us eu"`
+}
+
+// UpdateChannelRequest changes only fields which are sent, config is replaced as a whole (signing secret is kept),
+// rotate_secret replaces signing secret of a webhook channel
+type UpdateChannelRequest struct {
+	Name         *string               `json:"name" validate:"omitempty,min=1,max=100"`
+	Config       *ChannelConfigRequest `json:"config"`
+	Enabled      *bool                 `json:"enabled"`
+	RotateSecret bool                  `json:"rotate_secret"`
+}
+
+type SetMonitorChannelsRequest struct {
+	ChannelIDs []string `json:"channel_ids" validate:"lte=50,dive,uuid"`
+}
+
+// signing secret of a webhook channel is returned only here, and when it is rotated
+type CreateChannelResponse struct {
+	ChannelID     string `json:"channel_id"`
+	SigningSecret string `json:"signing_secret,omitempty"`
+}
+
+type ChannelResponse struct {
+	ID            string                `json:"id"`
+	Name          string                `json:"name"`
+	Type          string                `json:"type"`
+	Config        ChannelConfigResponse `json:"config"`
+	Enabled       bool                  `json:"enabled"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+	SigningSecret string                `json:"signing_secret,omitempty"` // set only on rotation
+}
+
+// ChannelConfigResponse hides secrets of config, webhook urls are cut to scheme and host, header values are left out,
+// of pagerduty and opsgenie keys only the last 4 chars are shown
+type ChannelConfigResponse struct {
+	Email       string   `json:"email,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	HeaderNames []string `json:"header_names,omitempty"`
+	Signed      bool     `json:"signed,omitempty"` // deliveries are signed, webhook has a signing secret
+	KeyHint     string   `json:"key_hint,omitempty"`
+	Region      string   `json:"region,omitempty"`
+}
+
+type GetAllChannelsResponse struct {
+	Channels []ChannelResponse `json:"channels"`
+}
+
+type MonitorChannelsResponse struct {
+	MonitorID string            `json:"monitor_id"`
+	Channels  []ChannelResponse `json:"channels"`
+}
+
+func toConfig(req *ChannelConfigRequest) Config {
+	return Config{
+		Email:      req.Email,
+		URL:        req.URL,
+		Headers:    req.Headers,
+		RoutingKey: req.RoutingKey,
+		APIKey:     req.APIKey,
+		Region:     req.Region,
+	}
+}
+
+func toChannelResponse(c *Channel) ChannelResponse {
+	return ChannelResponse{
+		ID:        c.ID.String(),
+		Name:      c.Name,
+		Type:      c.Type,
+		Config:    toConfigResponse(&c.Config),
+		Enabled:   c.Enabled,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func toChannelResponses(channels []Channel) []ChannelResponse {
+	res := make([]ChannelResponse, 0, len(channels))
+	for i := range channels {
+		res = append(res, toChannelResponse(&channels[i]))
+	}
+	return res
+}
+
+func toConfigResponse(cfg *Config) ChannelConfigResponse {
+	res := ChannelConfigResponse{
+		Email:   cfg.Email,
+		URL:     cfg.URLHint,
+		Signed:  cfg.Secret != "",
+		KeyHint: cfg.KeyHint,
+		Region:  cfg.Region,
+	}
+	for name := range cfg.Headers {
+		res.HeaderNames = append(res.HeaderNames, name)
+	}
+	sort.Strings(res.HeaderNames)
+	return res
+}
@@ -0,0 +1,339 @@
+package channel
+
+import (
+	"encoding/json"
+	"net/http"
+	middle "project-k/internals/middleware"
+	"project-k/pkg/apperror"
+	"project-k/pkg/utils"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// default and max page size of channels
+const (
+	defaultPageSize int32 = 50
+	maxPageSize     int32 = 200
+)
+
+type Handler struct {
+	service   *Service
+	validator *validator.Validate
+	logger    *zerolog.Logger
+}
+
+func NewHandler(service *Service, validator *validator.Validate, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *Handler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.channel.create_channel"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	var req CreateChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+
+	c, err := h.service.CreateChannel(ctx, CreateChannelCmd{
+		UserID: reqClaims.UserID,
+		Name:   req.Name,
+		Type:   req.Type,
+		Config: toConfig(&req.Config),
+	})
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("create channel error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, reqID, "channel created successfully", CreateChannelResponse{
+		ChannelID:     c.ID.String(),
+		SigningSecret: c.Config.Secret,
+	})
+}
+
+func (h *Handler) GetChannel(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.channel.get_channel"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	c, err := h.service.GetChannel(ctx, reqClaims.UserID, channelID)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving channel error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "channel retrieved successfully", toChannelResponse(&c))
+}
+
+// /channels?offset=0&limit=50
+func (h *Handler) GetAllChannels(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.channel.get_all_channels"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	limit, offset, err := pageParams(r, defaultPageSize, maxPageSize)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	channels, err := h.service.ListChannels(ctx, reqClaims.UserID, limit, offset)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving channels error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "channels retrieved successfully", GetAllChannelsResponse{
+		Channels: toChannelResponses(channels),
+	})
+}
+
+func (h *Handler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.channel.update_channel"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	var req UpdateChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+
+	cmd := UpdateChannelCmd{
+		UserID:       reqClaims.UserID,
+		ChannelID:    channelID,
+		Name:         req.Name,
+		Enabled:      req.Enabled,
+		RotateSecret: req.RotateSecret,
+	}
+	if req.Config != nil {
+		cfg := toConfig(req.Config)
+		cmd.Config = &cfg
+	}
+
+	c, err := h.service.UpdateChannel(ctx, cmd)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("update channel error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	res := toChannelResponse(&c)
+	if req.RotateSecret {
+		res.SigningSecret = c.Config.Secret
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, "channel updated successfully", res)
+}
+
+func (h *Handler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.channel.delete_channel"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	channelID, err := uuid.Parse(chi.URLParam(r, "channelID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	if err := h.service.DeleteChannel(ctx, reqClaims.UserID, channelID); err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("delete channel error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "channel deleted successfully", "ok")
+}
+
+func (h *Handler) GetMonitorChannels(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.channel.get_monitor_channels"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	channels, err := h.service.MonitorChannels(ctx, reqClaims.UserID, monitorID)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving monitor channels error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor channels retrieved successfully", MonitorChannelsResponse{
+		MonitorID: monitorID.String(),
+		Channels:  toChannelResponses(channels),
+	})
+}
+
+func (h *Handler) SetMonitorChannels(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.channel.set_monitor_channels"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	var req SetMonitorChannelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+
+	channelIDs := make([]uuid.UUID, 0, len(req.ChannelIDs))
+	for _, s := range req.ChannelIDs {
+		channelIDs = append(channelIDs, uuid.MustParse(s)) // validated above
+	}
+
+	channels, err := h.service.SetMonitorChannels(ctx, reqClaims.UserID, monitorID, channelIDs)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("set monitor channels error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor channels updated successfully", MonitorChannelsResponse{
+		MonitorID: monitorID.String(),
+		Channels:  toChannelResponses(channels),
+	})
+}
+
+// pageParams parses optional limit and offset of query,
+// limit falls back to def when missing, non positive or above maxLimit
+func pageParams(r *http.Request, def, maxLimit int32) (int32, int32, error) {
+	var limit, offset int64
+	var err error
+	if s := r.URL.Query().Get("limit"); s != "" {
+		if limit, err = strconv.ParseInt(s, 10, 32); err != nil {
+			return 0, 0, err
+		}
+	}
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if offset, err = strconv.ParseInt(s, 10, 32); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if limit <= 0 || limit > int64(maxLimit) {
+		limit = int64(def)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return int32(limit), int32(offset), nil
+}
@@ -0,0 +1,235 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"project-k/pkg/apperror"
+	"project-k/pkg/db"
+	"project-k/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	sealer  Sealer // secrets of config are stored sealed
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, sealer Sealer, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		sealer:  sealer,
+		logger:  logger,
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, cmd CreateChannelCmd) (Channel, error) {
+	const op string = "repo.channel.create"
+
+	config, err := r.marshalConfig(cmd.Config)
+	if err != nil {
+		return Channel{}, apperror.New(apperror.Internal, op, err)
+	}
+
+	c, err := r.querier.CreateNotificationChannel(ctx, db.CreateNotificationChannelParams{
+		UserID: utils.ToPgUUID(cmd.UserID),
+		Name:   cmd.Name,
+		Type:   cmd.Type,
+		Config: config,
+	})
+	if err != nil {
+		return Channel{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return toChannel(op, &c)
+}
+
+// Get returns channel of user, NotFound if user does not own it
+func (r *Repository) Get(ctx context.Context, userID, channelID uuid.UUID) (Channel, error) {
+	const op string = "repo.channel.get"
+
+	c, err := r.querier.GetNotificationChannel(ctx, db.GetNotificationChannelParams{
+		ID:     utils.ToPgUUID(channelID),
+		UserID: utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		return Channel{}, utils.WrapRepoError(op, err, true, r.logger)
+	}
+	return toChannel(op, &c)
+}
+
+func (r *Repository) List(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]Channel, error) {
+	const op string = "repo.channel.list"
+
+	rows, err := r.querier.ListNotificationChannels(ctx, db.ListNotificationChannelsParams{
+		UserID: utils.ToPgUUID(userID),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return []Channel{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return toChannels(op, rows)
+}
+
+func (r *Repository) Update(ctx context.Context, c Channel) (Channel, error) {
+	const op string = "repo.channel.update"
+
+	config, err := r.marshalConfig(c.Config)
+	if err != nil {
+		return Channel{}, apperror.New(apperror.Internal, op, err)
+	}
+
+	updated, err := r.querier.UpdateNotificationChannel(ctx, db.UpdateNotificationChannelParams{
+		ID:      utils.ToPgUUID(c.ID),
+		UserID:  utils.ToPgUUID(c.UserID),
+		Name:    c.Name,
+		Config:  config,
+		Enabled: c.Enabled,
+	})
+	if err != nil {
+		return Channel{}, utils.WrapRepoError(op, err, true, r.logger)
+	}
+	return toChannel(op, &updated)
+}
+
+func (r *Repository) Delete(ctx context.Context, userID, channelID uuid.UUID) error {
+	const op string = "repo.channel.delete"
+
+	n, err := r.querier.DeleteNotificationChannel(ctx, db.DeleteNotificationChannelParams{
+		ID:     utils.ToPgUUID(channelID),
+		UserID: utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, false, r.logger)
+	}
+	if n == 0 {
+		return &apperror.Error{
+			Kind:    apperror.NotFound,
+			Op:      op,
+			Message: "resource not found",
+		}
+	}
+	return nil
+}
+
+// CountOwned returns how many of channelIDs user owns
+func (r *Repository) CountOwned(ctx context.Context, userID uuid.UUID, channelIDs []uuid.UUID) (int64, error) {
+	const op string = "repo.channel.count_owned"
+
+	n, err := r.querier.CountUserNotificationChannels(ctx, db.CountUserNotificationChannelsParams{
+		UserID:     utils.ToPgUUID(userID),
+		ChannelIds: toPgUUIDs(channelIDs),
+	})
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return n, nil
+}
+
+// ListForMonitor returns channels linked to monitor, enabled or not
+func (r *Repository) ListForMonitor(ctx context.Context, monitorID uuid.UUID) ([]Channel, error) {
+	const op string = "repo.channel.list_for_monitor"
+
+	rows, err := r.querier.ListMonitorNotificationChannels(ctx, utils.ToPgUUID(monitorID))
+	if err != nil {
+		return []Channel{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return toChannels(op, rows)
+}
+
+// SetForMonitor replaces channels of monitor with channelIDs of user
+func (r *Repository) SetForMonitor(ctx context.Context, userID, monitorID uuid.UUID, channelIDs []uuid.UUID) error {
+	const op string = "repo.channel.set_for_monitor"
+
+	err := r.querier.SetMonitorNotificationChannels(ctx, db.SetMonitorNotificationChannelsParams{
+		MonitorID:  utils.ToPgUUID(monitorID),
+		UserID:     utils.ToPgUUID(userID),
+		ChannelIds: toPgUUIDs(channelIDs),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return nil
+}
+
+// OpenConfig returns cfg of a stored channel with its secrets decrypted, to send to it
+func (r *Repository) OpenConfig(cfg Config) (Config, error) {
+	const op string = "repo.channel.open_config"
+
+	opened, err := openConfig(r.sealer, cfg)
+	if err != nil {
+		return Config{}, apperror.New(apperror.Internal, op, err)
+	}
+	return opened, nil
+}
+
+// SealPlainConfigs encrypts secrets of channels stored before sealing existed, returns how many were sealed
+func (r *Repository) SealPlainConfigs(ctx context.Context) (int, error) {
+	const op string = "repo.channel.seal_plain_configs"
+
+	rows, err := r.querier.ListNotificationChannelsWithPlainConfig(ctx)
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	for i, row := range rows {
+		var cfg Config
+		if err := json.Unmarshal(row.Config, &cfg); err != nil {
+			return i, apperror.New(apperror.Internal, op, err)
+		}
+		config, err := r.marshalConfig(cfg)
+		if err != nil {
+			return i, apperror.New(apperror.Internal, op, err)
+		}
+		if err := r.querier.UpdateNotificationChannelConfig(ctx, db.UpdateNotificationChannelConfigParams{ID: row.ID, Config: config}); err != nil {
+			return i, utils.WrapRepoError(op, err, false, r.logger)
+		}
+	}
+	return len(rows), nil
+}
+
+// marshalConfig seals cfg into its stored form
+func (r *Repository) marshalConfig(cfg Config) ([]byte, error) {
+	if err := sealConfig(r.sealer, &cfg); err != nil {
+		return nil, err
+	}
+	return json.Marshal(cfg)
+}
+
+func toChannels(op string, rows []db.NotificationChannel) ([]Channel, error) {
+	channels := make([]Channel, 0, len(rows))
+	for i := range rows {
+		c, err := toChannel(op, &rows[i])
+		if err != nil {
+			return []Channel{}, err
+		}
+		channels = append(channels, c)
+	}
+	return channels, nil
+}
+
+func toChannel(op string, c *db.NotificationChannel) (Channel, error) {
+	ch := Channel{
+		ID:        utils.FromPgUUID(c.ID),
+		UserID:    utils.FromPgUUID(c.UserID),
+		Name:      c.Name,
+		Type:      c.Type,
+		Enabled:   c.Enabled,
+		CreatedAt: utils.FromPgTimestamptz(c.CreatedAt),
+		UpdatedAt: utils.FromPgTimestamptz(c.UpdatedAt),
+	}
+	if err := json.Unmarshal(c.Config, &ch.Config); err != nil {
+		return Channel{}, apperror.New(apperror.Internal, op, err)
+	}
+	return ch, nil
+}
+
+func toPgUUIDs(ids []uuid.UUID) []pgtype.UUID {
+	out := make([]pgtype.UUID, len(ids))
+	for i, id := range ids {
+		out[i] = utils.ToPgUUID(id)
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package channel
+
+import "github.com/go-chi/chi/v5"
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/", h.CreateChannel)
+	r.Get("/", h.GetAllChannels)
+	r.Get("/{channelID}", h.GetChannel)
+	r.Patch("/{channelID}", h.UpdateChannel)
+	r.Delete("/{channelID}", h.DeleteChannel)
+
+	return r
+}
+
+// MonitorRoutes are mounted under /monitors/{monitorID}/channels
+func MonitorRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.GetMonitorChannels)
+	r.Put("/", h.SetMonitorChannels)
+
+	return r
+}
+
+/*
+- POST: /channels -> create a notification channel (email, slack, discord, teams, webhook, pagerduty, opsgenie),
+  signing secret of a webhook channel is in response, it is not shown again
+	req auth : true
+	body : CreateChannelRequest
+	resp : CreateChannelResponse
+
+- GET: /channels?offset={}&limit={} -> channels of a user
+	req auth : true
+	body : nil
+	resp : GetAllChannelsResponse
+
+- GET: /channels/{channelID} -> a channel, secrets of config are hidden
+	req auth : true
+	body : nil
+	resp : ChannelResponse
+
+- PATCH: /channels/{channelID} -> rename, replace config, enable / disable a channel, rotate signing secret of a webhook
+	req auth : true
+	body : UpdateChannelRequest
+	resp : ChannelResponse
+
+- DELETE: /channels/{channelID} -> delete a channel, it is unlinked from its monitors
+	req auth : true
+	body : nil
+	resp : ok / error
+
+- GET: /monitors/{monitorID}/channels -> channels alerts of a monitor go to
+	req auth : true
+	body : nil
+	resp : MonitorChannelsResponse
+
+- PUT: /monitors/{monitorID}/channels -> replace channels of a monitor
+	req auth : true
+	body : SetMonitorChannelsRequest
+	resp : MonitorChannelsResponse
+*/
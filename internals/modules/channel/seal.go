@@ -0,0 +1,101 @@
+package channel
+
+import (
+	"net/url"
+	"project-k/internals/security"
+)
+
+// Sealer encrypts secrets kept at rest, see security.Cipher
+type Sealer interface {
+	Seal(plaintext []byte) (string, error)
+	Open(sealed string) ([]byte, error)
+}
+
+// sealConfig encrypts url, signing secret, keys and header values of cfg, values already sealed are left as is.
+// what responses show of url and key is kept beside them as hints, so they are opened only to send
+func sealConfig(s Sealer, cfg *Config) error {
+	if cfg.URL != "" && !security.IsSealed(cfg.URL) {
+		cfg.URLHint = urlHint(cfg.URL)
+	}
+	if key := cfg.RoutingKey + cfg.APIKey; key != "" && !security.IsSealed(key) {
+		cfg.KeyHint = keyHint(key)
+	}
+
+	for _, v := range []*string{&cfg.URL, &cfg.Secret, &cfg.RoutingKey, &cfg.APIKey} {
+		if err := sealValue(s, v); err != nil {
+			return err
+		}
+	}
+	if cfg.Headers != nil {
+		headers := make(map[string]string, len(cfg.Headers))
+		for k, v := range cfg.Headers {
+			if err := sealValue(s, &v); err != nil {
+				return err
+			}
+			headers[k] = v
+		}
+		cfg.Headers = headers
+	}
+	return nil
+}
+
+// openConfig returns cfg with its sealed values decrypted, plaintext values (channels stored before sealing) are kept
+func openConfig(s Sealer, cfg Config) (Config, error) {
+	for _, v := range []*string{&cfg.URL, &cfg.Secret, &cfg.RoutingKey, &cfg.APIKey} {
+		if err := openValue(s, v); err != nil {
+			return Config{}, err
+		}
+	}
+	if cfg.Headers != nil {
+		headers := make(map[string]string, len(cfg.Headers))
+		for k, v := range cfg.Headers {
+			if err := openValue(s, &v); err != nil {
+				return Config{}, err
+			}
+			headers[k] = v
+		}
+		cfg.Headers = headers
+	}
+	return cfg, nil
+}
+
+func sealValue(s Sealer, v *string) error {
+	if *v == "" || security.IsSealed(*v) {
+		return nil
+	}
+	sealed, err := s.Seal([]byte(*v))
+	if err != nil {
+		return err
+	}
+	*v = sealed
+	return nil
+}
+
+func openValue(s Sealer, v *string) error {
+	if !security.IsSealed(*v) {
+		return nil
+	}
+	plain, err := s.Open(*v)
+	if err != nil {
+		return err
+	}
+	*v = string(plain)
+	return nil
+}
+
+// urlHint is url cut to scheme and host, the path of a webhook url is its secret
+func urlHint(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host + "/…"
+}
+
+// keyHint is the last 4 chars of a pagerduty or opsgenie key, none of a short one
+func keyHint(key string) string {
+	if len(key) <= 8 {
+		return ""
+	}
+	return "…" + key[len(key)-4:]
+}
@@ -0,0 +1,120 @@
+package channel
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"project-k/config"
+	"project-k/internals/security"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSealer(t *testing.T) Sealer {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	c, err := security.NewCipher(&config.AuthConfig{EncryptionKey: key})
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestSealConfig(t *testing.T) {
+	s := testSealer(t)
+	tests := []struct {
+		name    string
+		cfg     Config
+		secrets []string // must not be stored in plaintext
+		res     ChannelConfigResponse
+	}{
+		{
+			name:    "webhook",
+			cfg:     Config{URL: "https://hooks.example.com/T0/B0/xyzzy", Secret: "whsec_abc", Headers: map[string]string{"Authorization": "Bearer tok-1"}},
+			secrets: []string{"xyzzy", "whsec_abc", "tok-1"},
+			res:     ChannelConfigResponse{URL: "https://hooks.example.com/…", HeaderNames: []string{"Authorization"}, Signed: true},
+		},
+		{
+			name:    "slack",
+			cfg:     Config{URL: "https://hooks.slack.com/services/T0/B0/s3cr3t"},
+			secrets: []string{"s3cr3t"},
+			res:     ChannelConfigResponse{URL: "https://hooks.slack.com/…"},
+		},
+		{
+			name:    "pagerduty",
+			cfg:     Config{RoutingKey: "0123456789abcdef0123456789abcdef", Region: RegionEU},
+			secrets: []string{"0123456789abcdef"},
+			res:     ChannelConfigResponse{KeyHint: "…cdef", Region: RegionEU},
+		},
+		{
+			name:    "opsgenie",
+			cfg:     Config{APIKey: "2d3f0a4e-1b2c-4d5e-8f90-abcdef123456", Region: RegionUS},
+			secrets: []string{"2d3f0a4e"},
+			res:     ChannelConfigResponse{KeyHint: "…3456", Region: RegionUS},
+		},
+		{
+			name: "email is not a secret",
+			cfg:  Config{Email: "ops@example.com"},
+			res:  ChannelConfigResponse{Email: "ops@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain := tt.cfg
+			sealed := tt.cfg
+			if err := sealConfig(s, &sealed); err != nil {
+				t.Fatalf("sealConfig: %v", err)
+			}
+			if tt.cfg.Headers != nil && tt.cfg.Headers["Authorization"] != "Bearer tok-1" {
+				t.Fatal("sealConfig changed headers of its caller")
+			}
+
+			stored, _ := json.Marshal(sealed)
+			for _, secret := range tt.secrets {
+				if strings.Contains(string(stored), secret) {
+					t.Errorf("stored config %s holds %q", stored, secret)
+				}
+			}
+			if res := toConfigResponse(&sealed); !reflect.DeepEqual(res, tt.res) {
+				t.Errorf("response = %+v, want %+v", res, tt.res)
+			}
+
+			// sealing again (an update keeping the signing secret) leaves sealed values alone
+			again := sealed
+			if err := sealConfig(s, &again); err != nil || !reflect.DeepEqual(again, sealed) {
+				t.Errorf("sealed twice = %+v, %v, want %+v", again, err, sealed)
+			}
+
+			opened, err := openConfig(s, sealed)
+			if err != nil {
+				t.Fatalf("openConfig: %v", err)
+			}
+			opened.URLHint, opened.KeyHint = "", ""
+			if !reflect.DeepEqual(opened, plain) {
+				t.Errorf("opened = %+v, want %+v", opened, plain)
+			}
+		})
+	}
+}
+
+func TestOpenConfigPlaintext(t *testing.T) {
+	// stored before sealing, read as is until sealed at startup
+	cfg := Config{URL: "https://hooks.example.com/x", Secret: "whsec_abc", RoutingKey: "key", Headers: map[string]string{"X": "y"}}
+	opened, err := openConfig(testSealer(t), cfg)
+	if err != nil || !reflect.DeepEqual(opened, cfg) {
+		t.Errorf("openConfig = %+v, %v, want %+v", opened, err, cfg)
+	}
+}
+
+func TestOpenConfigOtherKey(t *testing.T) {
+	cfg := Config{URL: "https://hooks.example.com/x"}
+	if err := sealConfig(testSealer(t), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := security.NewCipher(&config.AuthConfig{EncryptionKey: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32))})
+	if _, err := openConfig(other, cfg); err == nil {
+		t.Error("openConfig with another key succeeded")
+	}
+}
@@ -0,0 +1,263 @@
+package channel
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+// MonitorOwner returns NotFound if user does not own the monitor
+type MonitorOwner interface {
+	GetMonitor(context.Context, uuid.UUID, uuid.UUID) (monitor.Monitor, error)
+}
+
+// Service manages notification channels of users, and which monitors alert to them
+type Service struct {
+	repo       *Repository
+	monitorSvc MonitorOwner
+}
+
+func NewService(repo *Repository, monitorSvc MonitorOwner) *Service {
+	return &Service{
+		repo:       repo,
+		monitorSvc: monitorSvc,
+	}
+}
+
+func (s *Service) CreateChannel(ctx context.Context, cmd CreateChannelCmd) (Channel, error) {
+	const op string = "service.channel.create"
+
+	if err := validateConfig(op, cmd.Type, &cmd.Config); err != nil {
+		return Channel{}, err
+	}
+	if cmd.Type == TypeWebhook {
+		secret, err := newSigningSecret()
+		if err != nil {
+			return Channel{}, apperror.New(apperror.Internal, op, err)
+		}
+		cmd.Config.Secret = secret
+	}
+	c, err := s.repo.Create(ctx, cmd)
+	if err != nil {
+		return Channel{}, err
+	}
+	// stored sealed, returned in plaintext this once, to be shown to user
+	c.Config.Secret = cmd.Config.Secret
+	return c, nil
+}
+
+// OpenConfig returns cfg of a channel with its secrets decrypted, only to send to it
+func (s *Service) OpenConfig(cfg Config) (Config, error) {
+	return s.repo.OpenConfig(cfg)
+}
+
+func (s *Service) GetChannel(ctx context.Context, userID, channelID uuid.UUID) (Channel, error) {
+	return s.repo.Get(ctx, userID, channelID)
+}
+
+func (s *Service) ListChannels(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]Channel, error) {
+	return s.repo.List(ctx, userID, limit, offset)
+}
+
+func (s *Service) UpdateChannel(ctx context.Context, cmd UpdateChannelCmd) (Channel, error) {
+	const op string = "service.channel.update"
+
+	c, err := s.repo.Get(ctx, cmd.UserID, cmd.ChannelID)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if cmd.Name != nil {
+		c.Name = *cmd.Name
+	}
+	if cmd.Config != nil {
+		if err := validateConfig(op, c.Type, cmd.Config); err != nil {
+			return Channel{}, err
+		}
+		cmd.Config.Secret = c.Config.Secret
+		c.Config = *cmd.Config
+	}
+	if cmd.RotateSecret {
+		if c.Type != TypeWebhook {
+			return Channel{}, &apperror.Error{
+				Kind:    apperror.InvalidInput,
+				Op:      op,
+				Message: "only webhook channels have a signing secret",
+			}
+		}
+		secret, err := newSigningSecret()
+		if err != nil {
+			return Channel{}, apperror.New(apperror.Internal, op, err)
+		}
+		c.Config.Secret = secret
+	}
+	if cmd.Enabled != nil {
+		c.Enabled = *cmd.Enabled
+	}
+	updated, err := s.repo.Update(ctx, c)
+	if err != nil {
+		return Channel{}, err
+	}
+	if cmd.RotateSecret {
+		// stored sealed, returned in plaintext this once, to be shown to user
+		updated.Config.Secret = c.Config.Secret
+	}
+	return updated, nil
+}
+
+func (s *Service) DeleteChannel(ctx context.Context, userID, channelID uuid.UUID) error {
+	return s.repo.Delete(ctx, userID, channelID)
+}
+
+// MonitorChannels returns channels linked to a monitor of user
+func (s *Service) MonitorChannels(ctx context.Context, userID, monitorID uuid.UUID) ([]Channel, error) {
+	if _, err := s.monitorSvc.GetMonitor(ctx, userID, monitorID); err != nil {
+		return []Channel{}, err
+	}
+	return s.repo.ListForMonitor(ctx, monitorID)
+}
+
+// SetMonitorChannels links monitor of user to exactly channelIDs (empty unlinks all), returns linked channels
+func (s *Service) SetMonitorChannels(ctx context.Context, userID, monitorID uuid.UUID, channelIDs []uuid.UUID) ([]Channel, error) {
+	const op string = "service.channel.set_monitor_channels"
+
+	if _, err := s.monitorSvc.GetMonitor(ctx, userID, monitorID); err != nil {
+		return []Channel{}, err
+	}
+
+	// drop duplicates, so count of owned ones can be compared
+	seen := make(map[uuid.UUID]struct{}, len(channelIDs))
+	ids := make([]uuid.UUID, 0, len(channelIDs))
+	for _, id := range channelIDs {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > 0 {
+		owned, err := s.repo.CountOwned(ctx, userID, ids)
+		if err != nil {
+			return []Channel{}, err
+		}
+		if owned != int64(len(ids)) {
+			return []Channel{}, &apperror.Error{
+				Kind:    apperror.NotFound,
+				Op:      op,
+				Message: "channel not found",
+			}
+		}
+	}
+
+	if err := s.repo.SetForMonitor(ctx, userID, monitorID, ids); err != nil {
+		return []Channel{}, err
+	}
+	return s.repo.ListForMonitor(ctx, monitorID)
+}
+
+// ChannelsOfMonitor returns enabled channels of a monitor, for alerting, it does not check owner
+func (s *Service) ChannelsOfMonitor(ctx context.Context, monitorID uuid.UUID) ([]Channel, error) {
+	channels, err := s.repo.ListForMonitor(ctx, monitorID)
+	if err != nil {
+		return []Channel{}, err
+	}
+
+	enabled := channels[:0]
+	for _, c := range channels {
+		if c.Enabled {
+			enabled = append(enabled, c)
+		}
+	}
+	return enabled, nil
+}
+
+// newSigningSecret generates the key deliveries of a webhook channel are signed with
+func newSigningSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// validateConfig checks cfg has what a channel of type needs, and clears fields the type does not use
+func validateConfig(op, typ string, cfg *Config) error {
+	invalid := func(msg string) error {
+		return &apperror.Error{
+			Kind:    apperror.InvalidInput,
+			Op:      op,
+			Message: msg,
+		}
+	}
+
+	switch typ {
+	case TypeEmail:
+		addr, err := mail.ParseAddress(cfg.Email)
+		if err != nil || addr.Address != cfg.Email {
+			return invalid("email channel needs a valid config.email")
+		}
+		*cfg = Config{Email: cfg.Email}
+
+	case TypePagerDuty:
+		if !validKey(cfg.RoutingKey) {
+			return invalid("pagerduty channel needs config.routing_key, integration key of an events api v2 integration")
+		}
+		*cfg = Config{RoutingKey: cfg.RoutingKey, Region: region(cfg.Region)}
+
+	case TypeOpsgenie:
+		if !validKey(cfg.APIKey) {
+			return invalid("opsgenie channel needs config.api_key, key of an api integration")
+		}
+		*cfg = Config{APIKey: cfg.APIKey, Region: region(cfg.Region)}
+
+	case TypeSlack, TypeDiscord, TypeTeams, TypeWebhook:
+		u, err := url.Parse(cfg.URL)
+		if err != nil || u.Host == "" {
+			return invalid(fmt.Sprintf("%s channel needs a valid config.url", typ))
+		}
+		// chat webhooks are https only, a generic one may be plain http (internal receivers)
+		if u.Scheme != "https" && (typ != TypeWebhook || u.Scheme != "http") {
+			return invalid(fmt.Sprintf("config.url of %s channel must be https", typ))
+		}
+		if typ != TypeWebhook {
+			cfg.Headers = nil
+			cfg.Secret = ""
+		}
+		cfg.Email = ""
+		cfg.RoutingKey = ""
+		cfg.APIKey = ""
+		cfg.Region = ""
+
+	default:
+		return invalid(fmt.Sprintf("unknown channel type %q", typ))
+	}
+	return nil
+}
+
+// region of a pagerduty or opsgenie account, us unless eu
+func region(r string) string {
+	if r == RegionEU {
+		return RegionEU
+	}
+	return RegionUS
+}
+
+// validKey reports whether key looks like an integration key, non empty and no spaces or control chars
+func validKey(key string) bool {
+	if key == "" || len(key) > 128 {
+		return false
+	}
+	for _, c := range key {
+		if c <= ' ' || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
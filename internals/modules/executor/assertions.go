@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/jsonpath"
+	"regexp"
+)
+
+// checkAssertions runs assertions in order on the response, and returns an error naming the first one which failed
+func checkAssertions(assertions []monitor.Assertion, header http.Header, body []byte) error {
+	var (
+		doc     any
+		docErr  error
+		decoded bool
+	)
+
+	for _, a := range assertions {
+		var ok bool
+
+		switch a.Type {
+		case monitor.AssertBodyContains:
+			ok = bytes.Contains(body, []byte(a.Value))
+
+		case monitor.AssertBodyNotContains:
+			ok = !bytes.Contains(body, []byte(a.Value))
+
+		case monitor.AssertBodyRegex:
+			re, err := regexp.Compile(a.Value)
+			if err != nil {
+				return fmt.Errorf("%s: %v", a, err)
+			}
+			ok = re.Match(body)
+
+		case monitor.AssertJSONPathEquals, monitor.AssertJSONPathExists:
+			// decode body once, only if some assertion needs it
+			if !decoded {
+				doc, docErr = jsonpath.Decode(body)
+				decoded = true
+			}
+			if docErr != nil {
+				return fmt.Errorf("%s: body is not valid json", a)
+			}
+			v, found, err := jsonpath.Lookup(doc, a.Path)
+			if err != nil {
+				return fmt.Errorf("%s: %v", a, err)
+			}
+			if a.Type == monitor.AssertJSONPathExists {
+				ok = found
+			} else {
+				ok = found && jsonpath.String(v) == a.Value
+			}
+
+		case monitor.AssertHeaderEquals:
+			ok = header.Get(a.Path) == a.Value
+
+		default:
+			return fmt.Errorf("%s: unknown assertion type", a)
+		}
+
+		if !ok {
+			return fmt.Errorf("%s: failed", a)
+		}
+	}
+
+	return nil
+}
+
+func needsBody(assertions []monitor.Assertion) bool {
+	for _, a := range assertions {
+		if a.NeedsBody() {
+			return true
+		}
+	}
+	return false
+}
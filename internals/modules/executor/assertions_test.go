@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"net/http"
+	"project-k/internals/modules/monitor"
+	"strings"
+	"testing"
+)
+
+func TestCheckAssertions(t *testing.T) {
+	body := []byte(`{"status": "ok", "version": "1.2.0", "checks": [{"name": "db", "up": true}], "latency": 12}`)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Region", "eu-west-1")
+
+	tests := []struct {
+		name       string
+		assertions []monitor.Assertion
+		body       []byte
+		wantErr    string // substring of error, empty for success
+	}{
+		{
+			name:       "no assertions",
+			assertions: nil,
+		},
+		{
+			name:       "body_contains passes",
+			assertions: []monitor.Assertion{{Type: monitor.AssertBodyContains, Value: `"status": "ok"`}},
+		},
+		{
+			name:       "body_contains fails",
+			assertions: []monitor.Assertion{{Type: monitor.AssertBodyContains, Value: "degraded"}},
+			wantErr:    `body_contains "degraded": failed`,
+		},
+		{
+			name:       "body_not_contains passes",
+			assertions: []monitor.Assertion{{Type: monitor.AssertBodyNotContains, Value: "error"}},
+		},
+		{
+			name:       "body_not_contains fails",
+			assertions: []monitor.Assertion{{Type: monitor.AssertBodyNotContains, Value: "ok"}},
+			wantErr:    "body_not_contains",
+		},
+		{
+			name:       "body_regex passes",
+			assertions: []monitor.Assertion{{Type: monitor.AssertBodyRegex, Value: `"version": "1\.\d+\.\d+"`}},
+		},
+		{
+			name:       "body_regex fails",
+			assertions: []monitor.Assertion{{Type: monitor.AssertBodyRegex, Value: `"version": "2\.`}},
+			wantErr:    "body_regex",
+		},
+		{
+			name:       "body_regex invalid",
+			assertions: []monitor.Assertion{{Type: monitor.AssertBodyRegex, Value: `(`}},
+			wantErr:    "missing closing )",
+		},
+		{
+			name:       "json_path_equals string",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathEquals, Path: "$.status", Value: "ok"}},
+		},
+		{
+			name:       "json_path_equals nested bool",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathEquals, Path: "$.checks[0].up", Value: "true"}},
+		},
+		{
+			name:       "json_path_equals number",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathEquals, Path: "latency", Value: "12"}},
+		},
+		{
+			name:       "json_path_equals mismatch",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathEquals, Path: "$.status", Value: "down"}},
+			wantErr:    `json_path_equals $.status == "down": failed`,
+		},
+		{
+			name:       "json_path_equals missing path",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathEquals, Path: "$.checks[3].name", Value: "db"}},
+			wantErr:    "failed",
+		},
+		{
+			name:       "json_path_exists passes",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathExists, Path: "$.checks[0].name"}},
+		},
+		{
+			name:       "json_path_exists fails",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathExists, Path: "$.uptime"}},
+			wantErr:    "json_path_exists $.uptime: failed",
+		},
+		{
+			name:       "json path on non json body",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathExists, Path: "$.status"}},
+			body:       []byte("<html>ok</html>"),
+			wantErr:    "body is not valid json",
+		},
+		{
+			name:       "json path bad syntax",
+			assertions: []monitor.Assertion{{Type: monitor.AssertJSONPathExists, Path: "$.checks["}},
+			wantErr:    "invalid json path",
+		},
+		{
+			name:       "header_equals passes, name is case insensitive",
+			assertions: []monitor.Assertion{{Type: monitor.AssertHeaderEquals, Path: "x-region", Value: "eu-west-1"}},
+		},
+		{
+			name:       "header_equals fails",
+			assertions: []monitor.Assertion{{Type: monitor.AssertHeaderEquals, Path: "X-Region", Value: "us-east-1"}},
+			wantErr:    "header_equals",
+		},
+		{
+			name:       "header_equals missing header",
+			assertions: []monitor.Assertion{{Type: monitor.AssertHeaderEquals, Path: "X-Missing", Value: "1"}},
+			wantErr:    "header_equals",
+		},
+		{
+			name:       "unknown type",
+			assertions: []monitor.Assertion{{Type: "status_equals", Value: "200"}},
+			wantErr:    "unknown assertion type",
+		},
+		{
+			name: "first failing assertion is reported",
+			assertions: []monitor.Assertion{
+				{Type: monitor.AssertBodyContains, Value: "ok"},
+				{Type: monitor.AssertJSONPathEquals, Path: "$.version", Value: "1.3.0"},
+				{Type: monitor.AssertBodyContains, Value: "missing"},
+			},
+			wantErr: `json_path_equals $.version == "1.3.0"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := body
+			if tt.body != nil {
+				b = tt.body
+			}
+			err := checkAssertions(tt.assertions, header, b)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkAssertions() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkAssertions() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNeedsBody(t *testing.T) {
+	if needsBody([]monitor.Assertion{{Type: monitor.AssertHeaderEquals, Path: "X-A", Value: "1"}}) {
+		t.Error("needsBody() = true for header only assertions")
+	}
+	if !needsBody([]monitor.Assertion{
+		{Type: monitor.AssertHeaderEquals, Path: "X-A", Value: "1"},
+		{Type: monitor.AssertJSONPathExists, Path: "$.a"},
+	}) {
+		t.Error("needsBody() = false with a json path assertion")
+	}
+}
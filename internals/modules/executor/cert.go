@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// inspectCert reads the leaf certificate of a TLS connection, and verifies its chain for serverName.
+// alertDays is the expiry alert window of monitor (0 disables it)
+func inspectCert(state *tls.ConnectionState, serverName string, alertDays int, now time.Time) *CertInfo {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	leaf := state.PeerCertificates[0]
+
+	info := &CertInfo{
+		Subject:  nameOf(leaf.Subject),
+		Issuer:   nameOf(leaf.Issuer),
+		DNSNames: leaf.DNSNames,
+		NotAfter: leaf.NotAfter,
+		Expiring: alertDays > 0 && leaf.NotAfter.Sub(now) <= time.Duration(alertDays)*24*time.Hour,
+	}
+
+	// chain is already verified by the client, unless verification was skipped
+	if len(state.VerifiedChains) > 0 {
+		info.ChainValid = true
+		return info
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	info.ChainValid = err == nil
+	if err != nil {
+		info.ChainError = err.Error()
+	}
+
+	return info
+}
+
+// certFromError reads the certificate of a handshake which failed verification (expired, unknown authority, wrong host),
+// so its details are reported exactly when it is broken. nil if err is not a verification error.
+// rawURL is the url checked, url named by err wins (ex: a redirect target)
+func certFromError(err error, rawURL string, alertDays int, now time.Time) *CertInfo {
+	var certErr *tls.CertificateVerificationError
+	if !errors.As(err, &certErr) || len(certErr.UnverifiedCertificates) == 0 {
+		return nil
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		rawURL = urlErr.URL
+	}
+	var host string
+	if u, err := url.Parse(rawURL); err == nil {
+		host = u.Hostname()
+	}
+
+	info := inspectCert(&tls.ConnectionState{PeerCertificates: certErr.UnverifiedCertificates}, host, alertDays, now)
+	// handshake is the verdict, even if chain verifies here (roots of client may differ)
+	info.ChainValid = false
+	if info.ChainError == "" {
+		info.ChainError = certErr.Err.Error()
+	}
+	return info
+}
+
+// nameOf prefers common name, and falls back to full distinguished name
+func nameOf(n pkix.Name) string {
+	if n.CommonName != "" {
+		return n.CommonName
+	}
+	return n.String()
+}
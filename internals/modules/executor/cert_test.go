@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCertFromError(t *testing.T) {
+	// cert of test server is signed by an authority client does not trust
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // rejected handshake is logged by server
+	srv.StartTLS()
+	defer srv.Close()
+
+	_, err := http.Get(srv.URL)
+	if err == nil {
+		t.Fatal("request to untrusted server succeeded")
+	}
+	if reason, _ := classifyError(err); reason != "CERT_INVALID" {
+		t.Fatalf("classifyError() = %s, want CERT_INVALID", reason)
+	}
+
+	leaf := srv.Certificate()
+	info := certFromError(err, srv.URL, 30, leaf.NotAfter.Add(-10*24*time.Hour))
+	if info == nil {
+		t.Fatal("certFromError() = nil, want cert of failed handshake")
+	}
+	if info.ChainValid || info.ChainError == "" {
+		t.Errorf("ChainValid = %v, ChainError = %q, want invalid chain with error", info.ChainValid, info.ChainError)
+	}
+	if !info.NotAfter.Equal(leaf.NotAfter) || info.Issuer == "" {
+		t.Errorf("cert = %+v, want NotAfter %v and an issuer", info, leaf.NotAfter)
+	}
+	if !info.Expiring {
+		t.Error("Expiring = false, cert expires within alert window")
+	}
+
+	if certFromError(context.DeadlineExceeded, srv.URL, 30, time.Now()) != nil {
+		t.Error("certFromError() of a non certificate error is not nil")
+	}
+}
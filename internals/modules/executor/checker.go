@@ -0,0 +1,12 @@
+package executor
+
+import (
+	"context"
+	"project-k/internals/modules/monitor"
+)
+
+// Checker runs a single check of a monitor type, ctx carries the monitor's timeout.
+// Checkers never return errors, failures are reported in result with a Reason
+type Checker interface {
+	Check(ctx context.Context, monitor monitor.Monitor) HTTPResult
+}
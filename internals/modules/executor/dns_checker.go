@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"project-k/internals/modules/monitor"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dnsChecker resolves a name through the monitor's resolver and compares answers with expected values
+type dnsChecker struct{}
+
+func newDNSChecker() *dnsChecker {
+	return &dnsChecker{}
+}
+
+func (c *dnsChecker) Check(ctx context.Context, m monitor.Monitor) HTTPResult {
+	opts := m.Options.DNS
+	name, err := monitor.DNSName(m.Url)
+	if err == nil && opts == nil {
+		err = errors.New("dns options missing")
+	}
+	if err != nil {
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	resolver, err := c.resolver(opts.Resolver)
+	if err != nil {
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	answers, err := lookup(ctx, resolver, opts.RecordType, name)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		reason, isRetryable := classifyDNSError(err)
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			LatencyMs: latency,
+			Timings:   PhaseTimings{DNSMs: latency},
+			Reason:    reason,
+			Detail:    err.Error(),
+			Retryable: isRetryable,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	result := HTTPResult{
+		MonitorID: m.ID,
+		LatencyMs: latency,
+		Timings:   PhaseTimings{DNSMs: latency},
+		Success:   latency <= int64(m.LatencyThresholdMs),
+		Retryable: false,
+		CheckedAt: time.Now(),
+	}
+
+	// every expected value must be in answers
+	for _, want := range opts.Expected {
+		if !slices.Contains(answers, normalizeAnswer(opts.RecordType, want)) {
+			result.Success = false
+			result.Reason = "DNS_MISMATCH"
+			result.Detail = fmt.Sprintf("%s %s: expected %q in answers %q", opts.RecordType, name, want, answers)
+			break
+		}
+	}
+
+	return result
+}
+
+// resolver returns a resolver which sends every query to addr, or the system resolver when addr is empty
+func (c *dnsChecker) resolver(addr string) (*net.Resolver, error) {
+	if addr == "" {
+		return net.DefaultResolver, nil
+	}
+	return customResolver(addr)
+}
+
+// lookup resolves name for record type, and returns normalized answers
+func lookup(ctx context.Context, r *net.Resolver, recordType, name string) ([]string, error) {
+	var answers []string
+
+	switch recordType {
+	case monitor.RecordA, monitor.RecordAAAA:
+		network := "ip4"
+		if recordType == monitor.RecordAAAA {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case monitor.RecordCNAME:
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case monitor.RecordMX:
+		mxs, err := r.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			answers = append(answers, mx.Host)
+		}
+	case monitor.RecordTXT:
+		txts, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return txts, nil // TXT values are compared as they are
+	case monitor.RecordNS:
+		nss, err := r.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			answers = append(answers, ns.Host)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+
+	for i := range answers {
+		answers[i] = normalizeAnswer(recordType, answers[i])
+	}
+	return answers, nil
+}
+
+// normalizeAnswer puts answers and expected values in the same form, ips in canonical form, names lower case without trailing dot
+func normalizeAnswer(recordType, v string) string {
+	switch recordType {
+	case monitor.RecordTXT:
+		return v
+	case monitor.RecordA, monitor.RecordAAAA:
+		if ip := net.ParseIP(strings.TrimSpace(v)); ip != nil {
+			return ip.String()
+		}
+	}
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(v)), ".")
+}
+
+// classifyDNSError is classifyError for dns monitors, here a failed lookup is the thing being monitored,
+// so it must go through the incident flow instead of stopping the monitor (DNS_FAILURE is terminal)
+func classifyDNSError(err error) (string, bool) {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			return "DNS_NXDOMAIN", false
+		case dnsErr.IsTimeout:
+			return "DNS_TIMEOUT", true
+		default:
+			return "DNS_ERROR", true
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "DNS_TIMEOUT", true
+	}
+	return "DNS_ERROR", true
+}
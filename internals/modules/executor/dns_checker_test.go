@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"project-k/internals/modules/monitor"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsStandIn is an in process udp dns server answering example.test from zone, broken.example.test
+// gets SERVFAIL and other names NXDOMAIN, it returns address of the server
+func dnsStandIn(t *testing.T, zone map[dnsmessage.Type][]dnsmessage.Resource) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var p dnsmessage.Parser
+			h, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			q, err := p.Question()
+			if err != nil {
+				continue
+			}
+
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: h.ID, Response: true, Authoritative: true, RecursionAvailable: true},
+				Questions: []dnsmessage.Question{q},
+			}
+			switch q.Name.String() {
+			case "example.test.":
+				for _, r := range zone[q.Type] {
+					r.Header.Name, r.Header.Class, r.Header.TTL = q.Name, dnsmessage.ClassINET, 60
+					resp.Answers = append(resp.Answers, r)
+				}
+			case "broken.example.test.":
+				resp.Header.RCode = dnsmessage.RCodeServerFailure
+			default:
+				resp.Header.RCode = dnsmessage.RCodeNameError
+			}
+			out, err := resp.Pack()
+			if err != nil {
+				continue
+			}
+			pc.WriteTo(out, addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestDNSChecker(t *testing.T) {
+	resolver := dnsStandIn(t, map[dnsmessage.Type][]dnsmessage.Resource{
+		dnsmessage.TypeA: {
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeA}, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}},
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeA}, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 2}}},
+		},
+		dnsmessage.TypeAAAA: {
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeAAAA}, Body: &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}}},
+		},
+		dnsmessage.TypeMX: {
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeMX}, Body: &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mx1.example.test.")}},
+		},
+		dnsmessage.TypeTXT: {
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeTXT}, Body: &dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}}},
+		},
+		dnsmessage.TypeNS: {
+			{Header: dnsmessage.ResourceHeader{Type: dnsmessage.TypeNS}, Body: &dnsmessage.NSResource{NS: dnsmessage.MustNewName("ns1.example.test.")}},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		host       string
+		recordType string
+		expected   []string
+		success    bool
+		reason     string
+		retryable  bool
+	}{
+		{name: "A answers", recordType: monitor.RecordA, success: true},
+		{name: "A expected in answers", recordType: monitor.RecordA, expected: []string{"192.0.2.2", " 192.0.2.1 "}, success: true},
+		{name: "A missing", recordType: monitor.RecordA, expected: []string{"192.0.2.1", "192.0.2.9"}, reason: "DNS_MISMATCH"},
+		{name: "AAAA in other form", recordType: monitor.RecordAAAA, expected: []string{"2001:0db8:0000:0000:0000:0000:0000:0001"}, success: true},
+		{name: "MX case and trailing dot", recordType: monitor.RecordMX, expected: []string{"MX1.Example.Test."}, success: true},
+		{name: "NS", recordType: monitor.RecordNS, expected: []string{"ns1.example.test"}, success: true},
+		{name: "TXT exact", recordType: monitor.RecordTXT, expected: []string{"v=spf1 -all"}, success: true},
+		{name: "TXT is case sensitive", recordType: monitor.RecordTXT, expected: []string{"V=SPF1 -ALL"}, reason: "DNS_MISMATCH"},
+		{name: "nxdomain", host: "gone.example.test", recordType: monitor.RecordA, reason: "DNS_NXDOMAIN"},
+		{name: "servfail", host: "broken.example.test", recordType: monitor.RecordA, reason: "DNS_ERROR", retryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host := tt.host
+			if host == "" {
+				host = "example.test"
+			}
+			m := monitor.Monitor{
+				Type:               monitor.TypeDNS,
+				Url:                "dns://" + host + ".",
+				LatencyThresholdMs: 1000,
+				Options:            monitor.Options{DNS: &monitor.DNSOptions{RecordType: tt.recordType, Resolver: resolver, Expected: tt.expected}},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			res := newDNSChecker().Check(ctx, m)
+			if res.Success != tt.success || res.Reason != tt.reason || res.Retryable != tt.retryable {
+				t.Errorf("success, reason, retryable = %v, %q, %v, want %v, %q, %v (%s)",
+					res.Success, res.Reason, res.Retryable, tt.success, tt.reason, tt.retryable, res.Detail)
+			}
+		})
+	}
+}
+
+func TestClassifyDNSError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		reason    string
+		retryable bool
+	}{
+		{"not found", &net.DNSError{Err: "no such host", IsNotFound: true}, "DNS_NXDOMAIN", false},
+		{"timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, "DNS_TIMEOUT", true},
+		{"server failure", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, "DNS_ERROR", true},
+		{"context deadline", context.DeadlineExceeded, "DNS_TIMEOUT", true},
+		{"other", errors.New("connection refused"), "DNS_ERROR", true},
+	}
+	for _, tt := range tests {
+		reason, retryable := classifyDNSError(tt.err)
+		if reason != tt.reason || retryable != tt.retryable {
+			t.Errorf("%s: classifyDNSError = %s, %v, want %s, %v", tt.name, reason, retryable, tt.reason, tt.retryable)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// max body bytes kept as evidence of a failed check
+const evidenceBodyBytes = 8 << 10 // 8KB
+
+// response headers kept as evidence, rest are dropped (they may carry cookies or tokens)
+var evidenceHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Server",
+	"Location",
+	"Retry-After",
+	"WWW-Authenticate",
+	"Cache-Control",
+	"Via",
+	"X-Request-Id",
+	"CF-Ray",
+}
+
+// Evidence is what server answered (or the error we got) on a failed check,
+// stored with the incident so user can see why it went down
+type Evidence struct {
+	StatusLine    string            `json:"status_line,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          string            `json:"body,omitempty"`
+	BodyTruncated bool              `json:"body_truncated,omitempty"`
+	RemoteIP      string            `json:"remote_ip,omitempty"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// responseEvidence captures status line, selected headers and first evidenceBodyBytes of body of resp
+func responseEvidence(resp *http.Response, body []byte, remoteIP string) *Evidence {
+	ev := &Evidence{
+		StatusLine: resp.Proto + " " + resp.Status,
+		RemoteIP:   remoteIP,
+	}
+	for _, name := range evidenceHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			if ev.Headers == nil {
+				ev.Headers = make(map[string]string)
+			}
+			ev.Headers[name] = v
+		}
+	}
+
+	if len(body) > evidenceBodyBytes {
+		body = body[:evidenceBodyBytes]
+		ev.BodyTruncated = true
+	}
+	// body is stored as text, drop invalid bytes (binary body or a rune cut at the limit)
+	ev.Body = string(bytes.ToValidUTF8(body, nil))
+
+	return ev
+}
+
+// errorEvidence is evidence of a check which failed before a response
+func errorEvidence(err error, remoteIP string) *Evidence {
+	return &Evidence{
+		RemoteIP: remoteIP,
+		Error:    err.Error(),
+	}
+}
+
+// body is left out of logs, it can be large and may have user's data
+func (ev *Evidence) MarshalZerologObject(e *zerolog.Event) {
+	e.
+		Str("status_line", ev.StatusLine).
+		Str("remote_ip", ev.RemoteIP).
+		Str("error", ev.Error).
+		Int("body_bytes", len(ev.Body))
+}
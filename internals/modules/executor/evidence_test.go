@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/httpclient"
+
+	"github.com/rs/zerolog"
+)
+
+func TestResponseEvidence(t *testing.T) {
+	header := http.Header{
+		"Content-Type":  {"text/html"},
+		"Server":        {"nginx"},
+		"Retry-After":   {"120"},
+		"Set-Cookie":    {"session=s3cret"},
+		"Authorization": {"Bearer s3cret"},
+		"X-Api-Key":     {"s3cret"},
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		want      string
+		truncated bool
+	}{
+		{name: "empty body", body: ""},
+		{name: "small body", body: "upstream timed out", want: "upstream timed out"},
+		{name: "body at limit", body: strings.Repeat("a", evidenceBodyBytes), want: strings.Repeat("a", evidenceBodyBytes)},
+		{name: "body over limit", body: strings.Repeat("a", evidenceBodyBytes+1), want: strings.Repeat("a", evidenceBodyBytes), truncated: true},
+		{
+			// 3 byte rune cut at the limit is dropped, not stored half
+			name: "rune cut at limit", body: strings.Repeat("a", evidenceBodyBytes-1) + "€",
+			want: strings.Repeat("a", evidenceBodyBytes-1), truncated: true,
+		},
+		{name: "binary body", body: "ok\xff\xfeok", want: "okok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Proto: "HTTP/1.1", Status: "503 Service Unavailable", StatusCode: 503, Header: header}
+			ev := responseEvidence(resp, []byte(tt.body), "192.0.2.1")
+
+			if ev.StatusLine != "HTTP/1.1 503 Service Unavailable" || ev.RemoteIP != "192.0.2.1" {
+				t.Errorf("status line, remote ip = %q, %q", ev.StatusLine, ev.RemoteIP)
+			}
+			if ev.Body != tt.want || ev.BodyTruncated != tt.truncated {
+				t.Errorf("body = %d bytes (truncated %v), want %d bytes (truncated %v)", len(ev.Body), ev.BodyTruncated, len(tt.want), tt.truncated)
+			}
+			if !utf8.ValidString(ev.Body) {
+				t.Error("body is not valid utf-8")
+			}
+			want := map[string]string{"Content-Type": "text/html", "Server": "nginx", "Retry-After": "120"}
+			if len(ev.Headers) != len(want) {
+				t.Errorf("headers = %v, want %v", ev.Headers, want)
+			}
+			for k, v := range want {
+				if ev.Headers[k] != v {
+					t.Errorf("header %s = %q, want %q", k, ev.Headers[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerEvidence(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=s3cret")
+		w.Header().Set("Server", "stand-in")
+		if r.URL.Path == "/ok" {
+			w.Write([]byte("ok"))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(strings.Repeat("x", 20<<10)))
+	}))
+	defer srv.Close()
+
+	nop := zerolog.Nop()
+	checker := newHTTPChecker(httpclient.NewHttpClient(), "", &nop)
+	check := func(url string) HTTPResult {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return checker.Check(ctx, monitor.Monitor{Type: monitor.TypeHTTP, Url: url, ExpectedStatus: "200", LatencyThresholdMs: 5000})
+	}
+
+	if res := check(srv.URL + "/ok"); !res.Success || res.Evidence != nil {
+		t.Errorf("passing check: success %v, evidence %+v", res.Success, res.Evidence)
+	}
+
+	res := check(srv.URL + "/fail")
+	ev := res.Evidence
+	if res.Success || ev == nil {
+		t.Fatalf("failing check: success %v, evidence %+v", res.Success, ev)
+	}
+	if ev.StatusLine != "HTTP/1.1 500 Internal Server Error" || ev.RemoteIP != "127.0.0.1" {
+		t.Errorf("status line, remote ip = %q, %q", ev.StatusLine, ev.RemoteIP)
+	}
+	if len(ev.Body) != evidenceBodyBytes || !ev.BodyTruncated {
+		t.Errorf("body = %d bytes (truncated %v), want %d (truncated)", len(ev.Body), ev.BodyTruncated, evidenceBodyBytes)
+	}
+	if _, ok := ev.Headers["Set-Cookie"]; ok || ev.Headers["Server"] != "stand-in" {
+		t.Errorf("headers = %v", ev.Headers)
+	}
+
+	// failed before a response, evidence is the error
+	srv.Close()
+	res = check(srv.URL + "/fail")
+	if res.Evidence == nil || res.Evidence.Error == "" || res.Evidence.StatusLine != "" {
+		t.Errorf("connection error evidence = %+v", res.Evidence)
+	}
+}
@@ -2,6 +2,8 @@ package executor
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"net"
 	"net/http"
@@ -9,6 +11,7 @@ import (
 	"project-k/internals/modules/monitor"
 	"project-k/internals/modules/scheduler"
 	"project-k/pkg/apperror"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +19,9 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// used when a monitor has no timeout set (ex: old cached monitors)
+const defaultCheckTimeout = 15 * time.Second
+
 type MonitorService interface {
 	LoadMonitor(context.Context, uuid.UUID) (monitor.Monitor, error)
 	ScheduleMonitor(context.Context, uuid.UUID, int32, string)
@@ -35,9 +41,14 @@ type Executor struct {
 	monitorSvc MonitorService
 
 	// http goroutines config
-	httpSem    chan struct{}
-	httpWg     sync.WaitGroup
-	httpClient *http.Client
+	httpSem chan struct{}
+	httpWg  sync.WaitGroup
+
+	// checkers by monitor type
+	checkers map[string]Checker
+
+	// region checks are run from, recorded with each result
+	region string
 
 	// misc
 	logger *zerolog.Logger
@@ -50,9 +61,12 @@ func NewExecutor(
 	resultChan chan HTTPResult,
 	monitorSvc MonitorService,
 	httpClient *http.Client,
+	heartbeatStore HeartbeatStore,
 	logger *zerolog.Logger,
 ) *Executor {
 
+	httpChecker := newHTTPChecker(httpClient, executorConfig.Proxy, logger)
+
 	return &Executor{
 		ctx:         ctx,
 		workerCount: executorConfig.WorkerCount,
@@ -60,8 +74,17 @@ func NewExecutor(
 		resultChan:  resultChan,
 		monitorSvc:  monitorSvc,
 		httpSem:     make(chan struct{}, executorConfig.HTTPSemCount), // 5k http concurrent , specify it in config
-		httpClient:  httpClient,
-		logger:      logger,
+		checkers: map[string]Checker{
+			monitor.TypeHTTP:        httpChecker,
+			monitor.TypeTCP:         newTCPChecker(),
+			monitor.TypeDNS:         newDNSChecker(),
+			monitor.TypeGRPC:        newGRPCChecker(),
+			monitor.TypeWebSocket:   newWebSocketChecker(executorConfig.Proxy),
+			monitor.TypeTransaction: newTransactionChecker(httpChecker), // shares transports of http checker
+			monitor.TypeHeartbeat:   newHeartbeatChecker(heartbeatStore),
+		},
+		region: executorConfig.Region,
+		logger: logger,
 	}
 }
 
@@ -110,94 +133,141 @@ func (ew *Executor) startWork() {
 				ew.httpWg.Done()
 			}()
 
-			result := ew.executeHTTPCheck(monitor)
+			result := ew.runCheck(monitor)
 			ew.logger.Info().Object("http_result", result).Msg("Got HTTPResult and pushed to result channel")
 			ew.resultChan <- result
 		}()
 	}
 }
 
-// Stop waits for all workers and http gourotines to complete
-func (ew *Executor) Stop() {
-
-	ew.workerWg.Wait()
-	
-	ew.httpWg.Wait()
-}
-
-func (ew *Executor) executeHTTPCheck(monitor monitor.Monitor) HTTPResult {
-
-	start := time.Now()
+// runCheck dispatches the monitor to the checker of its type, bounded by monitor's own timeout
+func (ew *Executor) runCheck(m monitor.Monitor) HTTPResult {
+	monitorType := m.Type
+	if monitorType == "" { // monitors cached before type was added
+		monitorType = monitor.TypeHTTP
+	}
 
-	httpReqCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	var result HTTPResult
 
-	req, err := http.NewRequestWithContext(httpReqCtx, "GET", monitor.Url, nil)
-	if err != nil {
-		// this is request building error -> means url is wrong,
-		// so its clients problem, we should handle it seperately in result processor,
-		// and add this in DB/redis (so client get to know about this), and DO NOT RE-SCHEDULE IT
-		// log it as well as that we can see it
+	checker, ok := ew.checkers[monitorType]
+	if !ok {
+		// unknown type is a config problem -> DO NOT RE-SCHEDULE IT
 		ew.logger.Error().
-			Err(err).
-			Str("monitor_id", monitor.ID.String()).
-			Str("monitor_url", monitor.Url).
-			Msg("error in building request")
+			Str("monitor_id", m.ID.String()).
+			Str("monitor_type", monitorType).
+			Msg("no checker for monitor type")
 
-		return HTTPResult{
-			MonitorID: monitor.ID,
+		result = HTTPResult{
+			MonitorID: m.ID,
 			Success:   false,
-			Reason:    "INVALID_REQUEST", // check with this in result processor
+			Reason:    "INVALID_REQUEST",
+			Detail:    "unknown monitor type " + monitorType,
 			Retryable: false,
 			CheckedAt: time.Now(),
 		}
+	} else if n := m.Options.Network; n != nil && n.IPVersion == monitor.IPBoth {
+		result = checkEachIPVersion(checker, m)
+	} else {
+		result = checkWithTimeout(checker, m)
 	}
-	resp, err := ew.httpClient.Do(req)
-	latency := time.Since(start).Milliseconds()
-	if err != nil {
-		// this can be DNS err, network err, TLS err and context timeout(because of hanging request)
-		reason, isRetryable := ew.classifyError(err)
-		return HTTPResult{
-			MonitorID: monitor.ID,
-			Success:   false,
-			Reason:    reason,
-			Retryable: isRetryable,
-			CheckedAt: time.Now(),
-		}
+
+	// needed by result processor to reschedule and alert,
+	// a checker may set its own next check delay (ex: heartbeat)
+	if result.IntervalSec == 0 {
+		result.IntervalSec = m.IntervalSec
 	}
+	result.AlertEmail = m.AlertEmail
+	result.Region = ew.region
 
-	/*
-		when we retry and when not
-		-	Retry when its
-			- request building err -> its our err, log it as well as it will remain after retry as well
-			- network err/timeout -> maybe network is slow
-			- TLS timeout err
-		-	When not Retry
-			- DNS failure  -> its due to bad config -> log it
-			- retry count >= 3  // log it VERY IMPORTANT, in future put it in a seperate error channel for debugging
-	*/
-
-	defer resp.Body.Close()
-
-	success := resp.StatusCode == int(monitor.ExpectedStatus) && latency <= int64(monitor.LatencyThresholdMs)
-
-	return HTTPResult{
-		MonitorID: monitor.ID,
-		Status:    resp.StatusCode,
-		LatencyMs: latency,
-		Success:   success,
-		Reason:    "",
-		Retryable: false,
-		CheckedAt: time.Now(),
+	return result
+}
+
+// checkTimeout is monitor's timeout, a check never outlives its interval
+func checkTimeout(m monitor.Monitor) time.Duration {
+	timeout := time.Duration(m.TimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	if interval := time.Duration(m.IntervalSec) * time.Second; interval > 0 && timeout > interval {
+		timeout = interval
 	}
+	return timeout
+}
+
+// checkWithTimeout runs checker bounded by monitor's timeout
+func checkWithTimeout(checker Checker, m monitor.Monitor) HTTPResult {
+	// not derived from ew.ctx, so running checks can complete on shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout(m))
+	defer cancel()
+	return checker.Check(ctx, m)
 }
 
-func (_ *Executor) classifyError(err error) (string, bool) {
+// checkEachIPVersion runs the check pinned to ipv4 and pinned to ipv6 at the same time, under one timeout,
+// so both together take no longer than a single check. a failure is the result, ipv4 first
+func checkEachIPVersion(checker Checker, m monitor.Monitor) HTTPResult {
+	versions := []string{monitor.IPv4, monitor.IPv6}
+	results := make([]HTTPResult, len(versions))
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout(m))
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i, version := range versions {
+		network := *m.Options.Network
+		network.IPVersion = version
+		pinned := m
+		pinned.Options.Network = &network
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = checker.Check(ctx, pinned)
+		}()
+	}
+	wg.Wait()
+
+	ips := make([]string, 0, len(versions))
+	for i, result := range results {
+		if !result.Success {
+			result.Detail = versions[i] + " check failed" + detailSuffix(result.Detail)
+			return result
+		}
+		ips = append(ips, result.RemoteIP)
+	}
+	result := results[len(results)-1]
+	result.RemoteIP = strings.Join(ips, ", ")
+	return result
+}
+
+// Stop waits for all workers and http gourotines to complete
+func (ew *Executor) Stop() {
+
+	ew.workerWg.Wait()
+	
+	ew.httpWg.Wait()
+}
+
+// classifyError maps a check error to a failure reason, and tells if it is worth retrying
+func classifyError(err error) (string, bool) {
 
 	if errors.Is(err, context.DeadlineExceeded) {
 		return "TIMEOUT", true
 	}
 
+	// redirect loop or longer chain than allowed, retry will end the same
+	if errors.Is(err, errTooManyRedirects) {
+		return "TOO_MANY_REDIRECTS", false
+	}
+
+	// certificate could not be verified (expired, unknown authority, wrong host), retry will not help
+	var certErr *tls.CertificateVerificationError
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &certErr) || errors.As(err, &hostErr) || errors.As(err, &authErr) || errors.As(err, &invalidErr) {
+		return "CERT_INVALID", false
+	}
+
 	var dnsErr *net.DNSError
 	if errors.As(err, &dnsErr) {
 		return "DNS_FAILURE", false
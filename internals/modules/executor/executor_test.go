@@ -0,0 +1,120 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"project-k/internals/modules/monitor"
+)
+
+// pinnedChecker answers per ip version, it waits for checks of both versions to be running before answering,
+// so checks run one after another only end at their timeout
+type pinnedChecker struct {
+	mu        sync.Mutex
+	started   map[string]bool
+	deadlines map[string]time.Time
+	both      chan struct{}
+	results   map[string]HTTPResult
+}
+
+func newPinnedChecker(results map[string]HTTPResult) *pinnedChecker {
+	return &pinnedChecker{
+		started:   make(map[string]bool),
+		deadlines: make(map[string]time.Time),
+		both:      make(chan struct{}),
+		results:   results,
+	}
+}
+
+func (c *pinnedChecker) Check(ctx context.Context, m monitor.Monitor) HTTPResult {
+	version := m.Options.Network.IPVersion
+	deadline, _ := ctx.Deadline()
+
+	c.mu.Lock()
+	c.started[version] = true
+	c.deadlines[version] = deadline
+	if len(c.started) == 2 {
+		close(c.both)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-c.both:
+		return c.results[version]
+	case <-ctx.Done():
+		return HTTPResult{Reason: "TIMEOUT", Detail: "other ip version did not run alongside"}
+	}
+}
+
+func TestCheckEachIPVersion(t *testing.T) {
+	ok4 := HTTPResult{Success: true, RemoteIP: "93.184.216.34"}
+	ok6 := HTTPResult{Success: true, RemoteIP: "2606:2800:220:1::"}
+	fail := func(reason string) HTTPResult { return HTTPResult{Reason: reason, Detail: "connection refused"} }
+
+	tests := []struct {
+		name     string
+		results  map[string]HTTPResult
+		success  bool
+		reason   string
+		detail   string
+		remoteIP string
+	}{
+		{
+			name:     "both up",
+			results:  map[string]HTTPResult{monitor.IPv4: ok4, monitor.IPv6: ok6},
+			success:  true,
+			remoteIP: "93.184.216.34, 2606:2800:220:1::",
+		},
+		{
+			name:    "ipv6 down",
+			results: map[string]HTTPResult{monitor.IPv4: ok4, monitor.IPv6: fail("NETWORK_ERROR")},
+			reason:  "NETWORK_ERROR",
+			detail:  "ipv6 check failed: connection refused",
+		},
+		{
+			name:    "both down, ipv4 is reported",
+			results: map[string]HTTPResult{monitor.IPv4: fail("CONNECTION_REFUSED"), monitor.IPv6: fail("NETWORK_ERROR")},
+			reason:  "CONNECTION_REFUSED",
+			detail:  "ipv4 check failed: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := newPinnedChecker(tt.results)
+			m := monitor.Monitor{
+				TimeoutSec:  2,
+				IntervalSec: 1, // caps the timeout
+				Options:     monitor.Options{Network: &monitor.NetworkOptions{IPVersion: monitor.IPBoth}},
+			}
+
+			start := time.Now()
+			result := checkEachIPVersion(checker, m)
+			elapsed := time.Since(start)
+
+			if result.Success != tt.success || result.Reason != tt.reason || result.Detail != tt.detail {
+				t.Errorf("result = %v %s %q, want %v %s %q", result.Success, result.Reason, result.Detail, tt.success, tt.reason, tt.detail)
+			}
+			if tt.success && result.RemoteIP != tt.remoteIP {
+				t.Errorf("remote ip = %q, want %q", result.RemoteIP, tt.remoteIP)
+			}
+
+			// both versions share one deadline, within the interval, so together they fit in it
+			d4, d6 := checker.deadlines[monitor.IPv4], checker.deadlines[monitor.IPv6]
+			if d4.IsZero() || !d4.Equal(d6) {
+				t.Errorf("deadlines = %v and %v, want one shared deadline", d4, d6)
+			}
+			if budget := d4.Sub(start); budget > time.Second+100*time.Millisecond {
+				t.Errorf("deadline is %v after start, past the 1s interval", budget)
+			}
+			if elapsed >= time.Second {
+				t.Errorf("dual stack check took %v, want well within the interval", elapsed)
+			}
+			if m.Options.Network.IPVersion != monitor.IPBoth {
+				t.Error("network options of monitor were changed")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"project-k/internals/modules/monitor"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// grpcChecker checks grpc://host:port servers with the standard health checking protocol
+// (grpc.health.v1.Health/Check), only SERVING is a success
+type grpcChecker struct{}
+
+func newGRPCChecker() *grpcChecker {
+	return &grpcChecker{}
+}
+
+func (c *grpcChecker) Check(ctx context.Context, m monitor.Monitor) HTTPResult {
+	addr, err := monitor.TCPAddress(m.Url)
+	if err != nil {
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	var opts monitor.GRPCOptions
+	if m.Options.GRPC != nil {
+		opts = *m.Options.GRPC
+	}
+
+	creds := insecure.NewCredentials()
+	if opts.TLS {
+		host, _, _ := net.SplitHostPort(addr)
+		creds = credentials.NewTLS(&tls.Config{ServerName: host})
+	}
+
+	// connection per check, so latency includes connect (like other checkers)
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: opts.Service})
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		reason, isRetryable := classifyGRPCError(err)
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			LatencyMs: latency,
+			Reason:    reason,
+			Detail:    err.Error(),
+			Retryable: isRetryable,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	result := HTTPResult{
+		MonitorID: m.ID,
+		LatencyMs: latency,
+		Success:   latency <= int64(m.LatencyThresholdMs),
+		Retryable: false,
+		CheckedAt: time.Now(),
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		// NOT_SERVING, UNKNOWN, SERVICE_UNKNOWN
+		result.Success = false
+		result.Reason = resp.GetStatus().String()
+		if opts.Service != "" {
+			result.Detail = "service " + opts.Service
+		}
+	}
+
+	return result
+}
+
+// classifyGRPCError maps a failed health rpc to failure reason, and tells if it is retryable
+func classifyGRPCError(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return classifyError(err)
+	}
+
+	switch st.Code() {
+	case codes.DeadlineExceeded, codes.Canceled:
+		return "TIMEOUT", true
+	case codes.Unavailable: // server down, connection refused, TLS handshake failure
+		return "NETWORK_ERROR", true
+	case codes.NotFound: // health server does not know the service
+		return "SERVICE_UNKNOWN", false
+	case codes.Unimplemented: // server has no health service
+		return "HEALTH_UNIMPLEMENTED", false
+	default:
+		return "GRPC_" + grpcCodeName(st.Code()), true
+	}
+}
+
+// grpcCodeName returns code in upper snake case, ex: PERMISSION_DENIED
+func grpcCodeName(code codes.Code) string {
+	name := code.String()
+	out := make([]byte, 0, len(name)+4)
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		if ch >= 'A' && ch <= 'Z' {
+			if i > 0 {
+				out = append(out, '_')
+			}
+			out = append(out, ch)
+			continue
+		}
+		out = append(out, ch-'a'+'A')
+	}
+	return string(out)
+}
@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"project-k/internals/modules/monitor"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// grpcStandIn serves grpc on a local port, with the health service when withHealth is set,
+// server itself and "payments" are SERVING, "orders" is NOT_SERVING
+func grpcStandIn(t *testing.T, withHealth bool) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	if withHealth {
+		hs := health.NewServer()
+		hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+		hs.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+		hs.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+		healthpb.RegisterHealthServer(srv, hs)
+	}
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+	return "grpc://" + ln.Addr().String()
+}
+
+func TestGRPCChecker(t *testing.T) {
+	withHealth := grpcStandIn(t, true)
+	withoutHealth := grpcStandIn(t, false)
+
+	tests := []struct {
+		name      string
+		url       string
+		opts      *monitor.GRPCOptions
+		success   bool
+		reason    string
+		retryable bool
+	}{
+		{name: "server serving", url: withHealth, success: true},
+		{name: "service serving", url: withHealth, opts: &monitor.GRPCOptions{Service: "payments"}, success: true},
+		{name: "service not serving", url: withHealth, opts: &monitor.GRPCOptions{Service: "orders"}, reason: "NOT_SERVING"},
+		{name: "unknown service", url: withHealth, opts: &monitor.GRPCOptions{Service: "billing"}, reason: "SERVICE_UNKNOWN"},
+		{name: "no health service", url: withoutHealth, reason: "HEALTH_UNIMPLEMENTED"},
+		{name: "tls to plaintext server", url: withHealth, opts: &monitor.GRPCOptions{TLS: true}, reason: "NETWORK_ERROR", retryable: true},
+		{name: "connection refused", url: strings.Replace(closedPort(t), "tcp://", "grpc://", 1), reason: "NETWORK_ERROR", retryable: true},
+		{name: "not a grpc url", url: "grpc://example.com", reason: "INVALID_REQUEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := monitor.Monitor{Type: monitor.TypeGRPC, Url: tt.url, LatencyThresholdMs: 5000, Options: monitor.Options{GRPC: tt.opts}}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			res := newGRPCChecker().Check(ctx, m)
+			if res.Success != tt.success || res.Reason != tt.reason || res.Retryable != tt.retryable {
+				t.Errorf("success, reason, retryable = %v, %q, %v, want %v, %q, %v (%s)",
+					res.Success, res.Reason, res.Retryable, tt.success, tt.reason, tt.retryable, res.Detail)
+			}
+		})
+	}
+}
+
+func TestClassifyGRPCError(t *testing.T) {
+	tests := []struct {
+		err       error
+		reason    string
+		retryable bool
+	}{
+		{status.Error(codes.DeadlineExceeded, "deadline"), "TIMEOUT", true},
+		{status.Error(codes.Unavailable, "connection refused"), "NETWORK_ERROR", true},
+		{status.Error(codes.NotFound, "unknown service"), "SERVICE_UNKNOWN", false},
+		{status.Error(codes.Unimplemented, "no health"), "HEALTH_UNIMPLEMENTED", false},
+		{status.Error(codes.PermissionDenied, "denied"), "GRPC_PERMISSION_DENIED", true},
+		{status.Error(codes.ResourceExhausted, "slow down"), "GRPC_RESOURCE_EXHAUSTED", true},
+		{context.DeadlineExceeded, "TIMEOUT", true},
+		{errors.New("boom"), "UNKNOWN_ERROR", true},
+	}
+	for _, tt := range tests {
+		reason, retryable := classifyGRPCError(tt.err)
+		if reason != tt.reason || retryable != tt.retryable {
+			t.Errorf("classifyGRPCError(%v) = %s, %v, want %s, %v", tt.err, reason, retryable, tt.reason, tt.retryable)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"context"
+	"project-k/internals/modules/monitor"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// recheck delay of a heartbeat monitor whose ping is missing
+const heartbeatRecheckSec = 60
+
+type HeartbeatStore interface {
+	LastHeartbeat(ctx context.Context, monitorID uuid.UUID) (time.Time, error)
+}
+
+// heartbeatChecker is passive, monitored job pings us (see monitor.Service.RecordHeartbeat),
+// it only checks that last ping is within interval + grace
+type heartbeatChecker struct {
+	store HeartbeatStore
+}
+
+func newHeartbeatChecker(store HeartbeatStore) *heartbeatChecker {
+	return &heartbeatChecker{
+		store: store,
+	}
+}
+
+func (c *heartbeatChecker) Check(ctx context.Context, m monitor.Monitor) HTTPResult {
+	now := time.Now()
+
+	last, err := c.store.LastHeartbeat(ctx, m.ID)
+	if err != nil {
+		// our own store failed, not the monitored job
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			Reason:    "UNKNOWN_ERROR",
+			Detail:    err.Error(),
+			Retryable: true,
+			CheckedAt: now,
+		}
+	}
+
+	if !last.IsZero() {
+		deadline := last.Add(m.HeartbeatWindow())
+		if now.Before(deadline) {
+			// next check exactly when the ping becomes overdue
+			next := int32(deadline.Sub(now) / time.Second)
+			if next < 1 {
+				next = 1
+			}
+			return HTTPResult{
+				MonitorID:   m.ID,
+				Success:     true,
+				CheckedAt:   now,
+				IntervalSec: next,
+			}
+		}
+	}
+
+	detail := "no ping received"
+	if !last.IsZero() {
+		detail = "last ping at " + last.UTC().Format(time.RFC3339)
+	}
+
+	return HTTPResult{
+		MonitorID:   m.ID,
+		Success:     false,
+		Reason:      "HEARTBEAT_MISSED",
+		Detail:      detail,
+		Retryable:   false,
+		CheckedAt:   now,
+		IntervalSec: heartbeatRecheckSec,
+	}
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"project-k/internals/modules/monitor"
+
+	"github.com/google/uuid"
+)
+
+type heartbeatStore struct {
+	last time.Time
+	err  error
+}
+
+func (s heartbeatStore) LastHeartbeat(context.Context, uuid.UUID) (time.Time, error) {
+	return s.last, s.err
+}
+
+func TestHeartbeatChecker(t *testing.T) {
+	now := time.Now()
+	ago := func(sec float64) time.Time { return now.Add(-time.Duration(sec * float64(time.Second))) }
+
+	// window is 60s interval + 30s grace
+	tests := []struct {
+		name      string
+		store     heartbeatStore
+		success   bool
+		reason    string
+		retryable bool
+		next      int32 // seconds till next check, checked with a second of slack
+		detail    string
+	}{
+		{name: "never pinged", reason: "HEARTBEAT_MISSED", next: heartbeatRecheckSec, detail: "no ping received"},
+		{name: "pinged recently", store: heartbeatStore{last: ago(10)}, success: true, next: 80},
+		{name: "late but within grace", store: heartbeatStore{last: ago(75)}, success: true, next: 15},
+		{name: "just before deadline", store: heartbeatStore{last: ago(89.8)}, success: true, next: 1},
+		{name: "past grace", store: heartbeatStore{last: ago(91)}, reason: "HEARTBEAT_MISSED", next: heartbeatRecheckSec, detail: "last ping at "},
+		{name: "store error", store: heartbeatStore{err: errors.New("redis down")}, reason: "UNKNOWN_ERROR", retryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := monitor.Monitor{
+				ID:          uuid.New(),
+				Type:        monitor.TypeHeartbeat,
+				IntervalSec: 60,
+				Options:     monitor.Options{Heartbeat: &monitor.HeartbeatOptions{GraceSec: 30}},
+			}
+
+			res := newHeartbeatChecker(tt.store).Check(context.Background(), m)
+			if res.Success != tt.success || res.Reason != tt.reason || res.Retryable != tt.retryable {
+				t.Errorf("success, reason, retryable = %v, %q, %v, want %v, %q, %v",
+					res.Success, res.Reason, res.Retryable, tt.success, tt.reason, tt.retryable)
+			}
+			if res.IntervalSec < tt.next-1 || res.IntervalSec > tt.next {
+				t.Errorf("next check in %ds, want %ds", res.IntervalSec, tt.next)
+			}
+			if !strings.HasPrefix(res.Detail, tt.detail) {
+				t.Errorf("detail = %q, want prefix %q", res.Detail, tt.detail)
+			}
+		})
+	}
+}
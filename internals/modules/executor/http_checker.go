@@ -0,0 +1,297 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"project-k/internals/modules/monitor"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// max body bytes read from a response, rest is discarded with the connection
+const maxBodyBytes = 1 << 20 // 1MB
+
+// returned from CheckRedirect when monitor's max hops are exceeded
+var errTooManyRedirects = errors.New("too many redirects")
+
+// httpChecker checks http monitors, with the shared http client (or own client of proxied and mtls monitors)
+type httpChecker struct {
+	clients *transportCache
+	logger  *zerolog.Logger
+}
+
+func newHTTPChecker(httpClient *http.Client, defaultProxy string, logger *zerolog.Logger) *httpChecker {
+	return &httpChecker{
+		clients: newTransportCache(httpClient, defaultProxy),
+		logger:  logger,
+	}
+}
+
+func (c *httpChecker) Check(ctx context.Context, monitor monitor.Monitor) HTTPResult {
+	httpClient, err := c.clients.clientFor(&monitor)
+	if err != nil {
+		// bad client certificate is a config problem -> DO NOT RE-SCHEDULE IT
+		return HTTPResult{
+			MonitorID: monitor.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	result, _ := c.do(ctx, httpClient, &monitor, checkRequest{
+		Method:         monitor.Method,
+		URL:            monitor.Url,
+		Headers:        monitor.Headers,
+		Body:           monitor.Body,
+		ExpectedStatus: monitor.ExpectedStatus,
+		Assertions:     monitor.Assertions,
+	})
+	return result
+}
+
+// checkRequest is a request of a check, http monitors send one, transaction monitors one per step
+type checkRequest struct {
+	Method         string
+	URL            string
+	Headers        map[string]string
+	Body           string
+	ExpectedStatus string
+	Assertions     []monitor.Assertion
+	// body is needed by caller (ex: extraction), read it even if no assertion needs it
+	KeepBody bool
+}
+
+// checkResponse is what caller may need of a response, after its body is closed
+type checkResponse struct {
+	Header  http.Header
+	Cookies []*http.Cookie
+	Body    []byte // nil if body was not kept
+}
+
+// do sends req with redirect policy and auth of monitor, and checks the response,
+// response is nil if request failed before a response
+func (c *httpChecker) do(ctx context.Context, httpClient *http.Client, monitor *monitor.Monitor, cr checkRequest) (HTTPResult, *checkResponse) {
+
+	statusMatcher, err := parseExpectedStatus(cr.ExpectedStatus)
+	if err != nil {
+		// bad spec is a config problem, like a bad url -> DO NOT RE-SCHEDULE IT
+		c.logger.Error().
+			Err(err).
+			Str("monitor_id", monitor.ID.String()).
+			Str("expected_status", cr.ExpectedStatus).
+			Msg("error in parsing expected status")
+
+		return HTTPResult{
+			MonitorID: monitor.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}, nil
+	}
+
+	// trace every phase of request, so we can tell where the time went (DNS, connect, TLS, server, download)
+	tracer := &phaseTracer{}
+	httpReqCtx := httptrace.WithClientTrace(ctx, tracer.clientTrace())
+
+	start := time.Now()
+
+	req, err := newCheckRequest(httpReqCtx, &cr, monitor.Auth)
+	if err != nil {
+		// this is request building error -> means url is wrong,
+		// so its clients problem, we should handle it seperately in result processor,
+		// and add this in DB/redis (so client get to know about this), and DO NOT RE-SCHEDULE IT
+		// log it as well as that we can see it
+		c.logger.Error().
+			Err(err).
+			Str("monitor_id", monitor.ID.String()).
+			Str("monitor_url", redactURL(cr.URL)).
+			Msg("error in building request")
+
+		return HTTPResult{
+			MonitorID: monitor.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST", // check with this in result processor
+			Evidence:  errorEvidence(err, ""),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}, nil
+	}
+
+	// redirect policy is per monitor, so each check uses a copy of shared client (transport is still shared)
+	policy := monitor.Options.RedirectPolicy()
+	var redirects RedirectChain
+	client := *httpClient
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if !policy.Follow {
+			return http.ErrUseLastResponse
+		}
+		redirects = append(redirects, RedirectHop{URL: via[len(via)-1].URL.Redacted(), Status: next.Response.StatusCode})
+		if len(via) > policy.MaxHops {
+			return errTooManyRedirects
+		}
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		// this can be DNS err, network err, TLS err, redirect err and context timeout(because of hanging request)
+		reason, isRetryable := classifyError(err)
+		result := HTTPResult{
+			MonitorID: monitor.ID,
+			Success:   false,
+			LatencyMs: latency,
+			RemoteIP:  tracer.remoteIP(),
+			Timings:   tracer.timings(time.Time{}),
+			Redirects: redirects,
+			Evidence:  errorEvidence(err, tracer.remoteIP()),
+			Reason:    reason,
+			Retryable: isRetryable,
+			CheckedAt: time.Now(),
+		}
+		if errors.Is(err, errTooManyRedirects) {
+			result.Detail = fmt.Sprintf("stopped after %d redirects", policy.MaxHops)
+		}
+		result.Cert = certFromError(err, req.URL.String(), monitor.Options.CertExpiryAlertDays(), result.CheckedAt)
+		return result, nil
+	}
+
+	/*
+		when we retry and when not
+		-	Retry when its
+			- request building err -> its our err, log it as well as it will remain after retry as well
+			- network err/timeout -> maybe network is slow
+			- TLS timeout err
+		-	When not Retry
+			- DNS failure  -> its due to bad config -> log it
+			- retry count >= 3  // log it VERY IMPORTANT, in future put it in a seperate error channel for debugging
+	*/
+
+	defer resp.Body.Close()
+
+	// read the body (capped) to measure download time, and so the connection can be reused,
+	// keep it in memory only when assertions need it, else only its head (evidence if check fails)
+	var body, head []byte
+	var readErr error
+	if cr.KeepBody || needsBody(cr.Assertions) {
+		body, readErr = io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		head = body
+	} else {
+		head, readErr = io.ReadAll(io.LimitReader(resp.Body, evidenceBodyBytes+1))
+		if readErr == nil {
+			_, readErr = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes-int64(len(head))))
+		}
+	}
+	timings := tracer.timings(time.Now())
+
+	result := HTTPResult{
+		MonitorID: monitor.ID,
+		Status:    resp.StatusCode,
+		LatencyMs: latency,
+		RemoteIP:  tracer.remoteIP(),
+		Timings:   timings,
+		Success:   statusMatcher.Match(resp.StatusCode) && latency <= int64(monitor.LatencyThresholdMs),
+		Reason:    "",
+		Retryable: false,
+		CheckedAt: time.Now(),
+	}
+	if resp.TLS != nil {
+		result.Cert = inspectCert(resp.TLS, resp.Request.URL.Hostname(), monitor.Options.CertExpiryAlertDays(), result.CheckedAt)
+	}
+
+	finalURL := resp.Request.URL.String()
+	if len(redirects) > 0 {
+		result.Redirects = append(redirects, RedirectHop{URL: resp.Request.URL.Redacted(), Status: resp.StatusCode})
+	}
+	if result.Success && policy.ExpectFinalURL != "" && finalURL != policy.ExpectFinalURL {
+		result.Success = false
+		result.Reason = "REDIRECT_MISMATCH"
+		result.Detail = fmt.Sprintf("ended at %s, expected %s", resp.Request.URL.Redacted(), redactURL(policy.ExpectFinalURL))
+	}
+	checked := &checkResponse{Header: resp.Header, Cookies: resp.Cookies(), Body: body}
+
+	if result.Success && len(cr.Assertions) > 0 {
+		if readErr != nil { // body is needed to assert, but could not be read
+			result.Success = false
+			result.Reason, result.Retryable = classifyError(readErr)
+		} else if err := checkAssertions(cr.Assertions, resp.Header, body); err != nil {
+			result.Success = false
+			result.Reason = "ASSERTION_FAILED"
+			result.Detail = err.Error()
+		}
+	}
+
+	if !result.Success {
+		result.Evidence = responseEvidence(resp, head, result.RemoteIP)
+		if readErr != nil {
+			result.Evidence.Error = readErr.Error()
+		}
+	}
+
+	return result, checked
+}
+
+// newCheckRequest builds the request of a check from its method, headers and body, and monitor's auth
+func newCheckRequest(ctx context.Context, cr *checkRequest, auth *monitor.Auth) (*http.Request, error) {
+	method := cr.Method
+	if method == "" { // monitors cached before method was added
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if cr.Body != "" {
+		body = strings.NewReader(cr.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, cr.URL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range cr.Headers {
+		// Host is not sent from Header map, it must be set on request itself
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+
+	// auth is applied last, so it wins over an Authorization header
+	if auth != nil {
+		switch auth.Type {
+		case monitor.AuthBasic:
+			req.SetBasicAuth(auth.Username, auth.Password)
+		case monitor.AuthBearer:
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
+		}
+	}
+
+	return req, nil
+}
+
+// redactURL hides password of url userinfo, so it can be logged or stored in result
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
+
+// parseExpectedStatus parses monitor's expected status spec, (in Check, monitor package is shadowed)
+func parseExpectedStatus(spec string) (monitor.StatusMatcher, error) {
+	return monitor.ParseStatusMatcher(spec)
+}
@@ -0,0 +1,205 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/httpclient"
+
+	"github.com/rs/zerolog"
+)
+
+func TestNewCheckRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		cr        checkRequest
+		auth      *monitor.Auth
+		method    string
+		host      string
+		body      string
+		header    http.Header
+		noHeaders []string
+	}{
+		{
+			name:   "method defaults to GET",
+			cr:     checkRequest{URL: "https://example.com/health"},
+			method: http.MethodGet, host: "example.com",
+		},
+		{
+			name:   "method, headers and body",
+			cr:     checkRequest{Method: http.MethodPost, URL: "https://example.com/api", Headers: map[string]string{"content-type": "application/json", "X-Trace": "1"}, Body: `{"ping":true}`},
+			method: http.MethodPost, host: "example.com", body: `{"ping":true}`,
+			header: http.Header{"Content-Type": {"application/json"}, "X-Trace": {"1"}},
+		},
+		{
+			name:   "host header is set on request",
+			cr:     checkRequest{URL: "https://10.0.0.1/", Headers: map[string]string{"host": "internal.example"}},
+			method: http.MethodGet, host: "internal.example",
+			noHeaders: []string{"Host"},
+		},
+		{
+			name:   "basic auth",
+			cr:     checkRequest{URL: "https://example.com/"},
+			auth:   &monitor.Auth{Type: monitor.AuthBasic, Username: "monit", Password: "s3cret"},
+			method: http.MethodGet, host: "example.com",
+			header: http.Header{"Authorization": {"Basic bW9uaXQ6czNjcmV0"}},
+		},
+		{
+			name:   "auth wins over authorization header",
+			cr:     checkRequest{URL: "https://example.com/", Headers: map[string]string{"Authorization": "Bearer stale"}},
+			auth:   &monitor.Auth{Type: monitor.AuthBearer, Token: "fresh"},
+			method: http.MethodGet, host: "example.com",
+			header: http.Header{"Authorization": {"Bearer fresh"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := newCheckRequest(context.Background(), &tt.cr, tt.auth)
+			if err != nil {
+				t.Fatalf("newCheckRequest: %v", err)
+			}
+			if req.Method != tt.method || req.Host != tt.host {
+				t.Errorf("method, host = %s, %s, want %s, %s", req.Method, req.Host, tt.method, tt.host)
+			}
+			var body []byte
+			if req.Body != nil {
+				body, _ = io.ReadAll(req.Body)
+			}
+			if string(body) != tt.body || req.ContentLength != int64(len(tt.body)) {
+				t.Errorf("body = %q (%d), want %q", body, req.ContentLength, tt.body)
+			}
+			for k, want := range tt.header {
+				if got := req.Header.Values(k); len(got) != 1 || got[0] != want[0] {
+					t.Errorf("header %s = %q, want %q", k, got, want)
+				}
+			}
+			for _, k := range tt.noHeaders {
+				if _, ok := req.Header[k]; ok {
+					t.Errorf("header %s is set", k)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCheckRequestInvalid(t *testing.T) {
+	for _, cr := range []checkRequest{
+		{Method: "BAD METHOD", URL: "https://example.com/"},
+		{URL: "https://example.com/%zz"},
+	} {
+		if _, err := newCheckRequest(context.Background(), &cr, nil); err == nil {
+			t.Errorf("newCheckRequest(%s %s) err = nil", cr.Method, cr.URL)
+		}
+	}
+}
+
+func TestHTTPCheckerRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/a", http.RedirectHandler("/b", http.StatusMovedPermanently))
+	mux.Handle("/b", http.RedirectHandler("/c", http.StatusFound))
+	mux.HandleFunc("/c", func(w http.ResponseWriter, _ *http.Request) { w.Write([]byte("ok")) })
+	mux.Handle("/loop", http.RedirectHandler("/loop", http.StatusFound))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		userinfo string
+		redirect *monitor.RedirectOptions
+		expected string
+		success  bool
+		reason   string
+		status   int
+		hops     []RedirectHop // path of hop urls
+		detail   string
+	}{
+		{
+			name: "chain is followed and recorded", path: "/a", success: true, status: 200,
+			hops: []RedirectHop{{"/a", 301}, {"/b", 302}, {"/c", 200}},
+		},
+		{
+			name: "no redirect, no chain", path: "/c", success: true, status: 200,
+		},
+		{
+			name: "not followed", path: "/a", redirect: &monitor.RedirectOptions{Follow: false}, expected: "301",
+			success: true, status: 301,
+		},
+		{
+			name: "final url as expected", path: "/a", redirect: &monitor.RedirectOptions{Follow: true, ExpectFinalURL: srv.URL + "/c"},
+			success: true, status: 200, hops: []RedirectHop{{"/a", 301}, {"/b", 302}, {"/c", 200}},
+		},
+		{
+			name: "final url mismatch", path: "/a", redirect: &monitor.RedirectOptions{Follow: true, ExpectFinalURL: srv.URL + "/b"},
+			reason: "REDIRECT_MISMATCH", status: 200, hops: []RedirectHop{{"/a", 301}, {"/b", 302}, {"/c", 200}},
+			detail: "ended at " + srv.URL + "/c",
+		},
+		{
+			name: "loop stops at max hops", path: "/loop", redirect: &monitor.RedirectOptions{Follow: true, MaxHops: 3},
+			reason: "TOO_MANY_REDIRECTS", hops: []RedirectHop{{"/loop", 302}, {"/loop", 302}, {"/loop", 302}, {"/loop", 302}},
+			detail: "stopped after 3 redirects",
+		},
+		{
+			name: "loop with default max hops", path: "/loop",
+			reason: "TOO_MANY_REDIRECTS", detail: fmt.Sprintf("stopped after %d redirects", monitor.DefaultMaxRedirects),
+		},
+		{
+			name: "password is redacted in chain", path: "/a", userinfo: "monit:s3cret@", success: true, status: 200,
+			hops: []RedirectHop{{"/a", 301}, {"/b", 302}, {"/c", 200}},
+		},
+	}
+
+	nop := zerolog.Nop()
+	checker := newHTTPChecker(httpclient.NewHttpClient(), "", &nop)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.expected
+			if expected == "" {
+				expected = "200"
+			}
+			m := monitor.Monitor{
+				Type:               monitor.TypeHTTP,
+				Url:                strings.Replace(srv.URL, "://", "://"+tt.userinfo, 1) + tt.path,
+				ExpectedStatus:     expected,
+				LatencyThresholdMs: 5000,
+				Options:            monitor.Options{Redirect: tt.redirect},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			res := checker.Check(ctx, m)
+			if res.Success != tt.success || res.Reason != tt.reason || res.Status != tt.status {
+				t.Errorf("success, reason, status = %v, %q, %d, want %v, %q, %d (%s)",
+					res.Success, res.Reason, res.Status, tt.success, tt.reason, tt.status, res.Detail)
+			}
+			if !strings.HasPrefix(res.Detail, tt.detail) {
+				t.Errorf("detail = %q, want prefix %q", res.Detail, tt.detail)
+			}
+			if tt.hops == nil {
+				if tt.reason != "TOO_MANY_REDIRECTS" && len(res.Redirects) > 0 {
+					t.Errorf("redirects = %v, want none", res.Redirects)
+				}
+				return
+			}
+			if len(res.Redirects) != len(tt.hops) {
+				t.Fatalf("redirects = %v, want %v", res.Redirects, tt.hops)
+			}
+			for i, hop := range res.Redirects {
+				if !strings.HasSuffix(hop.URL, tt.hops[i].URL) || hop.Status != tt.hops[i].Status {
+					t.Errorf("hop %d = %v, want %v", i, hop, tt.hops[i])
+				}
+				if strings.Contains(hop.URL, "s3cret") {
+					t.Errorf("hop %d shows password: %s", i, hop.URL)
+				}
+			}
+		})
+	}
+}
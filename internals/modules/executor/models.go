@@ -12,22 +12,140 @@ type HTTPResult struct {
 	Success     bool
 	Status      int
 	LatencyMs   int64
+	RemoteIP    string // ip connected to, both ipv4 and ipv6 ones if checked over both
+	Timings     PhaseTimings
+	Cert        *CertInfo     // leaf certificate, only for https checks
+	Redirects   RedirectChain // followed redirects, last hop is the final response
+	Steps       []StepResult  // steps run by a transaction check, in order
+	FailedStep  int           // 1 based index of failed transaction step, 0 if none failed
+	Evidence    *Evidence     // response (or error) of a failed http check
 	Reason      string
+	Detail      string // more context on Reason, ex: which assertion failed
 	Retryable   bool
 	CheckedAt   time.Time
 	IntervalSec int32
 	AlertEmail  string
+	Region      string // region of executor which ran the check
+}
+
+// PhaseTimings holds the time spent in each phase of an HTTP check (in ms),
+// phases which were skipped (ex: reused connection) are 0
+type PhaseTimings struct {
+	DNSMs      int64
+	ConnectMs  int64
+	TLSMs      int64
+	TTFBMs     int64
+	DownloadMs int64
+	// message sent -> reply received, only for websocket checks with a message
+	RoundTripMs int64
+}
+
+// StepResult is outcome of one step of a transaction check
+type StepResult struct {
+	Index     int // 1 based
+	Name      string
+	Status    int
+	LatencyMs int64
+	Timings   PhaseTimings
+	Success   bool
+}
+
+// RedirectHop is a response on the way of a check, its url and status code
+type RedirectHop struct {
+	URL    string
+	Status int
+}
+
+type RedirectChain []RedirectHop
+
+// CertInfo is the leaf certificate presented by server
+type CertInfo struct {
+	Subject    string
+	Issuer     string
+	DNSNames   []string
+	NotAfter   time.Time
+	ChainValid bool
+	ChainError string
+	// expires within monitor's alert window, must be alerted but does not fail the check
+	Expiring bool
 }
 
 func (h HTTPResult) MarshalZerologObject(e *zerolog.Event) {
+	if h.Cert != nil {
+		e.Object("cert", h.Cert)
+	}
+	if len(h.Redirects) > 0 {
+		e.Array("redirects", h.Redirects)
+	}
+	if h.Evidence != nil {
+		e.Object("evidence", h.Evidence)
+	}
+	if len(h.Steps) > 0 {
+		e.Array("steps", stepResults(h.Steps)).Int("failed_step", h.FailedStep)
+	}
 	e.
 		Str("monitor_id", h.MonitorID.String()).
 		Bool("success", h.Success).
 		Int("status", h.Status).
 		Int64("latency_ms", h.LatencyMs).
+		Str("remote_ip", h.RemoteIP).
+		Object("timings", h.Timings).
 		Str("reason", h.Reason).
+		Str("detail", h.Detail).
 		Bool("retryable", h.Retryable).
 		Time("checked_at", h.CheckedAt).
 		Int32("interval_sec", h.IntervalSec).
-		Str("alert_email", h.AlertEmail)
+		Str("alert_email", h.AlertEmail).
+		Str("region", h.Region)
+}
+
+func (t PhaseTimings) MarshalZerologObject(e *zerolog.Event) {
+	e.
+		Int64("dns_ms", t.DNSMs).
+		Int64("connect_ms", t.ConnectMs).
+		Int64("tls_ms", t.TLSMs).
+		Int64("ttfb_ms", t.TTFBMs).
+		Int64("download_ms", t.DownloadMs).
+		Int64("round_trip_ms", t.RoundTripMs)
+}
+
+func (c *CertInfo) MarshalZerologObject(e *zerolog.Event) {
+	e.
+		Str("subject", c.Subject).
+		Str("issuer", c.Issuer).
+		Strs("dns_names", c.DNSNames).
+		Time("not_after", c.NotAfter).
+		Bool("chain_valid", c.ChainValid).
+		Str("chain_error", c.ChainError).
+		Bool("expiring", c.Expiring)
+}
+
+func (c RedirectChain) MarshalZerologArray(a *zerolog.Array) {
+	for _, hop := range c {
+		a.Object(hop)
+	}
+}
+
+func (r RedirectHop) MarshalZerologObject(e *zerolog.Event) {
+	e.
+		Str("url", r.URL).
+		Int("status", r.Status)
+}
+
+type stepResults []StepResult
+
+func (s stepResults) MarshalZerologArray(a *zerolog.Array) {
+	for _, step := range s {
+		a.Object(step)
+	}
+}
+
+func (s StepResult) MarshalZerologObject(e *zerolog.Event) {
+	e.
+		Int("index", s.Index).
+		Str("name", s.Name).
+		Int("status", s.Status).
+		Int64("latency_ms", s.LatencyMs).
+		Object("timings", s.Timings).
+		Bool("success", s.Success)
 }
@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"project-k/internals/modules/monitor"
+	"strings"
+)
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// networkDial returns a dial func applying monitor's network options on base dialer:
+// ip version pinning, custom resolver and static host overrides
+func networkDial(base *net.Dialer, o *monitor.NetworkOptions) (dialFunc, error) {
+	if o == nil {
+		return base.DialContext, nil
+	}
+
+	d := *base
+	if o.Resolver != "" {
+		r, err := customResolver(o.Resolver)
+		if err != nil {
+			return nil, err
+		}
+		d.Resolver = r
+	}
+
+	// host names are case insensitive
+	hosts := make(map[string]string, len(o.Hosts))
+	for host, ip := range o.Hosts {
+		hosts[strings.ToLower(host)] = ip
+	}
+	version := o.IPVersion
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		network = pinNetwork(network, version)
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if ip, ok := hosts[strings.ToLower(host)]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return d.DialContext(ctx, network, addr)
+	}, nil
+}
+
+// pinNetwork narrows tcp/udp network to ip version, ex: tcp -> tcp6
+func pinNetwork(network, version string) string {
+	if network != "tcp" && network != "udp" {
+		return network
+	}
+	switch version {
+	case monitor.IPv4:
+		return network + "4"
+	case monitor.IPv6:
+		return network + "6"
+	}
+	return network
+}
+
+// networkKey identifies network options, checks with same key can share connections
+func networkKey(o *monitor.NetworkOptions) string {
+	if o == nil {
+		return ""
+	}
+	// maps are printed with sorted keys
+	return fmt.Sprintf("%s|%s|%v", o.IPVersion, o.Resolver, o.Hosts)
+}
+
+// customResolver returns a resolver which sends all queries to addr (host:port, port defaults to 53)
+func customResolver(addr string) (*net.Resolver, error) {
+	server, err := monitor.ResolverAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, server)
+		},
+	}, nil
+}
+
+// remoteIP returns ip part of a connection's remote address
+func remoteIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
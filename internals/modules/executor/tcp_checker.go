@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"project-k/internals/modules/monitor"
+	"strconv"
+	"time"
+)
+
+// tcpChecker checks that a tcp://host:port accepts connections,
+// and optionally that server greets with an expected banner (SMTP, SSH, ...)
+type tcpChecker struct {
+	dialer *net.Dialer
+}
+
+func newTCPChecker() *tcpChecker {
+	return &tcpChecker{
+		dialer: &net.Dialer{},
+	}
+}
+
+func (c *tcpChecker) Check(ctx context.Context, monitor monitor.Monitor) HTTPResult {
+	addr, err := tcpAddress(monitor.Url)
+	if err != nil {
+		return HTTPResult{
+			MonitorID: monitor.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	dial, err := networkDial(c.dialer, monitor.Options.Network)
+	if err != nil {
+		return HTTPResult{
+			MonitorID: monitor.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	start := time.Now()
+	conn, err := dial(ctx, "tcp", addr)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		reason, isRetryable := classifyError(err)
+		return HTTPResult{
+			MonitorID: monitor.ID,
+			Success:   false,
+			LatencyMs: latency,
+			Reason:    reason,
+			Detail:    err.Error(),
+			Retryable: isRetryable,
+			CheckedAt: time.Now(),
+		}
+	}
+	defer conn.Close()
+
+	result := HTTPResult{
+		MonitorID: monitor.ID,
+		LatencyMs: latency,
+		RemoteIP:  remoteIP(conn.RemoteAddr()),
+		Timings:   PhaseTimings{ConnectMs: latency},
+		Success:   latency <= int64(monitor.LatencyThresholdMs),
+		Retryable: false,
+		CheckedAt: time.Now(),
+	}
+
+	opts := monitor.Options.TCP
+	if opts == nil || opts.ExpectBanner == "" {
+		return result
+	}
+
+	// read just enough of the greeting to compare with expected prefix
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetReadDeadline(deadline)
+	}
+	want := []byte(opts.ExpectBanner)
+	got := make([]byte, len(want))
+	n, err := io.ReadFull(conn, got)
+	result.Timings.TTFBMs = time.Since(start).Milliseconds() - latency
+	result.CheckedAt = time.Now()
+
+	if err != nil && n == 0 {
+		result.Success = false
+		result.Reason, result.Retryable = classifyError(err)
+		result.Detail = "no banner received: " + err.Error()
+		return result
+	}
+	if !bytes.Equal(got[:n], want) {
+		result.Success = false
+		result.Reason = "BANNER_MISMATCH"
+		result.Detail = fmt.Sprintf("expected banner %s, got %s", strconv.Quote(opts.ExpectBanner), strconv.Quote(string(got[:n])))
+	}
+
+	return result
+}
+
+// tcpAddress returns host:port of monitor url, (in Check, monitor package is shadowed)
+func tcpAddress(rawURL string) (string, error) {
+	return monitor.TCPAddress(rawURL)
+}
@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"project-k/internals/modules/monitor"
+)
+
+// tcpStandIn accepts connections on a local port and writes banner to each, then closes it,
+// a silent one keeps connection open without writing
+func tcpStandIn(t *testing.T, banner string, silent bool) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if silent {
+					conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+					conn.Read(make([]byte, 1))
+					return
+				}
+				conn.Write([]byte(banner))
+			}()
+		}
+	}()
+	return "tcp://" + ln.Addr().String()
+}
+
+// closedPort is a tcp url nothing listens on
+func closedPort(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return "tcp://" + addr
+}
+
+func TestTCPChecker(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       func(t *testing.T) string
+		expect    string
+		success   bool
+		reason    string
+		retryable bool
+	}{
+		{
+			name:    "port open",
+			url:     func(t *testing.T) string { return tcpStandIn(t, "", false) },
+			success: true,
+		},
+		{
+			name:    "banner matches",
+			url:     func(t *testing.T) string { return tcpStandIn(t, "220 mail.example ESMTP\r\n", false) },
+			expect:  "220 ",
+			success: true,
+		},
+		{
+			name:   "banner mismatch",
+			url:    func(t *testing.T) string { return tcpStandIn(t, "SSH-2.0-OpenSSH_9.6\r\n", false) },
+			expect: "220 ",
+			reason: "BANNER_MISMATCH",
+		},
+		{
+			name:   "banner shorter than expected",
+			url:    func(t *testing.T) string { return tcpStandIn(t, "22", false) },
+			expect: "220 ",
+			reason: "BANNER_MISMATCH",
+		},
+		{
+			name:      "no banner before timeout",
+			url:       func(t *testing.T) string { return tcpStandIn(t, "", true) },
+			expect:    "220 ",
+			reason:    "NETWORK_TIMEOUT",
+			retryable: true,
+		},
+		{
+			name:      "connection refused",
+			url:       closedPort,
+			reason:    "NETWORK_ERROR",
+			retryable: true,
+		},
+		{
+			name:   "not a tcp url",
+			url:    func(*testing.T) string { return "tcp://example.com" },
+			reason: "INVALID_REQUEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := monitor.Monitor{Type: monitor.TypeTCP, Url: tt.url(t), LatencyThresholdMs: 1000}
+			if tt.expect != "" {
+				m.Options.TCP = &monitor.TCPOptions{ExpectBanner: tt.expect}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+
+			res := newTCPChecker().Check(ctx, m)
+			if res.Success != tt.success || res.Reason != tt.reason || res.Retryable != tt.retryable {
+				t.Errorf("success, reason, retryable = %v, %q, %v, want %v, %q, %v (%s)",
+					res.Success, res.Reason, res.Retryable, tt.success, tt.reason, tt.retryable, res.Detail)
+			}
+			if tt.success && res.RemoteIP != "127.0.0.1" {
+				t.Errorf("remote ip = %q", res.RemoteIP)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// phaseTracer records the timestamps of each phase of a single HTTP check
+// through net/http/httptrace hooks, hooks can fire from transport goroutines so access is guarded
+type phaseTracer struct {
+	mu sync.Mutex
+
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	remoteAddr   net.Addr
+}
+
+func (t *phaseTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mark(&t.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mark(&t.dnsDone)
+		},
+		ConnectStart: func(string, string) {
+			// with happy eyeballs multiple dials can start, keep the first one
+			t.markOnce(&t.connectStart)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.markOnce(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mark(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mark(&t.tlsDone)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.remoteAddr = info.Conn.RemoteAddr()
+			t.mu.Unlock()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mark(&t.wroteRequest)
+		},
+		GotFirstResponseByte: func() {
+			t.mark(&t.firstByte)
+		},
+	}
+}
+
+func (t *phaseTracer) mark(ts *time.Time) {
+	t.mu.Lock()
+	*ts = time.Now()
+	t.mu.Unlock()
+}
+
+func (t *phaseTracer) markOnce(ts *time.Time) {
+	t.mu.Lock()
+	if ts.IsZero() {
+		*ts = time.Now()
+	}
+	t.mu.Unlock()
+}
+
+// timings builds PhaseTimings, bodyDone is the time body was fully read (zero if not read),
+// phases which did not happen (ex: reused connection, plain http) are left as 0
+func (t *phaseTracer) timings(bodyDone time.Time) PhaseTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return PhaseTimings{
+		DNSMs:      spanMs(t.dnsStart, t.dnsDone),
+		ConnectMs:  spanMs(t.connectStart, t.connectDone),
+		TLSMs:      spanMs(t.tlsStart, t.tlsDone),
+		TTFBMs:     spanMs(t.wroteRequest, t.firstByte),
+		DownloadMs: spanMs(t.firstByte, bodyDone),
+	}
+}
+
+// remoteIP returns ip of server the request was sent to (proxy if proxied), empty if no connection was made
+func (t *phaseTracer) remoteIP() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return remoteIP(t.remoteAddr)
+}
+
+func spanMs(start, end time.Time) int64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start).Milliseconds()
+}
@@ -0,0 +1,224 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/jsonpath"
+	"time"
+)
+
+// transactionChecker runs steps of a transaction monitor in order, as one user session (shared cookie jar),
+// it fails on first failing step
+type transactionChecker struct {
+	http *httpChecker
+}
+
+func newTransactionChecker(http *httpChecker) *transactionChecker {
+	return &transactionChecker{
+		http: http,
+	}
+}
+
+func (c *transactionChecker) Check(ctx context.Context, m monitor.Monitor) HTTPResult {
+	invalid := func(detail string) HTTPResult {
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    detail,
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	if m.Options.Transaction == nil || len(m.Options.Transaction.Steps) == 0 {
+		return invalid("transaction has no steps")
+	}
+
+	httpClient, err := c.http.clients.clientFor(&m)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return invalid(err.Error())
+	}
+	session := *httpClient
+	session.Jar = jar
+
+	var (
+		vars    = map[string]string{}
+		steps   []StepResult
+		latency int64
+		cert    *CertInfo
+		remote  string
+	)
+
+	for i, step := range m.Options.Transaction.Steps {
+		stepURL, err := monitor.StepURL(m.Url, monitor.Substitute(step.Url, vars))
+		if err != nil {
+			return invalid(fmt.Sprintf("%s: %v", stepLabel(i, step.Name), err))
+		}
+
+		// monitor headers are common to all steps, step headers win
+		headers := make(map[string]string, len(m.Headers)+len(step.Headers))
+		for k, v := range m.Headers {
+			headers[k] = v
+		}
+		for k, v := range step.Headers {
+			headers[k] = monitor.Substitute(v, vars)
+		}
+
+		r, resp := c.http.do(ctx, &session, &m, checkRequest{
+			Method:         step.Method,
+			URL:            stepURL,
+			Headers:        headers,
+			Body:           monitor.Substitute(step.Body, vars),
+			ExpectedStatus: step.ExpectedStatus,
+			Assertions:     step.Assertions,
+			KeepBody:       needsExtractBody(step.Extract),
+		})
+		latency += r.LatencyMs
+		remote = r.RemoteIP
+		if cert == nil {
+			cert = r.Cert
+		}
+		steps = append(steps, StepResult{
+			Index:     i + 1,
+			Name:      step.Name,
+			Status:    r.Status,
+			LatencyMs: r.LatencyMs,
+			Timings:   r.Timings,
+			Success:   r.Success,
+		})
+
+		if r.Success {
+			if err := extract(step.Extract, resp, vars); err != nil {
+				r.Success = false
+				r.Reason = "EXTRACTION_FAILED"
+				r.Detail = err.Error()
+				steps[i].Success = false
+			}
+		}
+
+		if !r.Success {
+			r.LatencyMs = latency
+			r.Timings = PhaseTimings{}
+			r.Cert = cert
+			r.Steps = steps
+			r.FailedStep = i + 1
+			if r.Detail == "" && r.Reason == "" { // status or latency did not match
+				r.Detail = fmt.Sprintf("status %d in %dms", steps[i].Status, steps[i].LatencyMs)
+			}
+			r.Detail = stepLabel(i, step.Name) + " failed" + detailSuffix(r.Detail)
+			return r
+		}
+	}
+
+	last := steps[len(steps)-1]
+	return HTTPResult{
+		MonitorID: m.ID,
+		Success:   true,
+		Status:    last.Status,
+		LatencyMs: latency,
+		RemoteIP:  remote,
+		Cert:      cert,
+		Steps:     steps,
+		Retryable: false,
+		CheckedAt: time.Now(),
+	}
+}
+
+// extract stores the values of extractions from step response in vars
+func extract(extractions []monitor.Extraction, resp *checkResponse, vars map[string]string) error {
+	if len(extractions) == 0 {
+		return nil
+	}
+	if resp == nil {
+		return fmt.Errorf("no response to extract from")
+	}
+
+	var (
+		doc     any
+		docErr  error
+		decoded bool
+	)
+
+	for _, e := range extractions {
+		var (
+			value string
+			found bool
+		)
+
+		switch e.From {
+		case monitor.ExtractJSONPath:
+			if !decoded {
+				doc, docErr = jsonpath.Decode(resp.Body)
+				decoded = true
+			}
+			if docErr != nil {
+				return fmt.Errorf("extract %s: body is not valid json", e.Name)
+			}
+			v, ok, err := jsonpath.Lookup(doc, e.Path)
+			if err != nil {
+				return fmt.Errorf("extract %s: %v", e.Name, err)
+			}
+			if ok {
+				value, found = jsonpath.String(v), true
+			}
+
+		case monitor.ExtractHeader:
+			if vals := resp.Header.Values(e.Path); len(vals) > 0 {
+				value, found = vals[0], true
+			}
+
+		case monitor.ExtractCookie:
+			value, found = cookieValue(resp.Cookies, e.Path)
+
+		default:
+			return fmt.Errorf("extract %s: unknown source %q", e.Name, e.From)
+		}
+
+		if !found {
+			return fmt.Errorf("extract %s: %s %q not found", e.Name, e.From, e.Path)
+		}
+		vars[e.Name] = value
+	}
+	return nil
+}
+
+func cookieValue(cookies []*http.Cookie, name string) (string, bool) {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c.Value, true
+		}
+	}
+	return "", false
+}
+
+func needsExtractBody(extractions []monitor.Extraction) bool {
+	for _, e := range extractions {
+		if e.From == monitor.ExtractJSONPath {
+			return true
+		}
+	}
+	return false
+}
+
+// stepLabel names a step in failure detail, ex: step 2 (login)
+func stepLabel(i int, name string) string {
+	if name == "" {
+		return fmt.Sprintf("step %d", i+1)
+	}
+	return fmt.Sprintf("step %d (%s)", i+1, name)
+}
+
+func detailSuffix(detail string) string {
+	if detail == "" {
+		return ""
+	}
+	return ": " + detail
+}
@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"net/http"
+	"project-k/internals/modules/monitor"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	resp := &checkResponse{
+		Header: http.Header{
+			"X-Request-Id": {"req-1", "req-2"},
+			"Location":     {"/orders/7"},
+		},
+		Cookies: []*http.Cookie{
+			{Name: "session", Value: "s-123"},
+			{Name: "theme", Value: "dark"},
+		},
+		Body: []byte(`{"token":"abc","user":{"id":42,"admin":true},"items":[{"sku":"x1"}],"empty":null}`),
+	}
+
+	tests := []struct {
+		name    string
+		extract []monitor.Extraction
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "json string, number, bool and array element",
+			extract: []monitor.Extraction{
+				{Name: "token", From: monitor.ExtractJSONPath, Path: "$.token"},
+				{Name: "id", From: monitor.ExtractJSONPath, Path: "$.user.id"},
+				{Name: "admin", From: monitor.ExtractJSONPath, Path: "user.admin"},
+				{Name: "sku", From: monitor.ExtractJSONPath, Path: "$.items[0].sku"},
+			},
+			want: map[string]string{"token": "abc", "id": "42", "admin": "true", "sku": "x1"},
+		},
+		{
+			name:    "json null",
+			extract: []monitor.Extraction{{Name: "e", From: monitor.ExtractJSONPath, Path: "$.empty"}},
+			want:    map[string]string{"e": "null"},
+		},
+		{
+			name:    "json path missing",
+			extract: []monitor.Extraction{{Name: "t", From: monitor.ExtractJSONPath, Path: "$.nope"}},
+			wantErr: `extract t: json_path "$.nope" not found`,
+		},
+		{
+			name: "header first value, case insensitive name",
+			extract: []monitor.Extraction{
+				{Name: "rid", From: monitor.ExtractHeader, Path: "x-request-id"},
+				{Name: "loc", From: monitor.ExtractHeader, Path: "Location"},
+			},
+			want: map[string]string{"rid": "req-1", "loc": "/orders/7"},
+		},
+		{
+			name:    "header missing",
+			extract: []monitor.Extraction{{Name: "h", From: monitor.ExtractHeader, Path: "X-Token"}},
+			wantErr: `extract h: header "X-Token" not found`,
+		},
+		{
+			name:    "cookie",
+			extract: []monitor.Extraction{{Name: "sid", From: monitor.ExtractCookie, Path: "session"}},
+			want:    map[string]string{"sid": "s-123"},
+		},
+		{
+			name:    "cookie names are case sensitive",
+			extract: []monitor.Extraction{{Name: "sid", From: monitor.ExtractCookie, Path: "Session"}},
+			wantErr: `extract sid: cookie "Session" not found`,
+		},
+		{
+			name:    "unknown source",
+			extract: []monitor.Extraction{{Name: "x", From: "body", Path: "x"}},
+			wantErr: `extract x: unknown source "body"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := map[string]string{}
+			err := extract(tt.extract, resp, vars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("extract err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extract: %v", err)
+			}
+			if !reflect.DeepEqual(vars, tt.want) {
+				t.Errorf("vars = %v, want %v", vars, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractOverwritesAndKeepsVars(t *testing.T) {
+	resp := &checkResponse{Header: http.Header{"X-Token": {"new"}}}
+	vars := map[string]string{"token": "old", "other": "kept"}
+
+	err := extract([]monitor.Extraction{{Name: "token", From: monitor.ExtractHeader, Path: "X-Token"}}, resp, vars)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	want := map[string]string{"token": "new", "other": "kept"}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("vars = %v, want %v", vars, want)
+	}
+}
+
+func TestExtractBadInput(t *testing.T) {
+	jsonExtract := []monitor.Extraction{{Name: "t", From: monitor.ExtractJSONPath, Path: "$.token"}}
+
+	if err := extract(nil, nil, map[string]string{}); err != nil {
+		t.Errorf("no extractions: err = %v, want nil", err)
+	}
+	if err := extract(jsonExtract, nil, map[string]string{}); err == nil {
+		t.Error("nil response: err = nil")
+	}
+	err := extract(jsonExtract, &checkResponse{Body: []byte("<html>")}, map[string]string{})
+	if err == nil || !strings.Contains(err.Error(), "not valid json") {
+		t.Errorf("html body: err = %v, want not valid json", err)
+	}
+}
+
+func TestNeedsExtractBody(t *testing.T) {
+	if needsExtractBody([]monitor.Extraction{{From: monitor.ExtractHeader}, {From: monitor.ExtractCookie}}) {
+		t.Error("header and cookie extractions need body")
+	}
+	if !needsExtractBody([]monitor.Extraction{{From: monitor.ExtractHeader}, {From: monitor.ExtractJSONPath}}) {
+		t.Error("json path extraction does not need body")
+	}
+}
@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/httpclient"
+	"sync"
+	"time"
+)
+
+// a cached client is dropped when unused for transportIdleTTL, or as least recently used one past maxCachedTransports
+const (
+	maxCachedTransports = 1000
+	transportIdleTTL    = 30 * time.Minute
+)
+
+// transportCache holds http clients which need their own transport (proxy, network options, mtls identity),
+// keyed by all of them, so connections are pooled per key and never shared across keys.
+// clients of deleted or changed monitors would pile up, so idle and least recently used ones are evicted
+type transportCache struct {
+	shared       *http.Client
+	defaultProxy string // proxy of executor config, empty for direct
+	maxEntries   int
+	idleTTL      time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	clients map[string]*list.Element // of *cachedClient in lru
+	lru     *list.List               // most recently used first
+}
+
+type cachedClient struct {
+	key      string
+	client   *http.Client
+	lastUsed time.Time
+}
+
+func newTransportCache(shared *http.Client, defaultProxy string) *transportCache {
+	return &transportCache{
+		shared:       shared,
+		defaultProxy: defaultProxy,
+		maxEntries:   maxCachedTransports,
+		idleTTL:      transportIdleTTL,
+		now:          time.Now,
+		clients:      make(map[string]*list.Element),
+		lru:          list.New(),
+	}
+}
+
+// clientFor returns the client to check monitor with, shared one if monitor needs no own transport
+func (tc *transportCache) clientFor(m *monitor.Monitor) (*http.Client, error) {
+	proxy := effectiveProxy(m, tc.defaultProxy)
+	mtls := m.Auth != nil && m.Auth.Type == monitor.AuthMTLS
+	network := m.Options.Network
+	if proxy == "" && !mtls && network == nil {
+		return tc.shared, nil
+	}
+
+	// key is a hash, so secrets (proxy password, client key) are not kept around as map keys
+	h := sha256.New()
+	h.Write([]byte(proxy + "\x00" + networkKey(network)))
+	if mtls {
+		h.Write([]byte("\x00" + m.Auth.ClientCert + "\x00" + m.Auth.ClientKey))
+	}
+	key := hex.EncodeToString(h.Sum(nil))
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	now := tc.now()
+	tc.evictIdle(now)
+	if e, ok := tc.clients[key]; ok {
+		cc := e.Value.(*cachedClient)
+		cc.lastUsed = now
+		tc.lru.MoveToFront(e)
+		return cc.client, nil
+	}
+
+	t := tc.baseTransport().Clone()
+
+	if proxy != "" {
+		u, err := httpclient.ParseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		// socks5 is supported by transport itself, userinfo is used as proxy credentials
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if network != nil {
+		dial, err := networkDial(httpclient.NewDialer(), network)
+		if err != nil {
+			return nil, err
+		}
+		t.DialContext = dial
+	}
+
+	if mtls {
+		cert, err := m.Auth.ClientCertificate()
+		if err != nil {
+			return nil, err
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	c := *tc.shared
+	c.Transport = t
+	tc.clients[key] = tc.lru.PushFront(&cachedClient{key: key, client: &c, lastUsed: now})
+	if tc.lru.Len() > tc.maxEntries {
+		tc.evict(tc.lru.Back())
+	}
+	return &c, nil
+}
+
+// evictIdle drops clients unused since idle ttl, they are at the back of lru
+func (tc *transportCache) evictIdle(now time.Time) {
+	for e := tc.lru.Back(); e != nil && now.Sub(e.Value.(*cachedClient).lastUsed) > tc.idleTTL; e = tc.lru.Back() {
+		tc.evict(e)
+	}
+}
+
+// evict drops a client and closes its idle connections, a check still running on it completes
+func (tc *transportCache) evict(e *list.Element) {
+	cc := tc.lru.Remove(e).(*cachedClient)
+	delete(tc.clients, cc.key)
+	cc.client.CloseIdleConnections()
+}
+
+func (tc *transportCache) baseTransport() *http.Transport {
+	if t, ok := tc.shared.Transport.(*http.Transport); ok {
+		return t
+	}
+	return http.DefaultTransport.(*http.Transport)
+}
+
+// effectiveProxy returns proxy url of monitor's checks, monitor's own proxy wins over default one
+func effectiveProxy(m *monitor.Monitor, defaultProxy string) string {
+	if p := m.Options.Proxy; p != nil {
+		if p.Direct {
+			return ""
+		}
+		if p.URL != "" {
+			return p.URL
+		}
+	}
+	return defaultProxy
+}
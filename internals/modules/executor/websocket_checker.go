@@ -0,0 +1,170 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/httpclient"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// websocketChecker checks ws(s):// endpoints, upgrade must succeed (101),
+// and if monitor has a message, a matching reply must come back before timeout
+type websocketChecker struct {
+	dialer       *websocket.Dialer
+	defaultProxy string
+}
+
+func newWebSocketChecker(defaultProxy string) *websocketChecker {
+	return &websocketChecker{
+		dialer:       &websocket.Dialer{},
+		defaultProxy: defaultProxy,
+	}
+}
+
+func (c *websocketChecker) Check(ctx context.Context, m monitor.Monitor) HTTPResult {
+	header := http.Header{}
+	for k, v := range m.Headers {
+		header.Set(k, v)
+	}
+
+	dialer, err := c.dialerFor(&m)
+	if err != nil {
+		return HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			Reason:    "INVALID_REQUEST",
+			Detail:    err.Error(),
+			Retryable: false,
+			CheckedAt: time.Now(),
+		}
+	}
+
+	tracer := &phaseTracer{}
+	traceCtx := httptrace.WithClientTrace(ctx, tracer.clientTrace())
+
+	start := time.Now()
+	conn, resp, err := dialer.DialContext(traceCtx, m.Url, header)
+	latency := time.Since(start).Milliseconds()
+	if err != nil {
+		result := HTTPResult{
+			MonitorID: m.ID,
+			Success:   false,
+			LatencyMs: latency,
+			RemoteIP:  tracer.remoteIP(),
+			Timings:   tracer.timings(time.Time{}),
+			Detail:    err.Error(),
+			CheckedAt: time.Now(),
+		}
+		if errors.Is(err, websocket.ErrBadHandshake) && resp != nil {
+			// server answered, but did not upgrade (ex: 502 from gateway)
+			result.Status = resp.StatusCode
+			result.Reason = "HANDSHAKE_FAILED"
+			result.Detail = "upgrade refused with status " + strconv.Itoa(resp.StatusCode)
+			return result
+		}
+		result.Reason, result.Retryable = classifyError(err)
+		result.Cert = certFromError(err, m.Url, m.Options.CertExpiryAlertDays(), result.CheckedAt)
+		return result
+	}
+	defer conn.Close()
+
+	result := HTTPResult{
+		MonitorID: m.ID,
+		Status:    resp.StatusCode,
+		LatencyMs: latency,
+		RemoteIP:  remoteIP(conn.RemoteAddr()),
+		Timings:   tracer.timings(time.Time{}),
+		Success:   latency <= int64(m.LatencyThresholdMs),
+		Retryable: false,
+		CheckedAt: time.Now(),
+	}
+	if resp.TLS != nil {
+		result.Cert = inspectCert(resp.TLS, resp.Request.URL.Hostname(), m.Options.CertExpiryAlertDays(), result.CheckedAt)
+	}
+
+	opts := m.Options.WebSocket
+	if opts == nil || opts.Send == "" {
+		closeWebSocket(conn)
+		return result
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetWriteDeadline(deadline)
+		_ = conn.SetReadDeadline(deadline)
+	}
+	conn.SetReadLimit(maxBodyBytes)
+
+	sent := time.Now()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(opts.Send)); err != nil {
+		result.Success = false
+		result.Reason, result.Retryable = classifyError(err)
+		result.Detail = "send failed: " + err.Error()
+		return result
+	}
+
+	_, reply, err := conn.ReadMessage()
+	result.Timings.RoundTripMs = time.Since(sent).Milliseconds()
+	result.CheckedAt = time.Now()
+	if err != nil {
+		result.Success = false
+		result.Reason, result.Retryable = classifyError(err)
+		result.Detail = "no reply: " + err.Error()
+		return result
+	}
+	closeWebSocket(conn)
+
+	if opts.ExpectReply != "" && !strings.Contains(string(reply), opts.ExpectReply) {
+		result.Success = false
+		result.Reason = "REPLY_MISMATCH"
+		result.Detail = fmt.Sprintf("expected reply containing %q, got %q", opts.ExpectReply, truncate(string(reply), 256))
+	}
+
+	return result
+}
+
+// dialerFor returns dialer with proxy and network options of monitor, shared one if it has none
+func (c *websocketChecker) dialerFor(m *monitor.Monitor) (*websocket.Dialer, error) {
+	proxy := effectiveProxy(m, c.defaultProxy)
+	if proxy == "" && m.Options.Network == nil {
+		return c.dialer, nil
+	}
+
+	d := *c.dialer
+	if proxy != "" {
+		u, err := httpclient.ParseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		d.Proxy = http.ProxyURL(u)
+	}
+	if m.Options.Network != nil {
+		dial, err := networkDial(&net.Dialer{}, m.Options.Network)
+		if err != nil {
+			return nil, err
+		}
+		d.NetDialContext = dial
+	}
+	return &d, nil
+}
+
+// closeWebSocket sends a close frame before connection is closed, so server sees a clean close
+func closeWebSocket(conn *websocket.Conn) {
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
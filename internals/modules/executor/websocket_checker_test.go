@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"project-k/internals/modules/monitor"
+
+	"github.com/gorilla/websocket"
+)
+
+// websocketStandIn upgrades /echo (replies "echo: <message>"), /silent (never replies),
+// /private (only with X-Token header) and refuses /down with 502
+func websocketStandIn(t *testing.T) string {
+	t.Helper()
+	var upgrader websocket.Upgrader
+	serve := func(echo bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			for {
+				typ, msg, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				if echo {
+					conn.WriteMessage(typ, append([]byte("echo: "), msg...))
+				}
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/echo", serve(true))
+	mux.Handle("/silent", serve(false))
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "t0ken" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		serve(true)(w, r)
+	})
+	mux.HandleFunc("/down", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestWebSocketChecker(t *testing.T) {
+	base := websocketStandIn(t)
+
+	tests := []struct {
+		name      string
+		path      string
+		headers   map[string]string
+		opts      *monitor.WebSocketOptions
+		success   bool
+		status    int
+		reason    string
+		retryable bool
+		detail    string
+	}{
+		{name: "handshake only", path: "/echo", success: true, status: 101},
+		{name: "echo matches", path: "/echo", opts: &monitor.WebSocketOptions{Send: "ping", ExpectReply: "echo: ping"}, success: true, status: 101},
+		{name: "any reply", path: "/echo", opts: &monitor.WebSocketOptions{Send: "ping"}, success: true, status: 101},
+		{
+			name: "reply mismatch", path: "/echo", opts: &monitor.WebSocketOptions{Send: "ping", ExpectReply: "pong"},
+			status: 101, reason: "REPLY_MISMATCH", detail: `expected reply containing "pong", got "echo: ping"`,
+		},
+		{
+			name: "no reply before timeout", path: "/silent", opts: &monitor.WebSocketOptions{Send: "ping"},
+			status: 101, reason: "NETWORK_TIMEOUT", retryable: true, detail: "no reply: ",
+		},
+		{name: "upgrade refused", path: "/down", status: 502, reason: "HANDSHAKE_FAILED", detail: "upgrade refused with status 502"},
+		{name: "header missing", path: "/private", status: 401, reason: "HANDSHAKE_FAILED"},
+		{name: "header sent", path: "/private", headers: map[string]string{"X-Token": "t0ken"}, opts: &monitor.WebSocketOptions{Send: "ping", ExpectReply: "ping"}, success: true, status: 101},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := monitor.Monitor{
+				Type:               monitor.TypeWebSocket,
+				Url:                base + tt.path,
+				Headers:            tt.headers,
+				LatencyThresholdMs: 5000,
+				Options:            monitor.Options{WebSocket: tt.opts},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+			defer cancel()
+
+			res := newWebSocketChecker("").Check(ctx, m)
+			if res.Success != tt.success || res.Status != tt.status || res.Reason != tt.reason || res.Retryable != tt.retryable {
+				t.Errorf("success, status, reason, retryable = %v, %d, %q, %v, want %v, %d, %q, %v (%s)",
+					res.Success, res.Status, res.Reason, res.Retryable, tt.success, tt.status, tt.reason, tt.retryable, res.Detail)
+			}
+			if !strings.HasPrefix(res.Detail, tt.detail) {
+				t.Errorf("detail = %q, want prefix %q", res.Detail, tt.detail)
+			}
+			if res.Success && res.RemoteIP != "127.0.0.1" {
+				t.Errorf("remote ip = %q", res.RemoteIP)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"fmt"
+	"project-k/pkg/jsonpath"
+	"regexp"
+)
+
+type AssertionType string
+
+const (
+	AssertBodyContains    AssertionType = "body_contains"
+	AssertBodyNotContains AssertionType = "body_not_contains"
+	AssertBodyRegex       AssertionType = "body_regex"
+	AssertJSONPathEquals  AssertionType = "json_path_equals"
+	AssertJSONPathExists  AssertionType = "json_path_exists"
+	AssertHeaderEquals    AssertionType = "header_equals"
+)
+
+// Assertion is a check on the response, which must hold for a check to be successful
+type Assertion struct {
+	Type AssertionType `json:"type"`
+	// json path for json_path_* , header name for header_equals
+	Path  string `json:"path,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// String names the assertion, used in failure reason
+func (a Assertion) String() string {
+	switch a.Type {
+	case AssertJSONPathExists:
+		return fmt.Sprintf("%s %s", a.Type, a.Path)
+	case AssertJSONPathEquals, AssertHeaderEquals:
+		return fmt.Sprintf("%s %s == %q", a.Type, a.Path, a.Value)
+	default:
+		return fmt.Sprintf("%s %q", a.Type, a.Value)
+	}
+}
+
+// NeedsBody reports if assertion reads the response body
+func (a Assertion) NeedsBody() bool {
+	return a.Type != AssertHeaderEquals
+}
+
+// Validate checks the assertion is well formed (required fields, regex and json path syntax)
+func (a Assertion) Validate() error {
+	switch a.Type {
+	case AssertBodyContains, AssertBodyNotContains:
+		if a.Value == "" {
+			return fmt.Errorf("%s needs a value", a.Type)
+		}
+	case AssertBodyRegex:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("%s has invalid regex: %v", a.Type, err)
+		}
+	case AssertJSONPathEquals, AssertJSONPathExists:
+		if err := jsonpath.Validate(a.Path); err != nil {
+			return fmt.Errorf("%s: %v", a.Type, err)
+		}
+	case AssertHeaderEquals:
+		if a.Path == "" {
+			return fmt.Errorf("%s needs a header name in path", a.Type)
+		}
+	default:
+		return fmt.Errorf("unknown assertion type %q", a.Type)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+)
+
+type AuthType string
+
+const (
+	AuthBasic  AuthType = "basic"
+	AuthBearer AuthType = "bearer"
+	AuthMTLS   AuthType = "mtls"
+)
+
+// Auth holds credentials of an authenticated check,
+// it is never returned in API responses or logged, see AuthSummary
+type Auth struct {
+	Type     AuthType `json:"type"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	Token    string   `json:"token,omitempty"`
+	// PEM encoded client certificate (chain) and its private key, for mtls
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+}
+
+// Validate checks the credentials needed by auth type are present,
+// and for mtls that certificate and key are a valid pair
+func (a *Auth) Validate() error {
+	switch a.Type {
+	case AuthBasic:
+		if a.Username == "" {
+			return fmt.Errorf("basic auth needs a username")
+		}
+	case AuthBearer:
+		if a.Token == "" {
+			return fmt.Errorf("bearer auth needs a token")
+		}
+	case AuthMTLS:
+		if _, err := a.ClientCertificate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown auth type %q", a.Type)
+	}
+	return nil
+}
+
+// ClientCertificate parses the PEM client certificate and key of mtls auth
+func (a *Auth) ClientCertificate() (tls.Certificate, error) {
+	cert, err := tls.X509KeyPair([]byte(a.ClientCert), []byte(a.ClientKey))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate or key: %v", err)
+	}
+	return cert, nil
+}
+
+// AuthSummary is the non secret part of Auth, safe to return to user
+type AuthSummary struct {
+	Type     AuthType `json:"type"`
+	Username string   `json:"username,omitempty"`
+}
+
+// Summary returns what can be shown of auth, nil if monitor has no auth
+func (a *Auth) Summary() *AuthSummary {
+	if a == nil {
+		return nil
+	}
+	return &AuthSummary{Type: a.Type, Username: a.Username}
+}
+
+// Sealer encrypts secrets kept at rest, see security.Cipher
+type Sealer interface {
+	Seal(plaintext []byte) (string, error)
+	Open(sealed string) ([]byte, error)
+}
+
+// sealedAuth is how auth is kept in db and cache, credentials are never stored in plaintext
+type sealedAuth struct {
+	Sealed string `json:"sealed"`
+}
+
+// sealAuth encrypts auth into its stored form, nil if monitor has no auth
+func sealAuth(s Sealer, a *Auth) ([]byte, error) {
+	if a == nil {
+		return nil, nil
+	}
+	plain, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.Seal(plain)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealedAuth{Sealed: sealed})
+}
+
+// openAuth decrypts stored auth, rows written before sealing hold plaintext auth and are read as is
+func openAuth(s Sealer, data []byte) (*Auth, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var stored sealedAuth
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	plain := data
+	if stored.Sealed != "" {
+		var err error
+		if plain, err = s.Open(stored.Sealed); err != nil {
+			return nil, err
+		}
+	}
+	var a *Auth
+	if err := json.Unmarshal(plain, &a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -0,0 +1,194 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"project-k/config"
+	"project-k/internals/security"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSealer(t *testing.T) Sealer {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	c, err := security.NewCipher(&config.AuthConfig{EncryptionKey: key})
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestSealAuth(t *testing.T) {
+	s := testSealer(t)
+	auth := &Auth{Type: AuthBasic, Username: "ops", Password: "hunter2"}
+
+	stored, err := sealAuth(s, auth)
+	if err != nil {
+		t.Fatalf("sealAuth: %v", err)
+	}
+	if bytes.Contains(stored, []byte("hunter2")) || bytes.Contains(stored, []byte("ops")) {
+		t.Fatalf("stored auth leaks credentials: %s", stored)
+	}
+	got, err := openAuth(s, stored)
+	if err != nil {
+		t.Fatalf("openAuth: %v", err)
+	}
+	if !reflect.DeepEqual(got, auth) {
+		t.Errorf("openAuth = %+v, want %+v", got, auth)
+	}
+
+	if stored, _ := sealAuth(s, nil); stored != nil {
+		t.Errorf("sealAuth(nil) = %s, want nil", stored)
+	}
+	for _, empty := range [][]byte{nil, []byte("null")} {
+		if got, err := openAuth(s, empty); got != nil || err != nil {
+			t.Errorf("openAuth(%q) = %+v, %v, want nil, nil", empty, got, err)
+		}
+	}
+}
+
+func TestOpenAuthPlaintext(t *testing.T) {
+	// rows written before sealing existed
+	got, err := openAuth(testSealer(t), []byte(`{"type":"bearer","token":"abc"}`))
+	if err != nil {
+		t.Fatalf("openAuth: %v", err)
+	}
+	if got == nil || got.Type != AuthBearer || got.Token != "abc" {
+		t.Errorf("openAuth = %+v, want bearer abc", got)
+	}
+}
+
+func TestOpenAuthWrongKey(t *testing.T) {
+	stored, _ := sealAuth(testSealer(t), &Auth{Type: AuthBearer, Token: "abc"})
+
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32))
+	other, _ := security.NewCipher(&config.AuthConfig{EncryptionKey: key})
+	if _, err := openAuth(other, stored); err == nil {
+		t.Error("openAuth with another key succeeded")
+	}
+}
+
+func TestCachedMonitorSealsAuth(t *testing.T) {
+	s := testSealer(t)
+	m := Monitor{Type: TypeHTTP, Url: "https://example.com", Auth: &Auth{Type: AuthBearer, Token: "tok-123"}}
+	sealed, err := sealAuth(s, m.Auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(cachedMonitor{Monitor: m, Auth: sealed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tok-123") {
+		t.Fatalf("cached monitor leaks token: %s", data)
+	}
+
+	var cm cachedMonitor
+	if err := json.Unmarshal(data, &cm); err != nil {
+		t.Fatal(err)
+	}
+	if cm.Monitor.Auth != nil {
+		t.Error("embedded auth decoded from cache, want only sealed form")
+	}
+	auth, err := openAuth(s, cm.Auth)
+	if err != nil || auth == nil || auth.Token != "tok-123" {
+		t.Errorf("openAuth = %+v, %v, want token tok-123", auth, err)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	in := map[string]string{
+		"Authorization":       "Bearer abc",
+		"Proxy-Authorization": "Basic xyz",
+		"Cookie":              "sid=1",
+		"X-API-Key":           "k",
+		"X-Auth-Token":        "t",
+		"X-Client-Secret":     "s",
+		"Accept":              "application/json",
+		"User-Agent":          "monit",
+	}
+	got := redactHeaders(in)
+	for name, value := range got {
+		switch name {
+		case "Accept", "User-Agent":
+			if value != in[name] {
+				t.Errorf("%s = %q, want %q", name, value, in[name])
+			}
+		default:
+			if value != "xxxxx" {
+				t.Errorf("%s = %q, want redacted", name, value)
+			}
+		}
+	}
+	if in["Authorization"] != "Bearer abc" {
+		t.Error("redactHeaders modified its input")
+	}
+	if redactHeaders(nil) != nil {
+		t.Error("redactHeaders(nil) != nil")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"", ""},
+		{`{"username":"ops","password":"hunter2"}`, `{"username":"ops","password":"xxxxx"}`},
+		{`{"user": "ops", "api_key": "k-1", "n": 1}`, `{"user": "ops", "api_key": "xxxxx", "n": 1}`},
+		{`{"password":"a\"b","next":"c"}`, `{"password":"xxxxx","next":"c"}`},
+		{`{"token": {{ token }}}`, `{"token": xxxxx}`},
+		{`{"refresh_token": 42}`, `{"refresh_token": xxxxx}`},
+		{"grant_type=client_credentials&client_id=app&client_secret=s3cr3t", "grant_type=client_credentials&client_id=app&client_secret=xxxxx"},
+		{"username=ops&password=hunter2", "username=ops&password=xxxxx"},
+		// nested object keeps its shape, credentials inside it are hidden
+		{`{"auth": {"user": "ops", "pass": "x", "secret": "y"}}`, `{"auth": {"user": "ops", "pass": "x", "secret": "xxxxx"}}`},
+		{`{"query":"{ status }"}`, `{"query":"{ status }"}`},
+		{"ping", "ping"},
+	}
+	for _, tt := range tests {
+		if got := redactBody(tt.body); got != tt.want {
+			t.Errorf("redactBody(%s) = %s, want %s", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestMonitorResponseRedacted(t *testing.T) {
+	m := &Monitor{
+		Headers: map[string]string{"X-Api-Key": "k-1"},
+		Body:    `{"password":"hunter2"}`,
+		Options: Options{Transaction: &TransactionOptions{Steps: []Step{
+			{Name: "login", Body: "username=ops&password=hunter2", Headers: map[string]string{"Cookie": "sid=1"}},
+		}}},
+	}
+	res := toMonitorResponse(m)
+	out, _ := json.Marshal(res)
+	for _, secret := range []string{"hunter2", "k-1", "sid=1"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("response %s holds %q", out, secret)
+		}
+	}
+	if m.Body != `{"password":"hunter2"}` || m.Options.Transaction.Steps[0].Body != "username=ops&password=hunter2" {
+		t.Error("response redaction modified the monitor")
+	}
+}
+
+func TestOptionsRedactedSteps(t *testing.T) {
+	o := Options{Transaction: &TransactionOptions{Steps: []Step{
+		{Name: "login", Headers: map[string]string{"Authorization": "Bearer abc", "Accept": "*/*"}},
+	}}}
+	r := o.redacted()
+	if got := r.Transaction.Steps[0].Headers["Authorization"]; got != "xxxxx" {
+		t.Errorf("step Authorization = %q, want redacted", got)
+	}
+	if got := r.Transaction.Steps[0].Headers["Accept"]; got != "*/*" {
+		t.Errorf("step Accept = %q, want */*", got)
+	}
+	if o.Transaction.Steps[0].Headers["Authorization"] != "Bearer abc" {
+		t.Error("redacted modified stored steps")
+	}
+}
@@ -12,7 +12,11 @@ type Cache interface {
 	SetMonitor(ctx context.Context, id string, data []byte, ttl time.Duration) error
 	Schedule(ctx context.Context, monitorID string, runAt time.Time) error 
 	ClearIncident(ctx context.Context, monitorID uuid.UUID) error
+	HasIncident(ctx context.Context, monitorID uuid.UUID) (bool, error)
+	GetIncident(ctx context.Context, monitorID uuid.UUID) (map[string]string, error)
 	DelMonitor(ctx context.Context, id string) error
 	DelStatus(ctx context.Context, monitorID uuid.UUID) error
 	DelSchedule(ctx context.Context, monitorID string) error
+	StoreHeartbeat(ctx context.Context, monitorID uuid.UUID, at time.Time) error
+	DelHeartbeat(ctx context.Context, monitorID uuid.UUID) error
 }
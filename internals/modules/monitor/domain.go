@@ -8,24 +8,46 @@ import (
 
 type CreateMonitorCmd struct {
 	UserID             uuid.UUID
+	Type               string
 	Url                string
 	IntervalSec        int32
 	TimeoutSec         int32
 	LatencyThresholdMs int32
-	ExpectedStatus     int32
+	ExpectedStatus     string // status spec, see StatusMatcher
 	AlertEmail         string
+	Method             string
+	Headers            map[string]string
+	Body               string
+	Assertions         []Assertion
+	Options            Options
+	Auth               *Auth
+	HeartbeatToken     string // generated by service for heartbeat monitors
+}
+
+type CreateMonitorResult struct {
+	MonitorID      uuid.UUID
+	HeartbeatToken string // only for heartbeat monitors
 }
 
 type Monitor struct {
 	ID                 uuid.UUID
 	UserID             uuid.UUID
+	Type               string
 	Url                string
 	AlertEmail         string
 	IntervalSec        int32
 	TimeoutSec         int32
 	LatencyThresholdMs int32
-	ExpectedStatus     int32
+	ExpectedStatus     string // status spec, see StatusMatcher
+	Method             string
+	Headers            map[string]string
+	Body               string
+	Assertions         []Assertion
+	Options            Options
+	Auth               *Auth // secrets, never return or log it
+	HeartbeatToken     string
 	Enabled            bool
+	CreatedAt          time.Time
 }
 
 type MonitorRecord struct {
@@ -1,27 +1,159 @@
 package monitor
 
 type CreateMonitorRequest struct {
-	Url                string `json:"url" validate:"required,url"`
-	AlertEmail         string `json:"alert_email" validate:"email"`
-	IntervalSec        int32  `json:"interval_sec" validate:"required,gte=60"`
-	TimeoutSec         int32  `json:"timeout_sec" validate:"required,gte=120"`
-	LatencyThresholdMs int32  `json:"latency_threshold_ms" validate:"required,gte=0"`
-	ExpectedStatus     int32  `json:"expected_status" validate:"required,gte=100,lte=599"`
+	// http (default), tcp, dns, grpc, websocket, transaction or heartbeat, for tcp url is tcp://host:port,
+	// for dns url is dns://name, for grpc url is grpc://host:port, for websocket url is ws(s)://host/path,
+	// for transaction url is the base url of steps, heartbeat has no url
+	Type               string         `json:"type" validate:"omitempty,oneof=http tcp dns grpc websocket transaction heartbeat"`
+	Url                string         `json:"url" validate:"required_unless=Type heartbeat,omitempty,url"`
+	AlertEmail         string         `json:"alert_email" validate:"email"`
+	IntervalSec        int32          `json:"interval_sec" validate:"required,gte=60"`
+	TimeoutSec         int32          `json:"timeout_sec" validate:"required,gte=1,lte=120"` // capped at interval_sec
+	LatencyThresholdMs int32          `json:"latency_threshold_ms" validate:"required,gte=0"`
+	ExpectedStatus     ExpectedStatus `json:"expected_status" validate:"omitempty,max=256,status_spec"` // required for http monitors
+	// request to send, method defaults to GET
+	Method  string            `json:"method" validate:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Headers map[string]string `json:"headers" validate:"omitempty,lte=50,dive,keys,required,max=256,endkeys,max=4096"`
+	Body    string            `json:"body" validate:"omitempty,max=65536"`
+	// checks on the response, all must pass for check to succeed
+	Assertions []AssertionRequest `json:"assertions" validate:"omitempty,lte=20,dive"`
+	// type specific settings
+	TCP       *TCPOptionsRequest       `json:"tcp"`
+	DNS       *DNSOptionsRequest       `json:"dns"`
+	TLS       *TLSOptionsRequest       `json:"tls"`
+	Heartbeat *HeartbeatOptionsRequest `json:"heartbeat"`
+	Redirect  *RedirectOptionsRequest  `json:"redirect"`
+	GRPC      *GRPCOptionsRequest      `json:"grpc"`
+	WebSocket *WebSocketOptionsRequest `json:"websocket"`
+	Proxy     *ProxyOptionsRequest     `json:"proxy"`
+	Network   *NetworkOptionsRequest   `json:"network"`
+	// steps of a transaction monitor
+	Steps []StepRequest `json:"steps" validate:"omitempty,lte=10,dive"`
+	// credentials of check, write only, never returned back
+	Auth *AuthRequest `json:"auth"`
+}
+
+type AuthRequest struct {
+	Type       string `json:"type" validate:"required,oneof=basic bearer mtls"`
+	Username   string `json:"username" validate:"max=256"`
+	Password   string `json:"password" validate:"max=1024"`
+	Token      string `json:"token" validate:"max=4096"`
+	ClientCert string `json:"client_cert" validate:"max=65536"`
+	ClientKey  string `json:"client_key" validate:"max=65536"`
+}
+
+type RedirectOptionsRequest struct {
+	Follow         *bool  `json:"follow"` // defaults to true
+	MaxHops        int    `json:"max_hops" validate:"gte=0,lte=30"`
+	ExpectFinalURL string `json:"expect_final_url" validate:"omitempty,max=2048,url"`
+}
+
+type StepRequest struct {
+	Name           string             `json:"name" validate:"max=64"`
+	Method         string             `json:"method" validate:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Url            string             `json:"url" validate:"required,max=2048"`
+	Headers        map[string]string  `json:"headers" validate:"omitempty,lte=50,dive,keys,required,max=256,endkeys,max=4096"`
+	Body           string             `json:"body" validate:"max=65536"`
+	ExpectedStatus ExpectedStatus     `json:"expected_status" validate:"omitempty,max=256,status_spec"` // defaults to 2xx
+	Assertions     []AssertionRequest `json:"assertions" validate:"omitempty,lte=20,dive"`
+	// values stored from response, used in later steps as {{name}}
+	Extract []ExtractionRequest `json:"extract" validate:"omitempty,lte=20,dive"`
+}
+
+type ExtractionRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+	From string `json:"from" validate:"required,oneof=json_path header cookie"`
+	Path string `json:"path" validate:"required,max=512"`
+}
+
+type NetworkOptionsRequest struct {
+	IPVersion string            `json:"ip_version" validate:"omitempty,oneof=ipv4 ipv6 both"`
+	Resolver  string            `json:"resolver" validate:"max=256"`
+	Hosts     map[string]string `json:"hosts" validate:"omitempty,lte=20,dive,keys,required,max=256,endkeys,ip"`
+}
+
+type ProxyOptionsRequest struct {
+	URL    string `json:"url" validate:"omitempty,max=2048,url"`
+	Direct bool   `json:"direct"`
+}
+
+type WebSocketOptionsRequest struct {
+	Send        string `json:"send" validate:"max=4096"`
+	ExpectReply string `json:"expect_reply" validate:"max=4096"`
+}
+
+type GRPCOptionsRequest struct {
+	Service string `json:"service" validate:"max=256"`
+	TLS     bool   `json:"tls"`
+}
+
+type HeartbeatOptionsRequest struct {
+	GraceSec int32 `json:"grace_sec" validate:"gte=0,lte=86400"`
+}
+
+type TLSOptionsRequest struct {
+	ExpiryAlertDays int `json:"expiry_alert_days" validate:"gte=0,lte=365"`
+}
+
+type TCPOptionsRequest struct {
+	ExpectBanner string `json:"expect_banner" validate:"max=256"`
+}
+
+type DNSOptionsRequest struct {
+	RecordType string   `json:"record_type" validate:"required,oneof=A AAAA CNAME MX TXT NS"`
+	Resolver   string   `json:"resolver" validate:"max=256"`
+	Expected   []string `json:"expected" validate:"omitempty,lte=50,dive,required,max=1024"`
+}
+
+type AssertionRequest struct {
+	Type  string `json:"type" validate:"required,oneof=body_contains body_not_contains body_regex json_path_equals json_path_exists header_equals"`
+	Path  string `json:"path" validate:"max=256"`
+	Value string `json:"value" validate:"max=1024"`
 }
 
 type CreateMonitorResponse struct {
-	MonitorID string `json:"monitor_id"`
+	MonitorID    string `json:"monitor_id"`
+	HeartbeatURL string `json:"heartbeat_url,omitempty"`
 }
 
 type GetMonitorResponse struct {
-	ID                 string `json:"id"`
-	Url                string `json:"url"`
-	AlertEmail         string `json:"alert_mail"`
-	IntervalSec        int32  `json:"interval_sec"`
-	TimeoutSec         int32  `json:"timeout_sec"`
-	LatencyThresholdMs int32  `json:"latency_threshold_ms"`
-	ExpectedStatus     int32  `json:"expected_status"`
-	Enabled            bool   `json:"enabled"`
+	ID                 string            `json:"id"`
+	Type               string            `json:"type"`
+	Url                string            `json:"url"`
+	AlertEmail         string            `json:"alert_mail"`
+	IntervalSec        int32             `json:"interval_sec"`
+	TimeoutSec         int32             `json:"timeout_sec"`
+	LatencyThresholdMs int32             `json:"latency_threshold_ms"`
+	ExpectedStatus     string            `json:"expected_status"`
+	Method             string            `json:"method"`
+	Headers            map[string]string `json:"headers,omitempty"`
+	Body               string            `json:"body,omitempty"`
+	Assertions         []Assertion       `json:"assertions,omitempty"`
+	Options            Options           `json:"options"`
+	Auth               *AuthSummary      `json:"auth,omitempty"`
+	HeartbeatURL       string            `json:"heartbeat_url,omitempty"`
+	Enabled            bool              `json:"enabled"`
+}
+
+func toMonitorResponse(mon *Monitor) GetMonitorResponse {
+	return GetMonitorResponse{
+		ID:                 mon.ID.String(),
+		Type:               mon.Type,
+		Url:                mon.Url,
+		AlertEmail:         mon.AlertEmail,
+		IntervalSec:        mon.IntervalSec,
+		TimeoutSec:         mon.TimeoutSec,
+		LatencyThresholdMs: mon.LatencyThresholdMs,
+		ExpectedStatus:     mon.ExpectedStatus,
+		Method:             mon.Method,
+		Headers:            redactHeaders(mon.Headers),
+		Body:               redactBody(mon.Body),
+		Assertions:         mon.Assertions,
+		Options:            mon.Options.redacted(),
+		Auth:               mon.Auth.Summary(),
+		HeartbeatURL:       heartbeatURL(mon.HeartbeatToken),
+		Enabled:            mon.Enabled,
+	}
 }
 
 type GetAllMonitorsResponse struct {
@@ -34,3 +166,109 @@ type GetAllMonitorsResponse struct {
 type UpdateMonitorStatusRequest struct {
 	Enable bool `json:"enable" validate:"required"`
 }
+
+func toAssertions(reqs []AssertionRequest) []Assertion {
+	if len(reqs) == 0 {
+		return nil
+	}
+	a := make([]Assertion, 0, len(reqs))
+	for _, r := range reqs {
+		a = append(a, Assertion{
+			Type:  AssertionType(r.Type),
+			Path:  r.Path,
+			Value: r.Value,
+		})
+	}
+	return a
+}
+
+func toOptions(req *CreateMonitorRequest) Options {
+	var o Options
+	if req.TCP != nil {
+		o.TCP = &TCPOptions{ExpectBanner: req.TCP.ExpectBanner}
+	}
+	if req.GRPC != nil {
+		o.GRPC = &GRPCOptions{Service: req.GRPC.Service, TLS: req.GRPC.TLS}
+	}
+	if len(req.Steps) > 0 {
+		o.Transaction = &TransactionOptions{Steps: toSteps(req.Steps)}
+	}
+	if req.Network != nil {
+		o.Network = &NetworkOptions{
+			IPVersion: req.Network.IPVersion,
+			Resolver:  req.Network.Resolver,
+			Hosts:     req.Network.Hosts,
+		}
+	}
+	if req.Proxy != nil {
+		o.Proxy = &ProxyOptions{URL: req.Proxy.URL, Direct: req.Proxy.Direct}
+	}
+	if req.WebSocket != nil {
+		o.WebSocket = &WebSocketOptions{Send: req.WebSocket.Send, ExpectReply: req.WebSocket.ExpectReply}
+	}
+	if req.Heartbeat != nil {
+		o.Heartbeat = &HeartbeatOptions{GraceSec: req.Heartbeat.GraceSec}
+	}
+	if req.Redirect != nil {
+		follow := req.Redirect.Follow == nil || *req.Redirect.Follow
+		o.Redirect = &RedirectOptions{
+			Follow:         follow,
+			MaxHops:        req.Redirect.MaxHops,
+			ExpectFinalURL: req.Redirect.ExpectFinalURL,
+		}
+	}
+	if req.TLS != nil {
+		o.TLS = &TLSOptions{ExpiryAlertDays: req.TLS.ExpiryAlertDays}
+	}
+	if req.DNS != nil {
+		o.DNS = &DNSOptions{
+			RecordType: req.DNS.RecordType,
+			Resolver:   req.DNS.Resolver,
+			Expected:   req.DNS.Expected,
+		}
+	}
+	return o
+}
+
+func toSteps(reqs []StepRequest) []Step {
+	steps := make([]Step, 0, len(reqs))
+	for _, r := range reqs {
+		extract := make([]Extraction, 0, len(r.Extract))
+		for _, e := range r.Extract {
+			extract = append(extract, Extraction{Name: e.Name, From: ExtractFrom(e.From), Path: e.Path})
+		}
+		steps = append(steps, Step{
+			Name:           r.Name,
+			Method:         r.Method,
+			Url:            r.Url,
+			Headers:        r.Headers,
+			Body:           r.Body,
+			ExpectedStatus: string(r.ExpectedStatus),
+			Assertions:     toAssertions(r.Assertions),
+			Extract:        extract,
+		})
+	}
+	return steps
+}
+
+func toAuth(req *AuthRequest) *Auth {
+	if req == nil {
+		return nil
+	}
+	return &Auth{
+		Type:       AuthType(req.Type),
+		Username:   req.Username,
+		Password:   req.Password,
+		Token:      req.Token,
+		ClientCert: req.ClientCert,
+		ClientKey:  req.ClientKey,
+	}
+}
+
+// heartbeatURL is the path, jobs ping for a heartbeat monitor
+func heartbeatURL(token string) string {
+	if token == "" {
+		return ""
+	}
+	return "/api/v1/heartbeat/" + token
+}
@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestCreateMonitorRequestValidation(t *testing.T) {
+	v := validator.New()
+	if err := RegisterValidations(v); err != nil {
+		t.Fatal(err)
+	}
+	valid := func() CreateMonitorRequest {
+		return CreateMonitorRequest{
+			Url:                "https://example.com/health",
+			AlertEmail:         "ops@example.com",
+			IntervalSec:        60,
+			TimeoutSec:         10,
+			LatencyThresholdMs: 500,
+			ExpectedStatus:     "200",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(r *CreateMonitorRequest)
+		wantErr bool
+	}{
+		{name: "no method, headers or body", edit: func(*CreateMonitorRequest) {}},
+		{name: "post with body", edit: func(r *CreateMonitorRequest) {
+			r.Method, r.Body = "POST", `{"ping":true}`
+			r.Headers = map[string]string{"Content-Type": "application/json"}
+		}},
+		{name: "unknown method", edit: func(r *CreateMonitorRequest) { r.Method = "TRACE" }, wantErr: true},
+		{name: "lowercase method", edit: func(r *CreateMonitorRequest) { r.Method = "get" }, wantErr: true},
+		{name: "empty header name", edit: func(r *CreateMonitorRequest) { r.Headers = map[string]string{"": "x"} }, wantErr: true},
+		{name: "header name too long", edit: func(r *CreateMonitorRequest) { r.Headers = map[string]string{strings.Repeat("a", 257): "x"} }, wantErr: true},
+		{name: "header value too long", edit: func(r *CreateMonitorRequest) { r.Headers = map[string]string{"X-Big": strings.Repeat("a", 4097)} }, wantErr: true},
+		{name: "too many headers", edit: func(r *CreateMonitorRequest) {
+			r.Headers = map[string]string{}
+			for i := 0; i < 51; i++ {
+				r.Headers[strings.Repeat("h", i+1)] = "x"
+			}
+		}, wantErr: true},
+		{name: "body at limit", edit: func(r *CreateMonitorRequest) { r.Method, r.Body = "PUT", strings.Repeat("a", 65536) }},
+		{name: "body too long", edit: func(r *CreateMonitorRequest) { r.Method, r.Body = "PUT", strings.Repeat("a", 65537) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.edit(&r)
+			if err := v.Struct(r); (err != nil) != tt.wantErr {
+				t.Errorf("validate err = %v, want err %v", err, tt.wantErr)
+			}
+		})
+	}
+}
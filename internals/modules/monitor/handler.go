@@ -53,14 +53,21 @@ func (h *Handler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	mID, err := h.service.CreateMonitor(ctx, CreateMonitorCmd{
+	res, err := h.service.CreateMonitor(ctx, CreateMonitorCmd{
 		UserID:             reqClaims.UserID,
+		Type:               req.Type,
 		Url:                req.Url,
 		IntervalSec:        req.IntervalSec,
 		TimeoutSec:         req.TimeoutSec,
 		LatencyThresholdMs: req.LatencyThresholdMs,
-		ExpectedStatus:     req.ExpectedStatus,
+		ExpectedStatus:     string(req.ExpectedStatus),
 		AlertEmail:         req.AlertEmail,
+		Method:             req.Method,
+		Headers:            req.Headers,
+		Body:               req.Body,
+		Assertions:         toAssertions(req.Assertions),
+		Options:            toOptions(&req),
+		Auth:               toAuth(req.Auth),
 	})
 	if err != nil {
 		h.logger.Error().
@@ -71,7 +78,10 @@ func (h *Handler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		utils.FromAppError(w, reqID, err)
 		return
 	}
-	utils.WriteJSON(w, http.StatusCreated, reqID, "monitor created successfully", CreateMonitorResponse{MonitorID: mID.String()})
+	utils.WriteJSON(w, http.StatusCreated, reqID, "monitor created successfully", CreateMonitorResponse{
+		MonitorID:    res.MonitorID.String(),
+		HeartbeatURL: heartbeatURL(res.HeartbeatToken),
+	})
 }
 
 func (h *Handler) GetMonitor(w http.ResponseWriter, r *http.Request) {
@@ -102,16 +112,7 @@ func (h *Handler) GetMonitor(w http.ResponseWriter, r *http.Request) {
 		utils.FromAppError(w, reqID, err)
 		return
 	}
-	m := GetMonitorResponse{
-		ID:                 mon.ID.String(),
-		Url:                mon.Url,
-		AlertEmail:         mon.AlertEmail,
-		IntervalSec:        mon.IntervalSec,
-		TimeoutSec:         mon.TimeoutSec,
-		LatencyThresholdMs: mon.LatencyThresholdMs,
-		ExpectedStatus:     mon.ExpectedStatus,
-		Enabled:            mon.Enabled,
-	}
+	m := toMonitorResponse(&mon)
 
 	utils.WriteJSON(w, http.StatusOK, reqID, "monitor retrieved successfully", m)
 }
@@ -161,17 +162,7 @@ func (h *Handler) GetAllMonitors(w http.ResponseWriter, r *http.Request) {
 	}
 	m := make([]GetMonitorResponse, 0, len(monitors))
 	for i := range monitors {
-		mon := &monitors[i]
-		m = append(m, GetMonitorResponse{
-			ID:                 mon.ID.String(),
-			Url:                mon.Url,
-			IntervalSec:        mon.IntervalSec,
-			TimeoutSec:         mon.TimeoutSec,
-			LatencyThresholdMs: mon.LatencyThresholdMs,
-			ExpectedStatus:     mon.ExpectedStatus,
-			Enabled:            mon.Enabled,
-			AlertEmail:         mon.AlertEmail,
-		})
+		m = append(m, toMonitorResponse(&monitors[i]))
 	}
 
 	resp := GetAllMonitorsResponse{
@@ -233,3 +224,30 @@ func (h *Handler) UpdateMonitorStatus(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJSON(w, http.StatusOK, reqID, "monitor status updated successfully", "ok")
 }
+
+// POST|GET : /heartbeat/{token}
+//
+// ping endpoint of heartbeat monitors, token itself authenticates the caller
+func (h *Handler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.heartbeat"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	token := chi.URLParam(r, "token")
+	if token == "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	if err := h.service.RecordHeartbeat(ctx, token); err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("recording heartbeat error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "heartbeat received", "ok")
+}
@@ -2,9 +2,11 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
 	"project-k/pkg/apperror"
 	"project-k/pkg/db"
 	"project-k/pkg/utils"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -12,12 +14,14 @@ import (
 
 type Repository struct {
 	querier *db.Queries
+	sealer  Sealer // auth is stored sealed
 	log     *zerolog.Logger
 }
 
-func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+func NewRepository(dbExecutor db.DBTX, sealer Sealer, logger *zerolog.Logger) *Repository {
 	return &Repository{
 		querier: db.New(dbExecutor),
+		sealer:  sealer,
 		log:     logger,
 	}
 }
@@ -25,6 +29,23 @@ func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
 func (r *Repository) Create(ctx context.Context, monitor CreateMonitorCmd) (uuid.UUID, error) {
 	const op string = "repo.monitor.create"
 
+	headers, err := json.Marshal(monitor.Headers)
+	if err != nil {
+		return uuid.UUID{}, apperror.New(apperror.Internal, op, err)
+	}
+	assertions, err := json.Marshal(monitor.Assertions)
+	if err != nil {
+		return uuid.UUID{}, apperror.New(apperror.Internal, op, err)
+	}
+	options, err := json.Marshal(monitor.Options)
+	if err != nil {
+		return uuid.UUID{}, apperror.New(apperror.Internal, op, err)
+	}
+	auth, err := sealAuth(r.sealer, monitor.Auth) // NULL if monitor has no auth
+	if err != nil {
+		return uuid.UUID{}, apperror.New(apperror.Internal, op, err)
+	}
+
 	monitorID, err := r.querier.CreateMonitor(ctx, db.CreateMonitorParams{
 		UserID:             utils.ToPgUUID(monitor.UserID),
 		Url:                monitor.Url,
@@ -33,6 +54,14 @@ func (r *Repository) Create(ctx context.Context, monitor CreateMonitorCmd) (uuid
 		LatencyThresholdMs: monitor.LatencyThresholdMs,
 		ExpectedStatus:     monitor.ExpectedStatus,
 		AlertEmail:         utils.ToPgText(monitor.AlertEmail),
+		Method:             monitor.Method,
+		Headers:            headers,
+		Body:               utils.ToPgText(monitor.Body),
+		Assertions:         assertions,
+		Type:               monitor.Type,
+		Options:            options,
+		HeartbeatToken:     utils.ToPgText(monitor.HeartbeatToken),
+		Auth:               auth,
 	})
 	if err == nil {
 		return utils.FromPgUUID(monitorID), nil
@@ -46,17 +75,7 @@ func (r *Repository) GetByID(ctx context.Context, monitorID uuid.UUID) (Monitor,
 
 	monitor, err := r.querier.GetMonitorByID(ctx, utils.ToPgUUID(monitorID))
 	if err == nil {
-		return Monitor{
-			ID:                 utils.FromPgUUID(monitor.ID),
-			UserID:             utils.FromPgUUID(monitor.UserID),
-			Url:                monitor.Url,
-			IntervalSec:        monitor.IntervalSec,
-			TimeoutSec:         monitor.TimeoutSec,
-			LatencyThresholdMs: monitor.LatencyThresholdMs,
-			ExpectedStatus:     monitor.ExpectedStatus,
-			Enabled:            monitor.Enabled,
-			AlertEmail:         utils.FromPgText(monitor.AlertEmail),
-		}, nil
+		return r.toMonitor(op, &monitor)
 	}
 
 	return Monitor{}, utils.WrapRepoError(op, err, true, r.log)
@@ -70,17 +89,19 @@ func (r *Repository) Get(ctx context.Context, userID, monitorID uuid.UUID) (Moni
 		UserID: utils.ToPgUUID(userID),
 	})
 	if err == nil {
-		return Monitor{
-			ID:                 utils.FromPgUUID(monitor.ID),
-			UserID:             utils.FromPgUUID(monitor.UserID),
-			Url:                monitor.Url,
-			IntervalSec:        monitor.IntervalSec,
-			TimeoutSec:         monitor.TimeoutSec,
-			LatencyThresholdMs: monitor.LatencyThresholdMs,
-			ExpectedStatus:     monitor.ExpectedStatus,
-			Enabled:            monitor.Enabled,
-			AlertEmail:         utils.FromPgText(monitor.AlertEmail),
-		}, nil
+		return r.toMonitor(op, &monitor)
+	}
+
+	return Monitor{}, utils.WrapRepoError(op, err, true, r.log)
+}
+
+// GetByHeartbeatToken finds the heartbeat monitor which owns the given ping token
+func (r *Repository) GetByHeartbeatToken(ctx context.Context, token string) (Monitor, error) {
+	const op string = "repo.monitor.get_by_heartbeat_token"
+
+	monitor, err := r.querier.GetMonitorByHeartbeatToken(ctx, utils.ToPgText(token))
+	if err == nil {
+		return r.toMonitor(op, &monitor)
 	}
 
 	return Monitor{}, utils.WrapRepoError(op, err, true, r.log)
@@ -100,18 +121,11 @@ func (r *Repository) GetAll(ctx context.Context, userID uuid.UUID, limit int32,
 		}
 		m := make([]Monitor, 0, len(monitors))
 		for i := range monitors {
-			mon := &monitors[i]
-			m = append(m, Monitor{
-				ID:                 utils.FromPgUUID(mon.ID),
-				UserID:             utils.FromPgUUID(mon.UserID),
-				Url:                mon.Url,
-				IntervalSec:        mon.IntervalSec,
-				TimeoutSec:         mon.TimeoutSec,
-				LatencyThresholdMs: mon.LatencyThresholdMs,
-				ExpectedStatus:     mon.ExpectedStatus,
-				Enabled:            mon.Enabled,
-				AlertEmail:         utils.FromPgText(mon.AlertEmail),
-			})
+			mon, err := r.toMonitor(op, &monitors[i])
+			if err != nil {
+				return []Monitor{}, err
+			}
+			m = append(m, mon)
 		}
 		return m, nil
 	}
@@ -152,3 +166,89 @@ func (r *Repository) SetEnabled(ctx context.Context, userID, monitorID uuid.UUID
 
 	return utils.WrapRepoError(op, err, false, r.log)
 }
+
+// CloseIncidents closes open incidents of monitor at endTime (never before their start), returns how many were closed
+func (r *Repository) CloseIncidents(ctx context.Context, monitorID uuid.UUID, endTime time.Time) (int, error) {
+	const op string = "repo.monitor.close_incidents"
+
+	closed, err := r.querier.CloseMonitorIncident(ctx, db.CloseMonitorIncidentParams{
+		MonitorID: utils.ToPgUUID(monitorID),
+		EndTime:   utils.ToPgTimestamptz(endTime),
+	})
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, false, r.log)
+	}
+	return len(closed), nil
+}
+
+// SealPlainAuth encrypts auth of monitors stored before sealing existed, returns how many were sealed
+func (r *Repository) SealPlainAuth(ctx context.Context) (int, error) {
+	const op string = "repo.monitor.seal_plain_auth"
+
+	rows, err := r.querier.ListMonitorsWithPlainAuth(ctx)
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, false, r.log)
+	}
+	for i, row := range rows {
+		auth, err := openAuth(r.sealer, row.Auth)
+		if err != nil {
+			return i, apperror.New(apperror.Internal, op, err)
+		}
+		sealed, err := sealAuth(r.sealer, auth)
+		if err != nil {
+			return i, apperror.New(apperror.Internal, op, err)
+		}
+		if err := r.querier.UpdateMonitorAuth(ctx, db.UpdateMonitorAuthParams{ID: row.ID, Auth: sealed}); err != nil {
+			return i, utils.WrapRepoError(op, err, false, r.log)
+		}
+	}
+	return len(rows), nil
+}
+
+// toMonitor maps a DB monitor row to domain Monitor
+func (r *Repository) toMonitor(op string, monitor *db.Monitor) (Monitor, error) {
+	var headers map[string]string
+	if len(monitor.Headers) > 0 {
+		if err := json.Unmarshal(monitor.Headers, &headers); err != nil {
+			return Monitor{}, apperror.New(apperror.Internal, op, err)
+		}
+	}
+	var assertions []Assertion
+	if len(monitor.Assertions) > 0 {
+		if err := json.Unmarshal(monitor.Assertions, &assertions); err != nil {
+			return Monitor{}, apperror.New(apperror.Internal, op, err)
+		}
+	}
+	var options Options
+	if len(monitor.Options) > 0 {
+		if err := json.Unmarshal(monitor.Options, &options); err != nil {
+			return Monitor{}, apperror.New(apperror.Internal, op, err)
+		}
+	}
+
+	auth, err := openAuth(r.sealer, monitor.Auth)
+	if err != nil {
+		return Monitor{}, apperror.New(apperror.Internal, op, err)
+	}
+
+	return Monitor{
+		ID:                 utils.FromPgUUID(monitor.ID),
+		UserID:             utils.FromPgUUID(monitor.UserID),
+		Type:               monitor.Type,
+		Url:                monitor.Url,
+		IntervalSec:        monitor.IntervalSec,
+		TimeoutSec:         monitor.TimeoutSec,
+		LatencyThresholdMs: monitor.LatencyThresholdMs,
+		ExpectedStatus:     monitor.ExpectedStatus,
+		Method:             monitor.Method,
+		Headers:            headers,
+		Body:               utils.FromPgText(monitor.Body),
+		Assertions:         assertions,
+		Options:            options,
+		Auth:               auth,
+		HeartbeatToken:     utils.FromPgText(monitor.HeartbeatToken),
+		Enabled:            monitor.Enabled,
+		AlertEmail:         utils.FromPgText(monitor.AlertEmail),
+		CreatedAt:          utils.FromPgTimestamptz(monitor.CreatedAt),
+	}, nil
+}
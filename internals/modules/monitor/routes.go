@@ -13,6 +13,16 @@ func Routes(h *Handler) chi.Router {
 	return r
 }
 
+// HeartbeatRoutes are public, the secret token in url identifies the monitor
+func HeartbeatRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/{token}", h.Heartbeat)
+	r.Get("/{token}", h.Heartbeat)
+
+	return r
+}
+
 
 /*
 - POST: /monitors  -> create monitor
@@ -34,4 +44,9 @@ func Routes(h *Handler) chi.Router {
 	req auth : true
 	body : UpdateMonitorStatusRequest
 	resp : ok / error
+
+- POST|GET: /heartbeat/{token} -> ping of a heartbeat monitor
+	req auth : false (token in url)
+	body : nil
+	resp : ok / error
 */
\ No newline at end of file
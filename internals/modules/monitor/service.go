@@ -2,8 +2,14 @@ package monitor
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"project-k/pkg/apperror"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,7 +36,7 @@ func NewService(monitorRepo *Repository, cache Cache, userSvc UserService, logge
 	}
 }
 
-func (s *Service) CreateMonitor(ctx context.Context, data CreateMonitorCmd) (uuid.UUID, error) {
+func (s *Service) CreateMonitor(ctx context.Context, data CreateMonitorCmd) (CreateMonitorResult, error) {
 
 	/*
 		- 1 Increment Monitor count if possible
@@ -44,20 +50,94 @@ func (s *Service) CreateMonitor(ctx context.Context, data CreateMonitorCmd) (uui
 
 	const op string = "service.monitor.create_monitor"
 
+	if data.Method == "" {
+		data.Method = http.MethodGet
+	}
+
+	if data.Type == "" {
+		data.Type = TypeHTTP
+	}
+	if err := validateTarget(data.Type, data.Url); err != nil {
+		return CreateMonitorResult{}, &apperror.Error{
+			Kind:    apperror.InvalidInput,
+			Op:      op,
+			Message: "invalid url: " + err.Error(),
+		}
+	}
+	if err := validateOptions(data.Type, data.Url, data.Options); err != nil {
+		return CreateMonitorResult{}, &apperror.Error{
+			Kind:    apperror.InvalidInput,
+			Op:      op,
+			Message: "invalid options: " + err.Error(),
+		}
+	}
+
+	// store expected status in canonical form, it is only used by http monitors
+	if data.Type == TypeHTTP || data.ExpectedStatus != "" {
+		matcher, err := ParseStatusMatcher(data.ExpectedStatus)
+		if err != nil {
+			return CreateMonitorResult{}, &apperror.Error{
+				Kind:    apperror.InvalidInput,
+				Op:      op,
+				Message: "invalid expected status: " + err.Error(),
+			}
+		}
+		data.ExpectedStatus = matcher.String()
+	}
+
+	for _, a := range data.Assertions {
+		if err := a.Validate(); err != nil {
+			return CreateMonitorResult{}, &apperror.Error{
+				Kind:    apperror.InvalidInput,
+				Op:      op,
+				Message: "invalid assertion: " + err.Error(),
+			}
+		}
+	}
+
+	if data.Auth != nil {
+		if data.Type != TypeHTTP && data.Type != TypeTransaction {
+			return CreateMonitorResult{}, &apperror.Error{
+				Kind:    apperror.InvalidInput,
+				Op:      op,
+				Message: "auth is only supported for http and transaction monitors",
+			}
+		}
+		if err := data.Auth.Validate(); err != nil {
+			return CreateMonitorResult{}, &apperror.Error{
+				Kind:    apperror.InvalidInput,
+				Op:      op,
+				Message: "invalid auth: " + err.Error(),
+			}
+		}
+	}
+
+	if data.Type == TypeHeartbeat {
+		token, err := newHeartbeatToken()
+		if err != nil {
+			return CreateMonitorResult{}, apperror.New(apperror.Internal, op, err)
+		}
+		data.HeartbeatToken = token
+	}
+
 	err := s.userSvc.IncrementMonitorCount(ctx, data.UserID)
 	if err != nil {
-		return uuid.UUID{}, err
+		return CreateMonitorResult{}, err
 	}
 
 	// now create monitor
 	monitorID, err := s.monitorRepo.Create(ctx, data)
 	if err != nil {
-		return uuid.UUID{}, err
+		return CreateMonitorResult{}, err
 	}
 
-	s.ScheduleMonitor(ctx, monitorID, data.IntervalSec, op)
+	s.ScheduleMonitor(ctx, monitorID, firstCheckDelay(&Monitor{
+		Type:        data.Type,
+		IntervalSec: data.IntervalSec,
+		Options:     data.Options,
+	}), op)
 
-	return monitorID, nil
+	return CreateMonitorResult{MonitorID: monitorID, HeartbeatToken: data.HeartbeatToken}, nil
 }
 
 func (s *Service) GetMonitor(ctx context.Context, userID uuid.UUID, monitorID uuid.UUID) (Monitor, error) {
@@ -167,7 +247,7 @@ func (s *Service) UpdateMonitorStatus(ctx context.Context, userID, monitorID uui
 
 	// 4. Side effects (best effort)
 	if enable {
-		s.ScheduleMonitor(ctx, m.ID, m.IntervalSec, op)
+		s.ScheduleMonitor(ctx, m.ID, firstCheckDelay(&m), op)
 	} else {
 		s.disableMonitor(ctx, monitorID)
 	}
@@ -175,6 +255,37 @@ func (s *Service) UpdateMonitorStatus(ctx context.Context, userID, monitorID uui
 	return true, nil
 }
 
+// RecordHeartbeat stores a ping of the heartbeat monitor owning the token. a down monitor is checked right away,
+// so it recovers without waiting, an up one keeps its deadline (a check per ping would flood history)
+func (s *Service) RecordHeartbeat(ctx context.Context, token string) error {
+	const op = "service.monitor.record_heartbeat"
+
+	m, err := s.monitorRepo.GetByHeartbeatToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	// pings of a disabled monitor are accepted but ignored
+	if !m.Enabled {
+		return nil
+	}
+
+	if err := s.cache.StoreHeartbeat(ctx, m.ID, time.Now()); err != nil {
+		return apperror.New(apperror.Internal, op, err)
+	}
+
+	down, err := s.cache.HasIncident(ctx, m.ID)
+	if err != nil {
+		// ping is stored, scheduled check still sees it
+		s.logger.Error().Str("op", op).Err(err).Msg("failed to get incident of heartbeat monitor")
+		return nil
+	}
+	if down {
+		s.ScheduleMonitor(ctx, m.ID, 0, op)
+	}
+	return nil
+}
+
 func (s *Service) ScheduleMonitor(ctx context.Context, mID uuid.UUID, intervalSec int32, op string) {
 
 	nextRun := time.Now().Add(time.Duration(intervalSec) * time.Second)
@@ -192,6 +303,8 @@ func (s *Service) ScheduleMonitor(ctx context.Context, mID uuid.UUID, intervalSe
 }
 
 func (s *Service) disableMonitor(ctx context.Context, monitorID uuid.UUID) {
+	// close DB incident (if any) before its redis state is gone
+	s.closeIncidents(ctx, monitorID)
 	// delete cached monitor (if any)
 	_ = s.cache.DelMonitor(ctx, monitorID.String())
 	// delete scheduled entry
@@ -200,10 +313,51 @@ func (s *Service) disableMonitor(ctx context.Context, monitorID uuid.UUID) {
 	_ = s.cache.ClearIncident(ctx, monitorID)
 	// delete status entry
 	_ = s.cache.DelStatus(ctx, monitorID)
+	// delete last heartbeat (if any), re-enabled monitor waits for a fresh ping
+	_ = s.cache.DelHeartbeat(ctx, monitorID)
+}
+
+// closeIncidents closes open DB incidents of a monitor stopped while down at its last failed check,
+// an incident left open would count as downtime in every later uptime report
+func (s *Service) closeIncidents(ctx context.Context, monitorID uuid.UUID) {
+	const op = "service.monitor.close_incidents"
+
+	endTime := time.Now()
+	if incident, err := s.cache.GetIncident(ctx, monitorID); err == nil {
+		if ts, err := strconv.ParseInt(incident["last_failure_at"], 10, 64); err == nil {
+			endTime = time.Unix(ts, 0)
+		}
+	}
+	if _, err := s.monitorRepo.CloseIncidents(ctx, monitorID, endTime); err != nil {
+		s.logger.Error().
+			Str("op", op).
+			Str("monitor_id", monitorID.String()).
+			Err(err).
+			Msg("failed to close incidents of stopped monitor")
+	}
+}
+
+// newHeartbeatToken generates the secret url token of a heartbeat monitor
+func newHeartbeatToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// cachedMonitor is a monitor as kept in redis, its auth shadows Monitor.Auth so credentials are cached sealed
+type cachedMonitor struct {
+	Monitor
+	Auth json.RawMessage `json:",omitempty"`
 }
 
 func (s *Service) cacheMonitor(ctx context.Context, m Monitor) error {
-	data, err := json.Marshal(m)
+	auth, err := sealAuth(s.monitorRepo.sealer, m.Auth)
+	if err != nil {
+		return fmt.Errorf("failed to seal monitor auth for cache: %w", err)
+	}
+	data, err := json.Marshal(cachedMonitor{Monitor: m, Auth: auth})
 	if err != nil {
 		return fmt.Errorf("failed to marshal monitor for cache: %w", err)
 	}
@@ -215,9 +369,17 @@ func (s *Service) getCachedMonitor(ctx context.Context, id uuid.UUID) (Monitor,
 	if err != nil {
 		return Monitor{}, err
 	}
-	var m Monitor
-	if err := json.Unmarshal(data, &m); err != nil {
+	var cm cachedMonitor
+	if err := json.Unmarshal(data, &cm); err != nil {
 		return Monitor{}, err
 	}
-	return m, nil
+	var stored sealedAuth
+	if len(cm.Auth) > 0 && (json.Unmarshal(cm.Auth, &stored) != nil || stored.Sealed == "") {
+		// cached before sealing existed, treat as a miss so it is re cached sealed
+		return Monitor{}, errors.New("monitor cached with plaintext auth")
+	}
+	if cm.Monitor.Auth, err = openAuth(s.monitorRepo.sealer, cm.Auth); err != nil {
+		return Monitor{}, err
+	}
+	return cm.Monitor, nil
 }
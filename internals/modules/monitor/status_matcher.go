@@ -0,0 +1,155 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+/*
+ Expected status spec => comma separated list of
+	 - exact code   : 200
+	 - class        : 2xx
+	 - range        : 200-299
+ ex: "200,204,3xx"
+*/
+
+type statusRange struct {
+	min int
+	max int
+}
+
+// StatusMatcher matches a HTTP status code against a parsed expected status spec
+type StatusMatcher []statusRange
+
+// ParseStatusMatcher parses an expected status spec
+func ParseStatusMatcher(spec string) (StatusMatcher, error) {
+	var m StatusMatcher
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		r, err := parseStatusPart(part)
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, r)
+	}
+
+	if len(m) == 0 {
+		return nil, errors.New("expected status is empty")
+	}
+	return m, nil
+}
+
+// Match reports if code is expected
+func (m StatusMatcher) Match(code int) bool {
+	for _, r := range m {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders matcher in canonical spec form
+func (m StatusMatcher) String() string {
+	parts := make([]string, 0, len(m))
+	for _, r := range m {
+		switch {
+		case r.min == r.max:
+			parts = append(parts, strconv.Itoa(r.min))
+		case r.min%100 == 0 && r.max == r.min+99:
+			parts = append(parts, fmt.Sprintf("%dxx", r.min/100))
+		default:
+			parts = append(parts, fmt.Sprintf("%d-%d", r.min, r.max))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseStatusPart(part string) (statusRange, error) {
+	// class -> 2xx
+	if len(part) == 3 && strings.HasSuffix(part, "xx") {
+		class, err := strconv.Atoi(part[:1])
+		if err != nil || class < 1 || class > 5 {
+			return statusRange{}, fmt.Errorf("invalid status class %q", part)
+		}
+		return statusRange{min: class * 100, max: class*100 + 99}, nil
+	}
+
+	// range -> 200-299
+	if lo, hi, ok := strings.Cut(part, "-"); ok {
+		min, err1 := parseStatusCode(lo)
+		max, err2 := parseStatusCode(hi)
+		if err1 != nil || err2 != nil || min > max {
+			return statusRange{}, fmt.Errorf("invalid status range %q", part)
+		}
+		return statusRange{min: min, max: max}, nil
+	}
+
+	// exact -> 200
+	code, err := parseStatusCode(part)
+	if err != nil {
+		return statusRange{}, err
+	}
+	return statusRange{min: code, max: code}, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %q", s)
+	}
+	return code, nil
+}
+
+// ExpectedStatus is the expected status spec in requests, it accepts a single code (200),
+// a spec string ("200,204,3xx") or a list of both ([200, "2xx", "300-399"])
+type ExpectedStatus string
+
+func (e *ExpectedStatus) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		*e = ExpectedStatus(strconv.Itoa(code))
+		return nil
+	}
+
+	var spec string
+	if err := json.Unmarshal(data, &spec); err == nil {
+		*e = ExpectedStatus(spec)
+		return nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return errors.New("expected_status must be a code, a string or a list")
+	}
+	parts := make([]string, 0, len(list))
+	for _, item := range list {
+		var p ExpectedStatus
+		if err := p.UnmarshalJSON(item); err != nil {
+			return err
+		}
+		parts = append(parts, string(p))
+	}
+	*e = ExpectedStatus(strings.Join(parts, ","))
+	return nil
+}
+
+// RegisterValidations registers monitor specific validation tags
+//
+//	status_spec -> field is a valid expected status spec
+func RegisterValidations(v *validator.Validate) error {
+	return v.RegisterValidation("status_spec", func(fl validator.FieldLevel) bool {
+		_, err := ParseStatusMatcher(fl.Field().String())
+		return err == nil
+	})
+}
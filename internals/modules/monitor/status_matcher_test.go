@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseStatusMatcher(t *testing.T) {
+	tests := []struct {
+		spec      string
+		canonical string // empty when spec is invalid
+		match     []int
+		noMatch   []int
+	}{
+		{spec: "200", canonical: "200", match: []int{200}, noMatch: []int{201, 199, 0}},
+		{spec: "2xx", canonical: "2xx", match: []int{200, 250, 299}, noMatch: []int{199, 300}},
+		{spec: "2XX", canonical: "2xx", match: []int{204}},
+		{spec: "200-204", canonical: "200-204", match: []int{200, 202, 204}, noMatch: []int{205}},
+		{spec: "200-299", canonical: "2xx", match: []int{299}},
+		{spec: "404-404", canonical: "404", match: []int{404}},
+		{spec: "200,204,3xx", canonical: "200,204,3xx", match: []int{200, 204, 301, 399}, noMatch: []int{201, 404}},
+		{spec: " 200 , 301-302 ,", canonical: "200,301-302", match: []int{302}, noMatch: []int{303}},
+		{spec: "100-599", canonical: "100-599", match: []int{100, 599}},
+		{spec: ""},
+		{spec: " , "},
+		{spec: "600"},
+		{spec: "99"},
+		{spec: "abc"},
+		{spec: "0xx"},
+		{spec: "6xx"},
+		{spec: "2x"},
+		{spec: "5xx-"},
+		{spec: "-200"},
+		{spec: "300-200"},
+		{spec: "200-600"},
+		{spec: "200,600"},
+	}
+
+	for _, tt := range tests {
+		m, err := ParseStatusMatcher(tt.spec)
+		if tt.canonical == "" {
+			if err == nil {
+				t.Errorf("ParseStatusMatcher(%q) = %v, want error", tt.spec, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseStatusMatcher(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got := m.String(); got != tt.canonical {
+			t.Errorf("ParseStatusMatcher(%q).String() = %q, want %q", tt.spec, got, tt.canonical)
+		}
+		for _, code := range tt.match {
+			if !m.Match(code) {
+				t.Errorf("%q does not match %d", tt.spec, code)
+			}
+		}
+		for _, code := range tt.noMatch {
+			if m.Match(code) {
+				t.Errorf("%q matches %d", tt.spec, code)
+			}
+		}
+	}
+}
+
+func TestExpectedStatusUnmarshal(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    string
+		wantErr bool
+	}{
+		{json: `200`, want: "200"}, // legacy integer
+		{json: `"200,3xx"`, want: "200,3xx"},
+		{json: `[200, "2xx", "300-399"]`, want: "200,2xx,300-399"},
+		{json: `[]`, want: ""},
+		{json: `true`, wantErr: true},
+		{json: `{"code": 200}`, wantErr: true},
+		{json: `[200, true]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var req struct {
+			ExpectedStatus ExpectedStatus `json:"expected_status"`
+		}
+		err := json.Unmarshal([]byte(`{"expected_status": `+tt.json+`}`), &req)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("unmarshal %s error = %v, want error %v", tt.json, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && string(req.ExpectedStatus) != tt.want {
+			t.Errorf("unmarshal %s = %q, want %q", tt.json, req.ExpectedStatus, tt.want)
+		}
+	}
+}
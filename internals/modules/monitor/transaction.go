@@ -0,0 +1,166 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"project-k/pkg/jsonpath"
+	"regexp"
+	"strings"
+)
+
+// MaxTransactionSteps is the max steps of a transaction monitor
+const MaxTransactionSteps = 10
+
+// step without expected status expects any 2xx
+const defaultStepStatus = "2xx"
+
+type ExtractFrom string
+
+const (
+	ExtractJSONPath ExtractFrom = "json_path"
+	ExtractHeader   ExtractFrom = "header"
+	ExtractCookie   ExtractFrom = "cookie"
+)
+
+// Extraction stores a value of step response in a variable, later steps use it as {{name}}
+type Extraction struct {
+	Name string      `json:"name"`
+	From ExtractFrom `json:"from"`
+	// json path, header name or cookie name
+	Path string `json:"path"`
+}
+
+// Step is one request of a transaction monitor, url, header values and body can use {{name}} variables
+type Step struct {
+	Name string `json:"name,omitempty"`
+	// method defaults to GET
+	Method string `json:"method,omitempty"`
+	// absolute, or relative to monitor url (ex: /api/login)
+	Url            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	ExpectedStatus string            `json:"expected_status"`
+	Assertions     []Assertion       `json:"assertions,omitempty"`
+	Extract        []Extraction      `json:"extract,omitempty"`
+}
+
+// TransactionOptions holds the ordered steps of a transaction monitor,
+// steps share a cookie jar, monitor's headers and auth are applied to every step
+type TransactionOptions struct {
+	Steps []Step `json:"steps"`
+}
+
+// {{ name }}
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// name of an extracted variable, same as inside {{ }}
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Substitute replaces {{name}} with value of variable, unknown variables are left as it is
+func Substitute(s string, vars map[string]string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return variablePattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := variablePattern.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// StepURL resolves url of a step against monitor url
+func StepURL(base, stepURL string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(stepURL)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(u).String(), nil
+}
+
+// normalize validates steps in order, and stores expected status of each step in canonical form,
+// a step can only use variables extracted by steps before it
+func (t *TransactionOptions) normalize(baseURL string) error {
+	if len(t.Steps) == 0 {
+		return fmt.Errorf("transaction monitor needs at least one step")
+	}
+	if len(t.Steps) > MaxTransactionSteps {
+		return fmt.Errorf("transaction monitor can have at most %d steps", MaxTransactionSteps)
+	}
+
+	defined := map[string]bool{}
+	for i := range t.Steps {
+		step := &t.Steps[i]
+		stepErr := func(format string, a ...any) error {
+			return fmt.Errorf("step %d: %s", i+1, fmt.Sprintf(format, a...))
+		}
+
+		if step.Method == "" {
+			step.Method = http.MethodGet
+		}
+
+		// every variable used must be defined by an earlier step
+		used := []string{step.Url, step.Body}
+		for _, v := range step.Headers {
+			used = append(used, v)
+		}
+		for _, s := range used {
+			for _, m := range variablePattern.FindAllStringSubmatch(s, -1) {
+				if !defined[m[1]] {
+					return stepErr("variable %q is not extracted by an earlier step", m[1])
+				}
+			}
+		}
+
+		// variables are checked above, so any value gives a parsable url
+		resolved, err := StepURL(baseURL, variablePattern.ReplaceAllString(step.Url, "x"))
+		if err != nil {
+			return stepErr("invalid url: %v", err)
+		}
+		if u, _ := url.Parse(resolved); (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return stepErr("url must resolve to a http(s) url")
+		}
+
+		spec := step.ExpectedStatus
+		if spec == "" {
+			spec = defaultStepStatus
+		}
+		matcher, err := ParseStatusMatcher(spec)
+		if err != nil {
+			return stepErr("invalid expected status: %v", err)
+		}
+		step.ExpectedStatus = matcher.String()
+
+		for _, a := range step.Assertions {
+			if err := a.Validate(); err != nil {
+				return stepErr("invalid assertion: %v", err)
+			}
+		}
+
+		for _, e := range step.Extract {
+			if !variableName.MatchString(e.Name) {
+				return stepErr("invalid variable name %q", e.Name)
+			}
+			switch e.From {
+			case ExtractJSONPath:
+				if err := jsonpath.Validate(e.Path); err != nil {
+					return stepErr("extract %s: %v", e.Name, err)
+				}
+			case ExtractHeader, ExtractCookie:
+				if e.Path == "" {
+					return stepErr("extract %s needs a %s name in path", e.Name, e.From)
+				}
+			default:
+				return stepErr("extract %s: unknown source %q", e.Name, e.From)
+			}
+			defined[e.Name] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package monitor
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{"token": "abc", "id": "42", "_x1": "y"}
+
+	tests := []struct {
+		in, want string
+	}{
+		{in: "no variables", want: "no variables"},
+		{in: "Bearer {{token}}", want: "Bearer abc"},
+		{in: "Bearer {{ token }}", want: "Bearer abc"},
+		{in: "/users/{{id}}/orders/{{id}}", want: "/users/42/orders/42"},
+		{in: "{{token}}{{id}}", want: "abc42"},
+		{in: "{{_x1}}", want: "y"},
+		{in: "{{missing}}", want: "{{missing}}"},
+		{in: "{{token}} {{missing}}", want: "abc {{missing}}"},
+		{in: "{{1id}}", want: "{{1id}}"},       // not a variable name
+		{in: "{{to-ken}}", want: "{{to-ken}}"}, // not a variable name
+		{in: "{token}", want: "{token}"},
+		{in: "{{ }}", want: "{{ }}"},
+	}
+
+	for _, tt := range tests {
+		if got := Substitute(tt.in, vars); got != tt.want {
+			t.Errorf("Substitute(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStepURL(t *testing.T) {
+	tests := []struct {
+		base, step, want string
+	}{
+		{base: "https://example.com/app", step: "/api/login", want: "https://example.com/api/login"},
+		{base: "https://example.com/app/", step: "login", want: "https://example.com/app/login"},
+		{base: "https://example.com", step: "https://other.com/x", want: "https://other.com/x"},
+	}
+	for _, tt := range tests {
+		got, err := StepURL(tt.base, tt.step)
+		if err != nil || got != tt.want {
+			t.Errorf("StepURL(%q, %q) = %q, %v, want %q", tt.base, tt.step, got, err, tt.want)
+		}
+	}
+}
+
+func TestTransactionNormalize(t *testing.T) {
+	const base = "https://example.com"
+
+	login := Step{
+		Name:    "login",
+		Method:  http.MethodPost,
+		Url:     "/login",
+		Extract: []Extraction{{Name: "token", From: ExtractJSONPath, Path: "$.token"}},
+	}
+
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr string // empty when steps are valid
+	}{
+		{name: "no steps", wantErr: "at least one step"},
+		{name: "too many steps", steps: make([]Step, MaxTransactionSteps+1), wantErr: "at most"},
+		{
+			name: "variable used after extraction",
+			steps: []Step{login, {
+				Url:     "/orders?t={{token}}",
+				Headers: map[string]string{"Authorization": "Bearer {{ token }}"},
+				Body:    `{"t":"{{token}}"}`,
+			}},
+		},
+		{
+			name:    "variable used before extraction",
+			steps:   []Step{{Url: "/me", Headers: map[string]string{"Authorization": "{{token}}"}}, login},
+			wantErr: `step 1: variable "token" is not extracted by an earlier step`,
+		},
+		{
+			name: "variable used in the step extracting it",
+			steps: []Step{{
+				Url:     "/login?t={{token}}",
+				Extract: []Extraction{{Name: "token", From: ExtractHeader, Path: "X-Token"}},
+			}},
+			wantErr: `step 1: variable "token"`,
+		},
+		{
+			name:    "unknown variable in body",
+			steps:   []Step{login, {Url: "/x", Body: "{{session}}"}},
+			wantErr: `step 2: variable "session"`,
+		},
+		{
+			name:  "variable in host",
+			steps: []Step{login, {Url: "https://{{token}}.example.com/x"}},
+		},
+		{name: "relative url to non http", steps: []Step{{Url: "ftp://example.com/x"}}, wantErr: "http(s)"},
+		{name: "invalid status", steps: []Step{{Url: "/", ExpectedStatus: "600"}}, wantErr: "invalid expected status"},
+		{
+			name:    "invalid assertion",
+			steps:   []Step{{Url: "/", Assertions: []Assertion{{Type: AssertBodyContains}}}},
+			wantErr: "invalid assertion",
+		},
+		{
+			name:    "invalid json path",
+			steps:   []Step{{Url: "/", Extract: []Extraction{{Name: "a", From: ExtractJSONPath, Path: "$.a[x]"}}}},
+			wantErr: "extract a",
+		},
+		{
+			name:    "header extraction without name",
+			steps:   []Step{{Url: "/", Extract: []Extraction{{Name: "a", From: ExtractHeader}}}},
+			wantErr: "needs a header name",
+		},
+		{
+			name:    "cookie extraction without name",
+			steps:   []Step{{Url: "/", Extract: []Extraction{{Name: "a", From: ExtractCookie}}}},
+			wantErr: "needs a cookie name",
+		},
+		{
+			name:    "unknown source",
+			steps:   []Step{{Url: "/", Extract: []Extraction{{Name: "a", From: "body", Path: "x"}}}},
+			wantErr: `unknown source "body"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := &TransactionOptions{Steps: tt.steps}
+			err := to.normalize(base)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("normalize: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("normalize err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransactionNormalizeVariableNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "token", valid: true},
+		{name: "_token", valid: true},
+		{name: "Token_2", valid: true},
+		{name: ""},
+		{name: "2token"},
+		{name: "to-ken"},
+		{name: "to ken"},
+		{name: " token"},
+		{name: "token}}"},
+		{name: "a}}{{b"},
+		{name: "a}} {{b"},
+	}
+
+	for _, tt := range tests {
+		to := &TransactionOptions{Steps: []Step{{
+			Url:     "/",
+			Extract: []Extraction{{Name: tt.name, From: ExtractHeader, Path: "X-Token"}},
+		}}}
+		err := to.normalize("https://example.com")
+		if tt.valid && err != nil {
+			t.Errorf("variable %q rejected: %v", tt.name, err)
+		}
+		if !tt.valid && (err == nil || !strings.Contains(err.Error(), "invalid variable name")) {
+			t.Errorf("variable %q: err = %v, want invalid variable name", tt.name, err)
+		}
+	}
+}
+
+func TestTransactionNormalizeDefaults(t *testing.T) {
+	to := &TransactionOptions{Steps: []Step{
+		{Url: "/"},
+		{Url: "/a", Method: http.MethodPost, ExpectedStatus: "200-299"},
+	}}
+	if err := to.normalize("https://example.com"); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+
+	if got := to.Steps[0].Method; got != http.MethodGet {
+		t.Errorf("default method = %q, want GET", got)
+	}
+	if got := to.Steps[0].ExpectedStatus; got != defaultStepStatus {
+		t.Errorf("default expected status = %q, want %q", got, defaultStepStatus)
+	}
+	if got := to.Steps[1].Method; got != http.MethodPost {
+		t.Errorf("method = %q, want POST", got)
+	}
+	// stored in canonical form
+	if got := to.Steps[1].ExpectedStatus; got != "2xx" {
+		t.Errorf("expected status = %q, want 2xx", got)
+	}
+}
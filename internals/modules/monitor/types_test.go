@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNetworkOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    NetworkOptions
+		wantErr string // empty when options are valid
+	}{
+		{name: "any", opts: NetworkOptions{}},
+		{name: "both", opts: NetworkOptions{IPVersion: IPBoth}},
+		{name: "unknown version", opts: NetworkOptions{IPVersion: "ipv5"}, wantErr: "unknown ip version"},
+		{name: "ipv4 override", opts: NetworkOptions{IPVersion: IPv4, Hosts: map[string]string{"example.com": "192.0.2.1"}}},
+		{name: "ipv6 override", opts: NetworkOptions{IPVersion: IPv6, Hosts: map[string]string{"example.com": "2001:db8::1"}}},
+		{
+			name:    "ipv4 with ipv6 override",
+			opts:    NetworkOptions{IPVersion: IPv4, Hosts: map[string]string{"example.com": "2001:db8::1"}},
+			wantErr: "does not match ip version",
+		},
+		{
+			name:    "both with ipv4 override",
+			opts:    NetworkOptions{IPVersion: IPBoth, Hosts: map[string]string{"example.com": "192.0.2.1"}},
+			wantErr: "can not be used with ip version both",
+		},
+		{
+			name:    "both with ipv6 override",
+			opts:    NetworkOptions{IPVersion: IPBoth, Hosts: map[string]string{"example.com": "2001:db8::1"}},
+			wantErr: "can not be used with ip version both",
+		},
+		{name: "invalid ip", opts: NetworkOptions{Hosts: map[string]string{"example.com": "nope"}}, wantErr: "invalid ip"},
+		{name: "invalid host", opts: NetworkOptions{Hosts: map[string]string{"a:80": "192.0.2.1"}}, wantErr: "invalid host"},
+		{name: "invalid resolver", opts: NetworkOptions{Resolver: "a/b"}, wantErr: "invalid resolver"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.validate(TypeHTTP)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validate err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (&NetworkOptions{}).validate(TypeDNS); err == nil {
+		t.Error("network options accepted for dns monitor")
+	}
+}
+
+func TestFirstCheckDelay(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Monitor
+		want int32
+	}{
+		{name: "http", m: Monitor{Type: TypeHTTP, IntervalSec: 60, Options: Options{Heartbeat: &HeartbeatOptions{GraceSec: 30}}}, want: 60},
+		{name: "heartbeat without grace", m: Monitor{Type: TypeHeartbeat, IntervalSec: 300}, want: 300},
+		{name: "heartbeat with grace", m: Monitor{Type: TypeHeartbeat, IntervalSec: 300, Options: Options{Heartbeat: &HeartbeatOptions{GraceSec: 120}}}, want: 420},
+	}
+	for _, tt := range tests {
+		if got := firstCheckDelay(&tt.m); got != tt.want {
+			t.Errorf("%s: firstCheckDelay = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
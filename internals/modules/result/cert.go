@@ -0,0 +1,56 @@
+package result
+
+import (
+	"fmt"
+	"time"
+
+	"project-k/internals/modules/alert"
+	"project-k/internals/modules/executor"
+)
+
+// certAlertInterval is the minimum gap between two CERT_EXPIRING alerts of a monitor
+const certAlertInterval = 24 * time.Hour
+
+// alertCertExpiry sends a CERT_EXPIRING alert when monitor's certificate is in its expiry window,
+// and CERT_RENEWED when an alerted certificate was replaced. it is independent of up/down state, so it never touches incidents
+func (rp *ResultProcessor) alertCertExpiry(r executor.HTTPResult) {
+	if r.Cert == nil {
+		return
+	}
+	ctx := rp.ctx
+
+	if !r.Cert.Expiring {
+		renewed, err := rp.redisSvc.ClearCertExpiringIfRenewed(ctx, r.MonitorID, r.Cert.NotAfter)
+		if err != nil {
+			rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to clear cert alert in redis")
+			return
+		}
+		if renewed {
+			rp.alertChan <- alert.AlertEvent{
+				MonitorID: r.MonitorID,
+				Type:      alert.AlertCertRenewed,
+				Message: fmt.Sprintf("certificate %q issued by %q now expires at %s",
+					r.Cert.Subject, r.Cert.Issuer, r.Cert.NotAfter.UTC().Format(time.RFC3339)),
+			}
+			rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Send cert renewed Alert to alert channel")
+		}
+		return
+	}
+
+	shouldAlert, err := rp.redisSvc.MarkCertAlertedIfNotSet(ctx, r.MonitorID, r.Cert.NotAfter, certAlertInterval)
+	if err != nil {
+		rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to mark cert alert in redis")
+		return
+	}
+	if !shouldAlert {
+		return
+	}
+
+	rp.alertChan <- alert.AlertEvent{
+		MonitorID: r.MonitorID,
+		Type:      alert.AlertCertExpiring,
+		Message: fmt.Sprintf("certificate %q issued by %q expires at %s",
+			r.Cert.Subject, r.Cert.Issuer, r.Cert.NotAfter.UTC().Format(time.RFC3339)),
+	}
+	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Send cert expiry Alert to alert channel")
+}
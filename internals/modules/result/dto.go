@@ -0,0 +1,104 @@
+package result
+
+import (
+	"project-k/internals/modules/executor"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IncidentResponse struct {
+	ID         string             `json:"id"`
+	MonitorID  string             `json:"monitor_id"`
+	StartTime  time.Time          `json:"start_time"`
+	EndTime    *time.Time         `json:"end_time,omitempty"` // nil while incident is open
+	Alerted    bool               `json:"alerted"`
+	HttpStatus int32              `json:"http_status"`
+	LatencyMs  int32              `json:"latency_ms"`
+	Evidence   *executor.Evidence `json:"evidence,omitempty"`
+}
+
+func toIncidentResponse(mI *MonitorIncident) IncidentResponse {
+	resp := IncidentResponse{
+		ID:         mI.ID.String(),
+		MonitorID:  mI.MonitorID.String(),
+		StartTime:  mI.StartTime,
+		Alerted:    mI.Alerted,
+		HttpStatus: mI.HttpStatus,
+		LatencyMs:  mI.LatencyMs,
+		Evidence:   mI.Evidence,
+	}
+	if !mI.EndTime.IsZero() {
+		resp.EndTime = &mI.EndTime
+	}
+	return resp
+}
+
+type ListIncidentsResponse struct {
+	MonitorID string             `json:"monitor_id"`
+	Limit     int32              `json:"limit"`
+	Offset    int32              `json:"offset"`
+	Incidents []IncidentResponse `json:"incidents"`
+}
+
+type CheckResultResponse struct {
+	CheckedAt  time.Time `json:"checked_at"`
+	Success    bool      `json:"success"`
+	HttpStatus int32     `json:"http_status"`
+	LatencyMs  int32     `json:"latency_ms"`
+	Reason     string    `json:"reason,omitempty"`
+	Region     string    `json:"region"`
+}
+
+func toCheckResultResponse(cr *CheckResult) CheckResultResponse {
+	return CheckResultResponse{
+		CheckedAt:  cr.CheckedAt,
+		Success:    cr.Success,
+		HttpStatus: cr.HttpStatus,
+		LatencyMs:  cr.LatencyMs,
+		Reason:     cr.Reason,
+		Region:     cr.Region,
+	}
+}
+
+type ListCheckResultsResponse struct {
+	MonitorID string                `json:"monitor_id"`
+	Limit     int32                 `json:"limit"`
+	Offset    int32                 `json:"offset"`
+	Checks    []CheckResultResponse `json:"checks"`
+}
+
+type UptimeResponse struct {
+	MonitorID     string           `json:"monitor_id,omitempty"`
+	From          time.Time        `json:"from"`
+	To            time.Time        `json:"to"`
+	UptimePercent float64          `json:"uptime_percent"`
+	MonitoredSec  int64            `json:"monitored_sec"`
+	DowntimeSec   int64            `json:"downtime_sec"`
+	IncidentCount int              `json:"incident_count"`
+	MTTRSec       int64            `json:"mttr_sec"`
+	ChecksTotal   int64            `json:"checks_total"`
+	ChecksFailed  int64            `json:"checks_failed"`
+	Monitors      []UptimeResponse `json:"monitors,omitempty"` // only on aggregate of a user
+}
+
+func toUptimeResponse(u *Uptime) UptimeResponse {
+	resp := UptimeResponse{
+		From:          u.From,
+		To:            u.To,
+		UptimePercent: u.UptimePercent,
+		MonitoredSec:  u.MonitoredSec,
+		DowntimeSec:   u.DowntimeSec,
+		IncidentCount: u.IncidentCount,
+		MTTRSec:       u.MTTRSec,
+		ChecksTotal:   u.Checks.Total,
+		ChecksFailed:  u.Checks.Failed,
+	}
+	if u.MonitorID != uuid.Nil {
+		resp.MonitorID = u.MonitorID.String()
+	}
+	for i := range u.Monitors {
+		resp.Monitors = append(resp.Monitors, toUptimeResponse(&u.Monitors[i]))
+	}
+	return resp
+}
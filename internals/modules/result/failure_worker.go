@@ -3,6 +3,7 @@ package result
 import (
 	"project-k/internals/modules/alert"
 	"project-k/internals/modules/executor"
+	"project-k/pkg/apperror"
 	"time"
 )
 
@@ -31,12 +32,18 @@ func (rp *ResultProcessor) handleFailure(r executor.HTTPResult) {
 		}
 	}()
 
+	rp.alertCertExpiry(r)
+
 	// Case 1 => stop monitoring : No Re-schedule
 	if r.Reason == "INVALID_REQUEST" || r.Reason == "DNS_FAILURE" { // these should have failure type, not String
 		rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Failure is Terminal, notify user")
-		if err := rp.redisSvc.StoreStatus(ctx, r.MonitorID, r.Status, r.LatencyMs, r.CheckedAt); err != nil {
+		if err := rp.redisSvc.StoreStatus(ctx, r.MonitorID, toStatus(r)); err != nil {
 			rp.logger.Error().Err(err).Msg("failed to store status in redis")
 		}
+		// monitor is not checked again, an open incident would count as downtime forever, so it ends at this check
+		if _, err := rp.incidentRepo.CloseIncident(ctx, r.MonitorID, failedAt(r)); err != nil && !apperror.IsKind(err, apperror.NotFound) {
+			rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to close incident of stopped monitor")
+		}
 		reschedule = false
 		return
 	}
@@ -68,7 +75,7 @@ func (rp *ResultProcessor) handleFailure(r executor.HTTPResult) {
 	}
 
 	// Case 3 => failure path : may Alert and but 100% Re-schedule
-	failCount, _, err := rp.redisSvc.IncrementIncident(ctx, r.MonitorID)
+	failCount, firstFailureAt, err := rp.redisSvc.IncrementIncident(ctx, r.MonitorID, r.Reason, failedAt(r))
 	if err != nil {
 		rp.logger.Error().Err(err).Msg("failed to increment incident count in redis")
 		// rp.monitorSvc.ScheduleMonitor(ctx, r.MonitorID, r.IntervalSec, "result.failure_worker")
@@ -96,11 +103,37 @@ func (rp *ResultProcessor) handleFailure(r executor.HTTPResult) {
 		rp.logger.Error().Err(err).Msg("failed to mark db_incident")
 	}
 
-	if err := rp.incidentRepo.Create(ctx, time.Now(), r); err != nil {
+	// incident started at the failure which opened it, not at the one crossing the threshold
+	startTime := firstFailureAt
+	incidentID, err := rp.incidentRepo.Create(ctx, startTime, r)
+	if err != nil {
 		rp.logger.Error().Err(err).Msg("failed to create incident in DB")
+	} else {
+		rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Created incident in DB")
+		// RECOVERED must carry the id DOWN carries, or pagerduty and opsgenie never resolve it
+		if err := rp.redisSvc.SetIncidentID(ctx, r.MonitorID, incidentID); err != nil {
+			rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to store incident id in redis")
+		}
 	}
-	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Created incident in DB")
 
-	rp.alertChan <- alert.AlertEvent{MonitorID: r.MonitorID}
+	// alert goes out even if incident was not stored, it is then sent without incident id
+	rp.alertChan <- alert.AlertEvent{
+		MonitorID:  r.MonitorID,
+		IncidentID: incidentID,
+		Type:       alert.AlertDown,
+		Message:    r.Reason,
+		Incident: &alert.IncidentDetails{
+			StartedAt:  startTime,
+			LastReason: r.Reason,
+		},
+	}
 	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Send Alert to alert channel")
 }
+
+// failedAt is when the failed check ran, results without check time count as failed now
+func failedAt(r executor.HTTPResult) time.Time {
+	if r.CheckedAt.IsZero() {
+		return time.Now()
+	}
+	return r.CheckedAt
+}
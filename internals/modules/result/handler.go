@@ -0,0 +1,255 @@
+package result
+
+import (
+	"net/http"
+	middle "project-k/internals/middleware"
+	"project-k/pkg/apperror"
+	"project-k/pkg/utils"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	service *Service
+	logger  *zerolog.Logger
+}
+
+func NewHandler(service *Service, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// /monitors/{monitorID}/incidents?offset=0&limit=10
+func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.incident.list_incidents"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	limit, offset, err := pageParams(r, 10, 100)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	incidents, err := h.service.ListIncidents(ctx, reqClaims.UserID, monitorID, limit, offset)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving incidents error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+	resp := ListIncidentsResponse{
+		MonitorID: monitorID.String(),
+		Limit:     limit,
+		Offset:    offset,
+		Incidents: make([]IncidentResponse, 0, len(incidents)),
+	}
+	for i := range incidents {
+		resp.Incidents = append(resp.Incidents, toIncidentResponse(&incidents[i]))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "incidents retrieved successfully", resp)
+}
+
+// /monitors/{monitorID}/incidents/{incidentID}
+func (h *Handler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.incident.get_incident"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	incident, err := h.service.GetIncident(ctx, reqClaims.UserID, monitorID, incidentID)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving incident error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "incident retrieved successfully", toIncidentResponse(&incident))
+}
+
+// /monitors/{monitorID}/checks?offset=0&limit=50
+func (h *Handler) ListCheckResults(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.history.list_check_results"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	limit, offset, err := pageParams(r, 50, 500)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	results, err := h.service.ListCheckResults(ctx, reqClaims.UserID, monitorID, limit, offset)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving check results error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+	resp := ListCheckResultsResponse{
+		MonitorID: monitorID.String(),
+		Limit:     limit,
+		Offset:    offset,
+		Checks:    make([]CheckResultResponse, 0, len(results)),
+	}
+	for i := range results {
+		resp.Checks = append(resp.Checks, toCheckResultResponse(&results[i]))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "check results retrieved successfully", resp)
+}
+
+// /monitors/{monitorID}/uptime?window=24h|7d|30d|90d  or  ?from=2026-09-01T00:00:00Z&to=2026-10-01T00:00:00Z
+func (h *Handler) MonitorUptime(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.uptime.monitor_uptime"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	q := r.URL.Query()
+	from, to, err := ParseUptimeWindow(q.Get("window"), q.Get("from"), q.Get("to"), time.Now())
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, err.Error())
+		return
+	}
+
+	uptime, err := h.service.MonitorUptime(ctx, reqClaims.UserID, monitorID, from, to)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("computing monitor uptime error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "uptime computed successfully", toUptimeResponse(&uptime))
+}
+
+// /uptime?window=30d  -> aggregate of all monitors of user
+func (h *Handler) UserUptime(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.uptime.user_uptime"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	q := r.URL.Query()
+	from, to, err := ParseUptimeWindow(q.Get("window"), q.Get("from"), q.Get("to"), time.Now())
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, err.Error())
+		return
+	}
+
+	uptime, err := h.service.UserUptime(ctx, reqClaims.UserID, from, to)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("computing user uptime error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "uptime computed successfully", toUptimeResponse(&uptime))
+}
+
+// pageParams parses optional limit and offset of query,
+// limit falls back to def when missing, non positive or above maxLimit
+func pageParams(r *http.Request, def, maxLimit int32) (int32, int32, error) {
+	var limit, offset int64
+	var err error
+	if s := r.URL.Query().Get("limit"); s != "" {
+		if limit, err = strconv.ParseInt(s, 10, 32); err != nil {
+			return 0, 0, err
+		}
+	}
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if offset, err = strconv.ParseInt(s, 10, 32); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if limit <= 0 || limit > int64(maxLimit) {
+		limit = int64(def)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return int32(limit), int32(offset), nil
+}
@@ -0,0 +1,150 @@
+package result
+
+import (
+	"context"
+	"errors"
+	"math"
+	"project-k/internals/modules/executor"
+	"project-k/pkg/db"
+	"project-k/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+)
+
+type CheckResultRepository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewCheckResultRepo(dbExecutor db.DBTX, logger *zerolog.Logger) *CheckResultRepository {
+	return &CheckResultRepository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// InsertBatch writes results with a single COPY, returns number of rows written
+func (r *CheckResultRepository) InsertBatch(ctx context.Context, results []executor.HTTPResult) (int64, error) {
+	const op string = "repo.check_result.insert_batch"
+
+	rows := make([]db.InsertCheckResultsParams, 0, len(results))
+	for i := range results {
+		rows = append(rows, db.InsertCheckResultsParams{
+			MonitorID:  utils.ToPgUUID(results[i].MonitorID),
+			CheckedAt:  utils.ToPgTimestamptz(results[i].CheckedAt),
+			Success:    results[i].Success,
+			HttpStatus: int32(results[i].Status),
+			LatencyMs:  int32(min(max(results[i].LatencyMs, 0), math.MaxInt32)),
+			Reason:     results[i].Reason,
+			Region:     results[i].Region,
+		})
+	}
+
+	n, err := r.querier.InsertCheckResults(ctx, rows)
+	if err == nil {
+		return n, nil
+	}
+	if !isRowError(err) {
+		return 0, utils.WrapRepoError(op, err, false, r.logger)
+	}
+
+	// one bad row (like result of a monitor deleted meanwhile) fails whole COPY, nothing is written,
+	// so rows are written one by one and the bad ones dropped
+	var dropped int
+	n = 0
+	for i := range rows {
+		err := r.querier.InsertCheckResult(ctx, db.InsertCheckResultParams(rows[i]))
+		if err == nil {
+			n++
+			continue
+		}
+		if !isRowError(err) {
+			return n, utils.WrapRepoError(op, err, false, r.logger)
+		}
+		dropped++
+	}
+	r.logger.Warn().
+		Err(err).
+		Int("batch_size", len(rows)).
+		Int("dropped", dropped).
+		Msg("check results batch had bad rows, wrote it row by row")
+	return n, nil
+}
+
+// isRowError reports if err is postgres rejecting data of a row (data exception or constraint violation),
+// not the connection or statement failing
+func isRowError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	class := pgErr.Code[:min(2, len(pgErr.Code))]
+	return class == "22" || class == "23"
+}
+
+// List returns check results of a monitor, latest first
+func (r *CheckResultRepository) List(ctx context.Context, monitorID uuid.UUID, limit int32, offset int32) ([]CheckResult, error) {
+	const op string = "repo.check_result.list"
+
+	rows, err := r.querier.ListCheckResults(ctx, db.ListCheckResultsParams{
+		MonitorID: utils.ToPgUUID(monitorID),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return []CheckResult{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+
+	results := make([]CheckResult, 0, len(rows))
+	for i := range rows {
+		results = append(results, CheckResult{
+			ID:         rows[i].ID,
+			MonitorID:  utils.FromPgUUID(rows[i].MonitorID),
+			CheckedAt:  utils.FromPgTimestamptz(rows[i].CheckedAt),
+			Success:    rows[i].Success,
+			HttpStatus: rows[i].HttpStatus,
+			LatencyMs:  rows[i].LatencyMs,
+			Reason:     rows[i].Reason,
+			Region:     rows[i].Region,
+		})
+	}
+	return results, nil
+}
+
+// CountInRange returns number of checks and failed checks of a monitor in [from, to)
+func (r *CheckResultRepository) CountInRange(ctx context.Context, monitorID uuid.UUID, from, to time.Time) (CheckCounts, error) {
+	const op string = "repo.check_result.count_in_range"
+
+	row, err := r.querier.CountCheckResultsInRange(ctx, db.CountCheckResultsInRangeParams{
+		MonitorID:  utils.ToPgUUID(monitorID),
+		RangeStart: utils.ToPgTimestamptz(from),
+		RangeEnd:   utils.ToPgTimestamptz(to),
+	})
+	if err != nil {
+		return CheckCounts{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return CheckCounts{Total: row.Total, Failed: row.Failed}, nil
+}
+
+// CountUserInRange is CountInRange of all monitors of a user, by monitor id
+func (r *CheckResultRepository) CountUserInRange(ctx context.Context, userID uuid.UUID, from, to time.Time) (map[uuid.UUID]CheckCounts, error) {
+	const op string = "repo.check_result.count_user_in_range"
+
+	rows, err := r.querier.CountUserCheckResultsInRange(ctx, db.CountUserCheckResultsInRangeParams{
+		UserID:     utils.ToPgUUID(userID),
+		RangeStart: utils.ToPgTimestamptz(from),
+		RangeEnd:   utils.ToPgTimestamptz(to),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, false, r.logger)
+	}
+
+	counts := make(map[uuid.UUID]CheckCounts, len(rows))
+	for i := range rows {
+		counts[utils.FromPgUUID(rows[i].MonitorID)] = CheckCounts{Total: rows[i].Total, Failed: rows[i].Failed}
+	}
+	return counts, nil
+}
@@ -0,0 +1,134 @@
+package result
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"project-k/internals/modules/executor"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+// checkResultsDB stands in for postgres, rows of deleted monitors violate the monitor fk
+type checkResultsDB struct {
+	deleted  uuid.UUID
+	copyErr  error // when set, COPY fails with it
+	execErr  error // when set, single inserts fail with it
+	copied   int
+	inserted []uuid.UUID
+}
+
+var fkViolation = &pgconn.PgError{Code: "23503", ConstraintName: "check_results_monitor_id_fkey"}
+
+func (d *checkResultsDB) CopyFrom(_ context.Context, _ pgx.Identifier, _ []string, src pgx.CopyFromSource) (int64, error) {
+	if d.copyErr != nil {
+		return 0, d.copyErr
+	}
+	var n int64
+	for src.Next() {
+		v, _ := src.Values()
+		if uuid.UUID(v[0].(pgtype.UUID).Bytes) == d.deleted {
+			return 0, fkViolation // copy is all or nothing
+		}
+		n++
+	}
+	d.copied = int(n)
+	return n, nil
+}
+
+func (d *checkResultsDB) Exec(_ context.Context, _ string, args ...interface{}) (pgconn.CommandTag, error) {
+	if d.execErr != nil {
+		return pgconn.CommandTag{}, d.execErr
+	}
+	id := uuid.UUID(args[0].(pgtype.UUID).Bytes)
+	if id == d.deleted {
+		return pgconn.CommandTag{}, fkViolation
+	}
+	d.inserted = append(d.inserted, id)
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (d *checkResultsDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (d *checkResultsDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return nil
+}
+
+func TestInsertBatch(t *testing.T) {
+	live, deleted := uuid.New(), uuid.New()
+	connErr := errors.New("connection reset by peer")
+
+	tests := []struct {
+		name     string
+		batch    []uuid.UUID
+		copyErr  error
+		execErr  error
+		written  int64
+		copied   int
+		inserted int
+		wantErr  bool
+	}{
+		{name: "all good", batch: []uuid.UUID{live, live, live}, written: 3, copied: 3},
+		{name: "deleted monitor is dropped", batch: []uuid.UUID{live, deleted, live, deleted}, written: 2, inserted: 2},
+		{name: "only deleted monitors", batch: []uuid.UUID{deleted, deleted}},
+		// a failing connection is not retried row by row
+		{name: "connection error", batch: []uuid.UUID{live, live}, copyErr: connErr, wantErr: true},
+		{name: "connection error while retrying", batch: []uuid.UUID{live, deleted}, execErr: connErr, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbtx := &checkResultsDB{deleted: deleted, copyErr: tt.copyErr, execErr: tt.execErr}
+			nop := zerolog.Nop()
+			repo := NewCheckResultRepo(dbtx, &nop)
+
+			results := make([]executor.HTTPResult, len(tt.batch))
+			for i, id := range tt.batch {
+				results[i] = executor.HTTPResult{MonitorID: id, Success: true}
+			}
+
+			n, err := repo.InsertBatch(context.Background(), results)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want err %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if n != tt.written || dbtx.copied != tt.copied || len(dbtx.inserted) != tt.inserted {
+				t.Errorf("written, copied, inserted = %d, %d, %d, want %d, %d, %d",
+					n, dbtx.copied, len(dbtx.inserted), tt.written, tt.copied, tt.inserted)
+			}
+			for _, id := range dbtx.inserted {
+				if id == deleted {
+					t.Error("row of deleted monitor was inserted")
+				}
+			}
+		})
+	}
+}
+
+func TestIsRowError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fkViolation, true},
+		{&pgconn.PgError{Code: "22001"}, true},  // string too long
+		{&pgconn.PgError{Code: "57014"}, false}, // query canceled
+		{&pgconn.PgError{}, false},
+		{context.DeadlineExceeded, false},
+		{errors.New("connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := isRowError(tt.err); got != tt.want {
+			t.Errorf("isRowError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
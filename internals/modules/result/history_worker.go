@@ -0,0 +1,72 @@
+package result
+
+import (
+	"context"
+	"time"
+
+	"project-k/internals/modules/executor"
+)
+
+// max time a batch write may take, it is not bound to processor ctx, so last batch is written on shutdown
+const historyWriteTimeout = 10 * time.Second
+
+// historyWorker writes every check result to check_results, in batches of historyBatchSize
+// or whatever is collected in historyFlushInterval, whichever comes first
+func (rp *ResultProcessor) historyWorker() {
+	defer rp.workerWG.Done()
+
+	batch := make([]executor.HTTPResult, 0, rp.historyBatchSize)
+	ticker := time.NewTicker(rp.historyFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case r, ok := <-rp.historyChan:
+			if !ok { // router is closed, write what is left
+				rp.flushHistory(batch)
+				rp.reportDroppedHistory()
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= rp.historyBatchSize {
+				rp.flushHistory(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				rp.flushHistory(batch)
+				batch = batch[:0]
+			}
+			rp.reportDroppedHistory()
+		}
+	}
+}
+
+func (rp *ResultProcessor) flushHistory(batch []executor.HTTPResult) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyWriteTimeout)
+	defer cancel()
+
+	// history is best effort, a failed batch is logged and dropped, so it never blocks the pipeline for long
+	n, err := rp.historyRepo.InsertBatch(ctx, batch)
+	if err != nil {
+		rp.logger.Error().
+			Err(err).
+			Int("batch_size", len(batch)).
+			Msg("failed to write check results batch")
+		return
+	}
+	rp.logger.Debug().Int64("rows", n).Msg("check results batch written")
+}
+
+// reportDroppedHistory logs how many results router dropped since last report, at most once per flush interval
+func (rp *ResultProcessor) reportDroppedHistory() {
+	if n := rp.historyDropped.Swap(0); n > 0 {
+		rp.logger.Warn().
+			Int64("dropped", n).
+			Msg("history channel full, check results dropped from history")
+	}
+}
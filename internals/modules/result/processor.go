@@ -4,6 +4,8 @@ import (
 	"context"
 	"project-k/config"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"project-k/internals/modules/alert"
 	"project-k/internals/modules/executor"
@@ -32,11 +34,19 @@ type ResultProcessor struct {
 	redisSvc     *redisstore.Client
 	monitorSvc   MonitorService
 	incidentRepo *MonitorIncidentRepository // here should be MonitorIncidentService, make a seperate module for Monitor Incident
+	historyRepo  *CheckResultRepository
+
+	// history writer config
+	historyBatchSize     int
+	historyFlushInterval time.Duration
+	// results left out of history since last report, because history channel was full
+	historyDropped atomic.Int64
 
 	// channels
 	resultChan  chan executor.HTTPResult
 	successChan chan executor.HTTPResult
 	failureChan chan executor.HTTPResult
+	historyChan chan executor.HTTPResult
 	alertChan   chan alert.AlertEvent
 
 	// misc
@@ -49,22 +59,27 @@ func NewResultProcessor(
 	redisSvc *redisstore.Client,
 	resultChan chan executor.HTTPResult,
 	incidentRepo *MonitorIncidentRepository,
+	historyRepo *CheckResultRepository,
 	monitorSvc MonitorService,
 	alertChan chan alert.AlertEvent,
 	logger *zerolog.Logger,
 ) *ResultProcessor {
 	return &ResultProcessor{
-		ctx:                ctx,
-		redisSvc:           redisSvc,
-		resultChan:         resultChan,
-		incidentRepo:       incidentRepo,
-		monitorSvc:         monitorSvc,
-		alertChan:          alertChan,
-		successChan:        make(chan executor.HTTPResult, resProcessorConfig.SuccessChannelSize), // number should be passed as parameter
-		failureChan:        make(chan executor.HTTPResult, resProcessorConfig.FailureChannelSize), // number should be passed as parameter
-		successWorkerCount: resProcessorConfig.SuccessWorkerCount,
-		failureWorkerCount: resProcessorConfig.FailureWorkerCount,
-		logger:             logger,
+		ctx:                  ctx,
+		redisSvc:             redisSvc,
+		resultChan:           resultChan,
+		incidentRepo:         incidentRepo,
+		historyRepo:          historyRepo,
+		monitorSvc:           monitorSvc,
+		alertChan:            alertChan,
+		successChan:          make(chan executor.HTTPResult, resProcessorConfig.SuccessChannelSize), // number should be passed as parameter
+		failureChan:          make(chan executor.HTTPResult, resProcessorConfig.FailureChannelSize), // number should be passed as parameter
+		historyChan:          make(chan executor.HTTPResult, resProcessorConfig.HistoryChannelSize),
+		historyBatchSize:     resProcessorConfig.HistoryBatchSize,
+		historyFlushInterval: resProcessorConfig.HistoryFlushInterval,
+		successWorkerCount:   resProcessorConfig.SuccessWorkerCount,
+		failureWorkerCount:   resProcessorConfig.FailureWorkerCount,
+		logger:               logger,
 	}
 }
 
@@ -83,6 +98,9 @@ func (rp *ResultProcessor) StartResultProcessor() {
 		go rp.failureWorker()
 	}
 
+	rp.workerWG.Add(1)
+	go rp.historyWorker()
+
 	// now start result router
 	go rp.router()
 
@@ -91,6 +109,14 @@ func (rp *ResultProcessor) StartResultProcessor() {
 
 func (rp *ResultProcessor) router() {
 	for r := range rp.resultChan {
+		// every check is kept in history, retries as well, but history is best effort,
+		// when db is slow and history channel is full the result is dropped so alerting is not stalled
+		select {
+		case rp.historyChan <- r:
+		default:
+			rp.historyDropped.Add(1)
+		}
+
 		if r.Success {
 			rp.successChan <- r
 		} else {
@@ -98,9 +124,10 @@ func (rp *ResultProcessor) router() {
 		}
 	}
 
-	// closing success and failure channel
+	// closing success, failure and history channel
 	close(rp.failureChan)
 	close(rp.successChan)
+	close(rp.historyChan)
 }
 
 // WorkersClosingWait waits for all workers to complete
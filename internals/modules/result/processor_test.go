@@ -0,0 +1,35 @@
+package result
+
+import (
+	"testing"
+
+	"project-k/internals/modules/executor"
+)
+
+func TestRouterDropsHistoryWhenFull(t *testing.T) {
+	rp := &ResultProcessor{
+		resultChan:  make(chan executor.HTTPResult, 5),
+		successChan: make(chan executor.HTTPResult, 5),
+		failureChan: make(chan executor.HTTPResult, 5),
+		historyChan: make(chan executor.HTTPResult, 1), // nobody reads it, like a stuck db write
+	}
+	for _, ok := range []bool{true, false, true, false, true} {
+		rp.resultChan <- executor.HTTPResult{Success: ok}
+	}
+	close(rp.resultChan)
+
+	rp.router() // returns once result channel is drained, it must not block on history
+
+	if got := len(rp.successChan); got != 3 {
+		t.Errorf("success results = %d, want 3", got)
+	}
+	if got := len(rp.failureChan); got != 2 {
+		t.Errorf("failure results = %d, want 2", got)
+	}
+	if got := len(rp.historyChan); got != 1 {
+		t.Errorf("history results = %d, want 1", got)
+	}
+	if got := rp.historyDropped.Load(); got != 4 {
+		t.Errorf("dropped = %d, want 4", got)
+	}
+}
@@ -2,6 +2,7 @@ package result
 
 import (
 	"context"
+	"encoding/json"
 	"project-k/internals/modules/executor"
 	"project-k/pkg/apperror"
 	"project-k/pkg/db"
@@ -25,24 +26,34 @@ func NewMonitorIncidentRepo(dbExecutor db.DBTX, logger *zerolog.Logger) *Monitor
 	}
 }
 
-func (r *MonitorIncidentRepository) Create(ctx context.Context, startTime time.Time, e executor.HTTPResult) error {
+// Create opens an incident of monitor of e, returns its id
+func (r *MonitorIncidentRepository) Create(ctx context.Context, startTime time.Time, e executor.HTTPResult) (uuid.UUID, error) {
 	const op string = "repo.monitor_incident.create"
 
-	err := r.querier.CreateMonitorIncident(ctx, db.CreateMonitorIncidentParams{
+	var evidence []byte // NULL if check has no evidence (non http checks)
+	if e.Evidence != nil {
+		var err error
+		if evidence, err = json.Marshal(e.Evidence); err != nil {
+			return uuid.UUID{}, apperror.New(apperror.Internal, op, err)
+		}
+	}
+
+	id, err := r.querier.CreateMonitorIncident(ctx, db.CreateMonitorIncidentParams{
 		MonitorID:  utils.ToPgUUID(e.MonitorID),
 		Alerted:    true,
 		HttpStatus: int32(e.Status),
 		LatencyMs:  int32(e.LatencyMs),
+		Evidence:   evidence,
 		StartTime: pgtype.Timestamptz{
 			Time:  startTime,
 			Valid: true,
 		},
 	})
 	if err == nil {
-		return nil
+		return utils.FromPgUUID(id), nil
 	}
 
-	return utils.WrapRepoError(op, err, false, r.logger)
+	return uuid.UUID{}, utils.WrapRepoError(op, err, false, r.logger)
 }
 
 func (r *MonitorIncidentRepository) GetByID(ctx context.Context, incidentID uuid.UUID) (MonitorIncident, error) {
@@ -50,38 +61,109 @@ func (r *MonitorIncidentRepository) GetByID(ctx context.Context, incidentID uuid
 
 	mI, err := r.querier.GetMonitorIncidentByID(ctx, utils.ToPgUUID(incidentID))
 	if err == nil {
-		return MonitorIncident{
-			ID:         utils.FromPgUUID(mI.ID),
-			MonitorID:  utils.FromPgUUID(mI.MonitorID),
-			Alerted:    mI.Alerted,
-			HttpStatus: mI.HttpStatus,
-			LatencyMs:  mI.LatencyMs,
-			StartTime:  utils.FromPgTimestamptz(mI.StartTime),
-			CreatedAt:  utils.FromPgTimestamptz(mI.CreatedAt),
-			EndTime:    utils.FromPgTimestamptz(mI.EndTime),
-		}, nil
+		return toMonitorIncident(op, &mI)
 	}
 
 	return MonitorIncident{}, utils.WrapRepoError(op, err, true, r.logger)
 }
 
-func (r *MonitorIncidentRepository) CloseIncident(ctx context.Context, monitorID uuid.UUID, endTime time.Time) error {
+// List returns incidents of a monitor, latest first
+func (r *MonitorIncidentRepository) List(ctx context.Context, monitorID uuid.UUID, limit int32, offset int32) ([]MonitorIncident, error) {
+	const op string = "repo.monitor_incident.list"
+
+	incidents, err := r.querier.ListMonitorIncidents(ctx, db.ListMonitorIncidentsParams{
+		MonitorID: utils.ToPgUUID(monitorID),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return []MonitorIncident{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+
+	return toMonitorIncidents(op, incidents)
+}
+
+// ListInRange returns incidents of a monitor which were open at any time in [from, to)
+func (r *MonitorIncidentRepository) ListInRange(ctx context.Context, monitorID uuid.UUID, from, to time.Time) ([]MonitorIncident, error) {
+	const op string = "repo.monitor_incident.list_in_range"
+
+	incidents, err := r.querier.ListMonitorIncidentsInRange(ctx, db.ListMonitorIncidentsInRangeParams{
+		MonitorID:  utils.ToPgUUID(monitorID),
+		RangeStart: utils.ToPgTimestamptz(from),
+		RangeEnd:   utils.ToPgTimestamptz(to),
+	})
+	if err != nil {
+		return []MonitorIncident{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return toMonitorIncidents(op, incidents)
+}
+
+// ListUserInRange returns incidents of all monitors of a user which were open at any time in [from, to)
+func (r *MonitorIncidentRepository) ListUserInRange(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]MonitorIncident, error) {
+	const op string = "repo.monitor_incident.list_user_in_range"
+
+	incidents, err := r.querier.ListUserIncidentsInRange(ctx, db.ListUserIncidentsInRangeParams{
+		UserID:     utils.ToPgUUID(userID),
+		RangeStart: utils.ToPgTimestamptz(from),
+		RangeEnd:   utils.ToPgTimestamptz(to),
+	})
+	if err != nil {
+		return []MonitorIncident{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return toMonitorIncidents(op, incidents)
+}
+
+// CloseIncident closes open incidents of monitor at endTime, returns closed ones
+func (r *MonitorIncidentRepository) CloseIncident(ctx context.Context, monitorID uuid.UUID, endTime time.Time) ([]MonitorIncident, error) {
 	const op string = "repo.monitor_incident.close_incident"
 
-	rowsAffected, err := r.querier.CloseMonitorIncident(ctx, db.CloseMonitorIncidentParams{
+	closed, err := r.querier.CloseMonitorIncident(ctx, db.CloseMonitorIncidentParams{
 		MonitorID: utils.ToPgUUID(monitorID),
 		EndTime:   utils.ToPgTimestamptz(endTime),
 	})
 	if err == nil {
-		if rowsAffected == 0 {
-			return &apperror.Error{
+		if len(closed) == 0 {
+			return nil, &apperror.Error{
 				Kind:    apperror.NotFound,
 				Op:      op,
 				Message: "resource not found",
 			}
 		}
-		return nil
+		return toMonitorIncidents(op, closed)
+	}
+
+	return nil, utils.WrapRepoError(op, err, false, r.logger)
+}
+
+func toMonitorIncidents(op string, incidents []db.MonitorIncident) ([]MonitorIncident, error) {
+	mIs := make([]MonitorIncident, 0, len(incidents))
+	for i := range incidents {
+		mI, err := toMonitorIncident(op, &incidents[i])
+		if err != nil {
+			return []MonitorIncident{}, err
+		}
+		mIs = append(mIs, mI)
+	}
+	return mIs, nil
+}
+
+func toMonitorIncident(op string, mI *db.MonitorIncident) (MonitorIncident, error) {
+	var evidence *executor.Evidence
+	if len(mI.Evidence) > 0 {
+		if err := json.Unmarshal(mI.Evidence, &evidence); err != nil {
+			return MonitorIncident{}, apperror.New(apperror.Internal, op, err)
+		}
 	}
 
-	return utils.WrapRepoError(op, err, false, r.logger)
+	return MonitorIncident{
+		ID:         utils.FromPgUUID(mI.ID),
+		MonitorID:  utils.FromPgUUID(mI.MonitorID),
+		Alerted:    mI.Alerted,
+		HttpStatus: mI.HttpStatus,
+		LatencyMs:  mI.LatencyMs,
+		StartTime:  utils.FromPgTimestamptz(mI.StartTime),
+		CreatedAt:  utils.FromPgTimestamptz(mI.CreatedAt),
+		EndTime:    utils.FromPgTimestamptz(mI.EndTime),
+		Evidence:   evidence,
+	}, nil
 }
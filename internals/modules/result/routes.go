@@ -0,0 +1,67 @@
+package result
+
+import "github.com/go-chi/chi/v5"
+
+// IncidentRoutes are mounted under /monitors/{monitorID}/incidents
+func IncidentRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListIncidents)
+	r.Get("/{incidentID}", h.GetIncident)
+
+	return r
+}
+
+// HistoryRoutes are mounted under /monitors/{monitorID}/checks
+func HistoryRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListCheckResults)
+
+	return r
+}
+
+// MonitorUptimeRoutes are mounted under /monitors/{monitorID}/uptime
+func MonitorUptimeRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.MonitorUptime)
+
+	return r
+}
+
+// UptimeRoutes are mounted under /uptime, aggregate of all monitors of user
+func UptimeRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.UserUptime)
+
+	return r
+}
+
+/*
+- GET: /monitors/{monitorID}/incidents?offset={}&limit={}  -> incidents of a monitor, latest first
+	req auth : true
+	body : nil
+	resp : ListIncidentsResponse
+
+- GET: /monitors/{monitorID}/incidents/{incidentID} -> an incident with evidence of failed check
+	req auth : true
+	body : nil
+	resp : IncidentResponse
+
+- GET: /monitors/{monitorID}/checks?offset={}&limit={}  -> check history of a monitor, latest first
+	req auth : true
+	body : nil
+	resp : ListCheckResultsResponse
+
+- GET: /monitors/{monitorID}/uptime?window=24h|7d|30d|90d  (or from={RFC3339}&to={RFC3339})  -> uptime, downtime, incidents, MTTR
+	req auth : true
+	body : nil
+	resp : UptimeResponse
+
+- GET: /uptime?window=30d  (or from/to) -> uptime of all monitors of user, and their aggregate
+	req auth : true
+	body : nil
+	resp : UptimeResponse (with monitors)
+*/
@@ -0,0 +1,127 @@
+package result
+
+import (
+	"context"
+	"project-k/internals/modules/monitor"
+	"project-k/pkg/apperror"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MonitorOwner reads monitors of a user, GetMonitor returns NotFound if user does not own it
+type MonitorOwner interface {
+	GetMonitor(context.Context, uuid.UUID, uuid.UUID) (monitor.Monitor, error)
+	GetAllMonitors(context.Context, uuid.UUID, int32, int32) ([]monitor.Monitor, error)
+}
+
+// Service serves incidents and check history of monitors to their owners
+type Service struct {
+	incidentRepo *MonitorIncidentRepository
+	historyRepo  *CheckResultRepository
+	monitorSvc   MonitorOwner
+}
+
+func NewService(incidentRepo *MonitorIncidentRepository, historyRepo *CheckResultRepository, monitorSvc MonitorOwner) *Service {
+	return &Service{
+		incidentRepo: incidentRepo,
+		historyRepo:  historyRepo,
+		monitorSvc:   monitorSvc,
+	}
+}
+
+func (s *Service) ListIncidents(ctx context.Context, userID, monitorID uuid.UUID, limit int32, offset int32) ([]MonitorIncident, error) {
+	if _, err := s.monitorSvc.GetMonitor(ctx, userID, monitorID); err != nil {
+		return []MonitorIncident{}, err
+	}
+	return s.incidentRepo.List(ctx, monitorID, limit, offset)
+}
+
+func (s *Service) GetIncident(ctx context.Context, userID, monitorID, incidentID uuid.UUID) (MonitorIncident, error) {
+	const op string = "service.incident.get_incident"
+
+	if _, err := s.monitorSvc.GetMonitor(ctx, userID, monitorID); err != nil {
+		return MonitorIncident{}, err
+	}
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID)
+	if err != nil {
+		return MonitorIncident{}, err
+	}
+	// incident of some other monitor, same as not found
+	if incident.MonitorID != monitorID {
+		return MonitorIncident{}, &apperror.Error{
+			Kind:    apperror.NotFound,
+			Op:      op,
+			Message: "incident not found",
+		}
+	}
+	return incident, nil
+}
+
+// ListCheckResults returns recent checks of a monitor, latest first
+func (s *Service) ListCheckResults(ctx context.Context, userID, monitorID uuid.UUID, limit int32, offset int32) ([]CheckResult, error) {
+	if _, err := s.monitorSvc.GetMonitor(ctx, userID, monitorID); err != nil {
+		return []CheckResult{}, err
+	}
+	return s.historyRepo.List(ctx, monitorID, limit, offset)
+}
+
+// MonitorUptime computes uptime of a monitor over [from, to)
+func (s *Service) MonitorUptime(ctx context.Context, userID, monitorID uuid.UUID, from, to time.Time) (Uptime, error) {
+	m, err := s.monitorSvc.GetMonitor(ctx, userID, monitorID)
+	if err != nil {
+		return Uptime{}, err
+	}
+
+	incidents, err := s.incidentRepo.ListInRange(ctx, monitorID, from, to)
+	if err != nil {
+		return Uptime{}, err
+	}
+	checks, err := s.historyRepo.CountInRange(ctx, monitorID, from, to)
+	if err != nil {
+		return Uptime{}, err
+	}
+
+	return computeUptime(monitorID, m.CreatedAt, from, to, time.Now(), incidents, checks), nil
+}
+
+// UserUptime computes uptime of every monitor of a user over [from, to), and their aggregate
+func (s *Service) UserUptime(ctx context.Context, userID uuid.UUID, from, to time.Time) (Uptime, error) {
+	const pageSize = 100
+
+	var monitors []monitor.Monitor
+	for offset := int32(0); ; offset += pageSize {
+		page, err := s.monitorSvc.GetAllMonitors(ctx, userID, pageSize, offset)
+		if err != nil {
+			return Uptime{}, err
+		}
+		monitors = append(monitors, page...)
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	incidents, err := s.incidentRepo.ListUserInRange(ctx, userID, from, to)
+	if err != nil {
+		return Uptime{}, err
+	}
+	checks, err := s.historyRepo.CountUserInRange(ctx, userID, from, to)
+	if err != nil {
+		return Uptime{}, err
+	}
+
+	byMonitor := make(map[uuid.UUID][]MonitorIncident)
+	for _, inc := range incidents {
+		byMonitor[inc.MonitorID] = append(byMonitor[inc.MonitorID], inc)
+	}
+
+	now := time.Now()
+	uptimes := make([]Uptime, 0, len(monitors))
+	for i := range monitors {
+		m := &monitors[i]
+		uptimes = append(uptimes, computeUptime(m.ID, m.CreatedAt, from, to, now, byMonitor[m.ID], checks[m.ID]))
+	}
+
+	return aggregateUptime(from, to, uptimes), nil
+}
@@ -0,0 +1,41 @@
+package result
+
+import (
+	"fmt"
+	"project-k/internals/modules/executor"
+	"project-k/pkg/redisstore"
+)
+
+// toStatus maps a check result to the status stored in redis
+func toStatus(r executor.HTTPResult) redisstore.Status {
+	s := redisstore.Status{
+		StatusCode: r.Status,
+		LatencyMs:  r.LatencyMs,
+		RemoteIP:   r.RemoteIP,
+		CheckedAt:  r.CheckedAt,
+		Reason:     r.Reason,
+		Detail:     r.Detail,
+		DNSMs:      r.Timings.DNSMs,
+		ConnectMs:  r.Timings.ConnectMs,
+		TLSMs:      r.Timings.TLSMs,
+		TTFBMs:     r.Timings.TTFBMs,
+		DownloadMs: r.Timings.DownloadMs,
+
+		RoundTripMs: r.Timings.RoundTripMs,
+	}
+	if r.Cert != nil {
+		s.CertExpiresAt = r.Cert.NotAfter
+		s.CertSubject = r.Cert.Subject
+		s.CertIssuer = r.Cert.Issuer
+		s.CertSANs = r.Cert.DNSNames
+		s.CertValid = r.Cert.ChainValid
+	}
+	for _, hop := range r.Redirects {
+		s.Redirects = append(s.Redirects, fmt.Sprintf("%d %s", hop.Status, hop.URL))
+	}
+	for _, step := range r.Steps {
+		s.Steps = append(s.Steps, fmt.Sprintf("%d %s %d %dms", step.Index, step.Name, step.Status, step.LatencyMs))
+	}
+	s.FailedStep = r.FailedStep
+	return s
+}
@@ -1,9 +1,13 @@
 package result
 
 import (
+	"strconv"
 	"time"
 
+	"project-k/internals/modules/alert"
 	"project-k/internals/modules/executor"
+
+	"github.com/google/uuid"
 )
 
 func (rp *ResultProcessor) successWorker() {
@@ -26,8 +30,10 @@ func (rp *ResultProcessor) handleSuccess(r executor.HTTPResult) {
 		rp.monitorSvc.ScheduleMonitor(ctx, r.MonitorID, r.IntervalSec, "result.success_worker")
 	}()
 
+	rp.alertCertExpiry(r)
+
 	// store success in redis
-	if err := rp.redisSvc.StoreStatus(ctx, r.MonitorID, r.Status, r.LatencyMs, r.CheckedAt); err != nil {
+	if err := rp.redisSvc.StoreStatus(ctx, r.MonitorID, toStatus(r)); err != nil {
 		rp.logger.Error().
 			Err(err).
 			Str("monitor_id", r.MonitorID.String()).
@@ -44,7 +50,7 @@ func (rp *ResultProcessor) handleSuccess(r executor.HTTPResult) {
 			Msg("failed to get incident from redis, skipping recovery")
 		return
 	}
-	if incident == nil { // No incident → nothing to recover
+	if len(incident) == 0 { // No incident → nothing to recover
 		rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("No old incident found in redis")
 		return
 	}
@@ -53,15 +59,22 @@ func (rp *ResultProcessor) handleSuccess(r executor.HTTPResult) {
 
 	// Close DB incident IF it was ever created
 	dbIncident := incident["db_incident"] == "true"
+	resolvedAt := time.Now()
 
+	var closed []MonitorIncident
 	if dbIncident {
-		if err := rp.incidentRepo.CloseIncident(ctx, r.MonitorID, time.Now()); err != nil {
+		if closed, err = rp.incidentRepo.CloseIncident(ctx, r.MonitorID, resolvedAt); err != nil {
 			rp.logger.Error().
 				Err(err).
 				Msg("failed to close incident in DB, keeping redis incident")
 		}
 	}
 
+	// user was told it is down, tell it is back
+	if incident["alerted"] == "true" {
+		rp.alertRecovered(r.MonitorID, incident, closed, resolvedAt)
+	}
+
 	// Clear Redis incident (safe now)
 	if err := rp.redisSvc.ClearIncident(ctx, r.MonitorID); err != nil {
 		rp.logger.Error().
@@ -78,3 +91,43 @@ func (rp *ResultProcessor) handleSuccess(r executor.HTTPResult) {
 			Msg("failed to clear retry state from redis")
 	}
 }
+
+// alertRecovered sends RECOVERED for incident of redis, with details of DB incident it closed (if any),
+// incident start falls back to first failure in redis when DB incident is missing.
+// incident id is the one DOWN was sent with (kept in redis), so both share a dedup key even when closing failed
+func (rp *ResultProcessor) alertRecovered(monitorID uuid.UUID, incident map[string]string, closed []MonitorIncident, resolvedAt time.Time) {
+	ev := alert.AlertEvent{
+		MonitorID: monitorID,
+		Type:      alert.AlertRecovered,
+		Incident: &alert.IncidentDetails{
+			ResolvedAt: resolvedAt,
+			LastReason: incident["last_reason"],
+		},
+	}
+
+	if id, err := uuid.Parse(incident["incident_id"]); err == nil {
+		ev.IncidentID = id
+	}
+
+	if len(closed) > 0 {
+		// oldest open incident, it is the one user was alerted about
+		first := closed[0]
+		for _, inc := range closed[1:] {
+			if inc.StartTime.Before(first.StartTime) {
+				first = inc
+			}
+		}
+		if ev.IncidentID == uuid.Nil { // alerted before its id was kept in redis
+			ev.IncidentID = first.ID
+		}
+		ev.Incident.StartedAt = first.StartTime
+	} else if ts, err := strconv.ParseInt(incident["first_failure_at"], 10, 64); err == nil {
+		ev.Incident.StartedAt = time.Unix(ts, 0)
+	}
+	if !ev.Incident.StartedAt.IsZero() {
+		ev.Incident.Duration = resolvedAt.Sub(ev.Incident.StartedAt)
+	}
+
+	rp.alertChan <- ev
+	rp.logger.Info().Str("monitor_id", monitorID.String()).Msg("Send recovery Alert to alert channel")
+}
@@ -0,0 +1,96 @@
+package result
+
+import (
+	"testing"
+	"time"
+
+	"project-k/internals/modules/alert"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestAlertRecovered(t *testing.T) {
+	monitorID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	downID := uuid.MustParse("00000000-0000-0000-0000-0000000000d1")
+	otherID := uuid.MustParse("00000000-0000-0000-0000-0000000000d2")
+	start := time.Unix(1700000000, 0)
+	resolvedAt := start.Add(10 * time.Minute)
+
+	tests := []struct {
+		name       string
+		incident   map[string]string
+		closed     []MonitorIncident
+		incidentID uuid.UUID
+		startedAt  time.Time // zero when start is unknown, duration is then zero too
+	}{
+		{
+			name:       "closed incident alerted as down",
+			incident:   map[string]string{"incident_id": downID.String()},
+			closed:     []MonitorIncident{{ID: downID, StartTime: start}},
+			incidentID: downID,
+			startedAt:  start,
+		},
+		{
+			// closing failed or found nothing, RECOVERED still resolves what DOWN opened
+			// start falls back to first failure kept in redis
+			name:       "nothing closed",
+			incident:   map[string]string{"incident_id": downID.String(), "first_failure_at": "1699999940"},
+			incidentID: downID,
+			startedAt:  start.Add(-time.Minute),
+		},
+		{
+			name:       "closed an older incident too",
+			incident:   map[string]string{"incident_id": downID.String()},
+			closed:     []MonitorIncident{{ID: downID, StartTime: start}, {ID: otherID, StartTime: start.Add(-time.Hour)}},
+			incidentID: downID,
+			startedAt:  start.Add(-time.Hour),
+		},
+		{
+			name:       "alerted before id was kept in redis",
+			incident:   map[string]string{"first_failure_at": "1699999000"},
+			closed:     []MonitorIncident{{ID: downID, StartTime: start}},
+			incidentID: downID,
+			startedAt:  start, // closed incident wins over redis
+		},
+		{
+			name:      "down was sent without incident",
+			incident:  map[string]string{"first_failure_at": "1700000000"},
+			startedAt: start,
+		},
+		{
+			name:     "start unknown",
+			incident: map[string]string{},
+		},
+		{
+			name:     "bad first failure",
+			incident: map[string]string{"first_failure_at": "yesterday"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nop := zerolog.Nop()
+			rp := &ResultProcessor{alertChan: make(chan alert.AlertEvent, 1), logger: &nop}
+
+			rp.alertRecovered(monitorID, tt.incident, tt.closed, resolvedAt)
+
+			ev := <-rp.alertChan
+			if ev.Type != alert.AlertRecovered || ev.MonitorID != monitorID {
+				t.Errorf("event = %s of %s, want RECOVERED of %s", ev.Type, ev.MonitorID, monitorID)
+			}
+			if ev.IncidentID != tt.incidentID {
+				t.Errorf("incident id = %s, want %s", ev.IncidentID, tt.incidentID)
+			}
+			var duration time.Duration
+			if !tt.startedAt.IsZero() {
+				duration = resolvedAt.Sub(tt.startedAt)
+			}
+			inc := ev.Incident
+			if !inc.StartedAt.Equal(tt.startedAt) || inc.Duration != duration || !inc.ResolvedAt.Equal(resolvedAt) {
+				t.Errorf("started, duration, resolved = %v, %v, %v, want %v, %v, %v",
+					inc.StartedAt, inc.Duration, inc.ResolvedAt, tt.startedAt, duration, resolvedAt)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package result
 
 import (
+	"project-k/internals/modules/executor"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,4 +16,21 @@ type MonitorIncident struct {
 	HttpStatus int32
 	LatencyMs  int32
 	CreatedAt  time.Time
-}
\ No newline at end of file
+	Evidence   *executor.Evidence // nil for incidents opened before evidence was kept
+}
+type CheckResult struct {
+	ID         int64
+	MonitorID  uuid.UUID
+	CheckedAt  time.Time
+	Success    bool
+	HttpStatus int32
+	LatencyMs  int32
+	Reason     string
+	Region     string
+}
+
+// CheckCounts is number of checks in a time range, and how many of them failed
+type CheckCounts struct {
+	Total  int64
+	Failed int64
+}
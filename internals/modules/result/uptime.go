@@ -0,0 +1,191 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// max span of a custom uptime window
+const maxUptimeWindow = 366 * 24 * time.Hour
+
+// preset windows of uptime api
+var uptimeWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// Uptime of a monitor (or all monitors of a user) over [From, To)
+type Uptime struct {
+	MonitorID uuid.UUID // zero for aggregate of a user
+	From      time.Time
+	To        time.Time
+	// part of window monitor existed in, window before monitor was created is not counted
+	MonitoredSec  int64
+	DowntimeSec   int64
+	UptimePercent float64
+	IncidentCount int
+	// mean time to recovery of incidents resolved in window, 0 if none was resolved
+	MTTRSec int64
+	Checks  CheckCounts
+	// set only on aggregate
+	Monitors []Uptime
+
+	// total repair time and count of incidents resolved in window, to aggregate MTTR
+	repairSec int64
+	resolved  int64
+}
+
+// ParseUptimeWindow resolves window (24h, 7d, 30d, 90d) or custom from, to (RFC3339) into a time range,
+// to defaults to now, window defaults to 24h when neither is given
+func ParseUptimeWindow(window, from, to string, now time.Time) (time.Time, time.Time, error) {
+	if window != "" {
+		if from != "" || to != "" {
+			return time.Time{}, time.Time{}, errors.New("window and from/to can not be used together")
+		}
+		d, ok := uptimeWindows[window]
+		if !ok {
+			return time.Time{}, time.Time{}, fmt.Errorf("unknown window %q, use 24h, 7d, 30d or 90d", window)
+		}
+		return now.Add(-d), now, nil
+	}
+
+	if from == "" {
+		if to != "" {
+			return time.Time{}, time.Time{}, errors.New("from is required with to")
+		}
+		return now.Add(-uptimeWindows["24h"]), now, nil
+	}
+
+	start, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be RFC3339: %v", err)
+	}
+	end := now
+	if to != "" {
+		if end, err = time.Parse(time.RFC3339, to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be RFC3339: %v", err)
+		}
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	if end.Sub(start) > maxUptimeWindow {
+		return time.Time{}, time.Time{}, errors.New("window can not be longer than 366 days")
+	}
+	return start, end, nil
+}
+
+// computeUptime computes uptime of a monitor created at createdAt, from its incidents open in [from, to),
+// open incidents count as down till now
+func computeUptime(monitorID uuid.UUID, createdAt, from, to, now time.Time, incidents []MonitorIncident, checks CheckCounts) Uptime {
+	u := Uptime{
+		MonitorID: monitorID,
+		From:      from,
+		To:        to,
+		Checks:    checks,
+	}
+
+	start := from
+	if createdAt.After(start) {
+		start = createdAt
+	}
+	end := to
+	if now.Before(end) {
+		end = now
+	}
+	if !start.Before(end) { // monitor did not exist in window
+		u.UptimePercent = 100
+		return u
+	}
+	u.MonitoredSec = int64(end.Sub(start).Seconds())
+
+	// clip incidents to window, and merge the overlapping ones so downtime is not counted twice
+	type span struct{ start, end time.Time }
+	spans := make([]span, 0, len(incidents))
+	var repair time.Duration
+	for i := range incidents {
+		inc := &incidents[i]
+		u.IncidentCount++
+
+		incEnd := inc.EndTime
+		if incEnd.IsZero() { // still open
+			incEnd = now
+		} else if !incEnd.Before(from) && incEnd.Before(to) { // resolved in window
+			repair += incEnd.Sub(inc.StartTime)
+			u.resolved++
+		}
+
+		s, e := inc.StartTime, incEnd
+		if s.Before(start) {
+			s = start
+		}
+		if e.After(end) {
+			e = end
+		}
+		if s.Before(e) {
+			spans = append(spans, span{s, e})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+	var down time.Duration
+	for i := 0; i < len(spans); {
+		cur := spans[i]
+		for i++; i < len(spans) && !spans[i].start.After(cur.end); i++ {
+			if spans[i].end.After(cur.end) {
+				cur.end = spans[i].end
+			}
+		}
+		down += cur.end.Sub(cur.start)
+	}
+
+	u.DowntimeSec = int64(down.Seconds())
+	u.UptimePercent = uptimePercent(u.MonitoredSec, u.DowntimeSec)
+	u.repairSec = int64(repair.Seconds())
+	if u.resolved > 0 {
+		u.MTTRSec = u.repairSec / u.resolved
+	}
+	return u
+}
+
+// aggregateUptime sums uptime of monitors of a user, uptime percent is weighted by monitored time of each monitor
+func aggregateUptime(from, to time.Time, monitors []Uptime) Uptime {
+	agg := Uptime{
+		From:     from,
+		To:       to,
+		Monitors: monitors,
+	}
+
+	for i := range monitors {
+		m := &monitors[i]
+		agg.MonitoredSec += m.MonitoredSec
+		agg.DowntimeSec += m.DowntimeSec
+		agg.IncidentCount += m.IncidentCount
+		agg.Checks.Total += m.Checks.Total
+		agg.Checks.Failed += m.Checks.Failed
+		agg.repairSec += m.repairSec
+		agg.resolved += m.resolved
+	}
+
+	agg.UptimePercent = uptimePercent(agg.MonitoredSec, agg.DowntimeSec)
+	if agg.resolved > 0 {
+		agg.MTTRSec = agg.repairSec / agg.resolved
+	}
+	return agg
+}
+
+// uptimePercent is rounded to 4 decimals (99.9999), 100 if nothing was monitored
+func uptimePercent(monitoredSec, downtimeSec int64) float64 {
+	if monitoredSec <= 0 {
+		return 100
+	}
+	p := float64(monitoredSec-downtimeSec) / float64(monitoredSec) * 100
+	return math.Round(p*1e4) / 1e4
+}
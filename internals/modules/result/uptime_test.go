@@ -0,0 +1,218 @@
+package result
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestComputeUptime(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h float64) time.Time { return t0.Add(time.Duration(h * float64(time.Hour))) }
+	inc := func(start, end float64) MonitorIncident {
+		i := MonitorIncident{StartTime: at(start)}
+		if end >= 0 {
+			i.EndTime = at(end)
+		}
+		return i
+	}
+	const open = -1
+
+	// window is [0h, 10h) unless a case moves now or createdAt
+	tests := []struct {
+		name       string
+		createdAt  time.Time
+		now        time.Time
+		incidents  []MonitorIncident
+		monitored  int64
+		downtime   int64
+		percent    float64
+		count      int
+		mttr       int64
+		resolvedIn int64
+	}{
+		{
+			name:      "no incidents",
+			monitored: 36000, percent: 100,
+		},
+		{
+			name:      "incident inside window",
+			incidents: []MonitorIncident{inc(1, 2)},
+			monitored: 36000, downtime: 3600, percent: 90, count: 1, mttr: 3600, resolvedIn: 1,
+		},
+		{
+			// downtime is clipped to window, repair time is the whole incident
+			name:      "started before window, resolved in it",
+			incidents: []MonitorIncident{inc(-2, 1)},
+			monitored: 36000, downtime: 3600, percent: 90, count: 1, mttr: 3 * 3600, resolvedIn: 1,
+		},
+		{
+			name:      "resolved after window is not in mttr",
+			incidents: []MonitorIncident{inc(9, 11)},
+			monitored: 36000, downtime: 3600, percent: 90, count: 1,
+		},
+		{
+			name:      "overlapping and touching incidents are merged",
+			incidents: []MonitorIncident{inc(3, 4), inc(1, 3), inc(2, 4), inc(4, 5)},
+			monitored: 36000, downtime: 4 * 3600, percent: 60, count: 4,
+			mttr: (3600 + 2*3600 + 2*3600 + 3600) / 4, resolvedIn: 4,
+		},
+		{
+			name:      "nested incident",
+			incidents: []MonitorIncident{inc(1, 5), inc(2, 3)},
+			monitored: 36000, downtime: 4 * 3600, percent: 60, count: 2, mttr: (4*3600 + 3600) / 2, resolvedIn: 2,
+		},
+		{
+			name:      "window before creation is not monitored",
+			createdAt: at(5),
+			incidents: []MonitorIncident{inc(4, 6)},
+			monitored: 5 * 3600, downtime: 3600, percent: 80, count: 1, mttr: 2 * 3600, resolvedIn: 1,
+		},
+		{
+			name:      "created after window",
+			createdAt: at(12),
+			percent:   100,
+		},
+		{
+			name:      "open incident is down till now",
+			now:       at(5),
+			incidents: []MonitorIncident{inc(4, open)},
+			monitored: 5 * 3600, downtime: 3600, percent: 80, count: 1,
+		},
+		{
+			name:      "open incident is clipped to window end",
+			now:       at(12),
+			incidents: []MonitorIncident{inc(8, open)},
+			monitored: 36000, downtime: 2 * 3600, percent: 80, count: 1,
+		},
+		{
+			name:      "uptime percent is rounded to 4 decimals",
+			incidents: []MonitorIncident{{StartTime: at(1), EndTime: at(1).Add(7 * time.Second)}},
+			monitored: 36000, downtime: 7, percent: 99.9806, count: 1, mttr: 7, resolvedIn: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			createdAt := tt.createdAt
+			if createdAt.IsZero() {
+				createdAt = at(-100)
+			}
+			now := tt.now
+			if now.IsZero() {
+				now = at(10)
+			}
+
+			u := computeUptime(uuid.Nil, createdAt, at(0), at(10), now, tt.incidents, CheckCounts{})
+			if u.MonitoredSec != tt.monitored || u.DowntimeSec != tt.downtime {
+				t.Errorf("monitored, downtime = %d, %d, want %d, %d", u.MonitoredSec, u.DowntimeSec, tt.monitored, tt.downtime)
+			}
+			if u.UptimePercent != tt.percent {
+				t.Errorf("uptime = %v, want %v", u.UptimePercent, tt.percent)
+			}
+			if u.IncidentCount != tt.count {
+				t.Errorf("incidents = %d, want %d", u.IncidentCount, tt.count)
+			}
+			if u.MTTRSec != tt.mttr || u.resolved != tt.resolvedIn {
+				t.Errorf("mttr, resolved = %d, %d, want %d, %d", u.MTTRSec, u.resolved, tt.mttr, tt.resolvedIn)
+			}
+		})
+	}
+}
+
+func TestAggregateUptime(t *testing.T) {
+	from, to := time.Unix(0, 0), time.Unix(100000, 0)
+
+	tests := []struct {
+		name     string
+		monitors []Uptime
+		percent  float64
+		mttr     int64
+	}{
+		{name: "no monitors", percent: 100},
+		{
+			// weighted by monitored time, a mean of percents would say 50
+			name: "weighted by monitored time",
+			monitors: []Uptime{
+				{MonitoredSec: 99000, UptimePercent: 100},
+				{MonitoredSec: 1000, DowntimeSec: 1000, UptimePercent: 0, IncidentCount: 1, repairSec: 1000, resolved: 1},
+			},
+			percent: 99, mttr: 1000,
+		},
+		{
+			// mttr is over all resolved incidents, not a mean of monitor mttrs (which would be 550)
+			name: "mttr over all resolved incidents",
+			monitors: []Uptime{
+				{MonitoredSec: 50000, DowntimeSec: 300, IncidentCount: 3, repairSec: 300, resolved: 3, MTTRSec: 100},
+				{MonitoredSec: 50000, DowntimeSec: 1000, IncidentCount: 2, repairSec: 1000, resolved: 1, MTTRSec: 1000},
+			},
+			percent: 98.7, mttr: 325,
+		},
+		{
+			name:     "nothing monitored",
+			monitors: []Uptime{{}, {}},
+			percent:  100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agg := aggregateUptime(from, to, tt.monitors)
+			if agg.UptimePercent != tt.percent || agg.MTTRSec != tt.mttr {
+				t.Errorf("uptime, mttr = %v, %d, want %v, %d", agg.UptimePercent, agg.MTTRSec, tt.percent, tt.mttr)
+			}
+			var monitored, down int64
+			var incidents int
+			for _, m := range tt.monitors {
+				monitored += m.MonitoredSec
+				down += m.DowntimeSec
+				incidents += m.IncidentCount
+			}
+			if agg.MonitoredSec != monitored || agg.DowntimeSec != down || agg.IncidentCount != incidents {
+				t.Errorf("totals = %d, %d, %d, want %d, %d, %d",
+					agg.MonitoredSec, agg.DowntimeSec, agg.IncidentCount, monitored, down, incidents)
+			}
+			if agg.MonitorID != uuid.Nil || len(agg.Monitors) != len(tt.monitors) {
+				t.Errorf("aggregate has monitor id %s or %d monitors", agg.MonitorID, len(agg.Monitors))
+			}
+		})
+	}
+}
+
+func TestParseUptimeWindow(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		window, from, to string
+		wantFrom         time.Time
+		wantTo           time.Time
+		wantErr          bool
+	}{
+		{wantFrom: now.Add(-24 * time.Hour), wantTo: now},
+		{window: "7d", wantFrom: now.Add(-7 * 24 * time.Hour), wantTo: now},
+		{window: "1y", wantErr: true},
+		{window: "7d", from: "2026-05-01T00:00:00Z", wantErr: true},
+		{from: "2026-05-01T00:00:00Z", wantFrom: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), wantTo: now},
+		{from: "2026-05-01T00:00:00Z", to: "2026-05-02T00:00:00Z",
+			wantFrom: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{to: "2026-05-02T00:00:00Z", wantErr: true},
+		{from: "yesterday", wantErr: true},
+		{from: "2026-05-02T00:00:00Z", to: "2026-05-01T00:00:00Z", wantErr: true},
+		{from: "2024-01-01T00:00:00Z", to: "2026-01-01T00:00:00Z", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		from, to, err := ParseUptimeWindow(tt.window, tt.from, tt.to, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseUptimeWindow(%q, %q, %q) err = nil", tt.window, tt.from, tt.to)
+			}
+			continue
+		}
+		if err != nil || !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+			t.Errorf("ParseUptimeWindow(%q, %q, %q) = %v, %v, %v, want %v, %v",
+				tt.window, tt.from, tt.to, from, to, err, tt.wantFrom, tt.wantTo)
+		}
+	}
+}
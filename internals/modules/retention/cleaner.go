@@ -0,0 +1,176 @@
+package retention
+
+import (
+	"context"
+	"project-k/config"
+	"project-k/internals/modules/rollup"
+	"project-k/pkg/redisstore"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// name of redis lock, held by the instance cleaning up
+const lockName = "retention"
+
+// Cleaner is a background process that deletes check history and rollups past retention of owner's plan.
+// Rows are deleted in small batches with a pause between them, so no statement holds locks for long
+type Cleaner struct {
+	// lifecycle
+	ctx        context.Context
+	interval   time.Duration
+	batchSize  int
+	batchPause time.Duration
+
+	// policies
+	free config.RetentionPolicyConfig
+	paid config.RetentionPolicyConfig
+
+	// services
+	repo     *Repository
+	redisSvc *redisstore.Client
+
+	// misc
+	owner  string // id of this instance in retention lock
+	logger *zerolog.Logger
+}
+
+func NewCleaner(
+	ctx context.Context,
+	retentionConfig *config.RetentionConfig,
+	repo *Repository,
+	redisSvc *redisstore.Client,
+	logger *zerolog.Logger,
+) *Cleaner {
+
+	return &Cleaner{
+		ctx:        ctx,
+		interval:   retentionConfig.Interval,
+		batchSize:  retentionConfig.BatchSize,
+		batchPause: retentionConfig.BatchPause,
+		free:       retentionConfig.Free,
+		paid:       retentionConfig.Paid,
+		repo:       repo,
+		redisSvc:   redisSvc,
+		owner:      uuid.NewString(),
+		logger:     logger,
+	}
+}
+
+// Run starts the Cleaner
+func (c *Cleaner) Run() {
+	if c.interval <= 0 {
+		panic("retention loop interval must be > 0")
+	}
+	c.logger.Info().Msg("Retention cleaner started")
+	ticker := time.NewTicker(c.interval)
+	defer func() {
+		ticker.Stop()
+		c.logger.Info().Msg("Retention cleaner stopped")
+	}()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+
+		case <-ticker.C:
+			c.doWork()
+		}
+	}
+}
+
+func (c *Cleaner) doWork() {
+	// every instance runs the loop, one of them does the work.
+	// a run longer than interval may overlap with next one on another instance, deletes are idempotent so it is fine
+	ok, err := c.redisSvc.AcquireLock(c.ctx, lockName, c.owner, c.interval)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("error in acquiring retention lock")
+		return
+	}
+	if !ok {
+		return
+	}
+	defer func() {
+		if err := c.redisSvc.ReleaseLock(c.ctx, lockName, c.owner); err != nil {
+			c.logger.Error().Err(err).Msg("error in releasing retention lock")
+		}
+	}()
+
+	raw, minute, coarse := c.cutoffsAt(time.Now().UTC())
+
+	deleted, err := c.drain(func(ctx context.Context) (int64, error) {
+		return c.repo.DeleteCheckResults(ctx, raw, c.batchSize)
+	})
+	c.logResult("check_results", deleted, err)
+	if err != nil {
+		return
+	}
+
+	for _, res := range []struct {
+		name string
+		cut  cutoffs
+	}{
+		{rollup.ResolutionMinute, minute},
+		{rollup.ResolutionHour, coarse},
+		{rollup.ResolutionDay, coarse},
+	} {
+		deleted, err := c.drain(func(ctx context.Context) (int64, error) {
+			return c.repo.DeleteRollups(ctx, res.name, res.cut, c.batchSize)
+		})
+		c.logResult("latency_rollups_"+res.name, deleted, err)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// cutoffsAt returns cutoffs of check results, minute rollups and hour / day rollups at now
+func (c *Cleaner) cutoffsAt(now time.Time) (raw, minute, coarse cutoffs) {
+	raw = cutoffs{
+		free: now.AddDate(0, 0, -c.free.RawDays),
+		paid: now.AddDate(0, 0, -c.paid.RawDays),
+	}
+	minute = cutoffs{
+		free: now.AddDate(0, 0, -c.free.MinuteRollupDays),
+		paid: now.AddDate(0, 0, -c.paid.MinuteRollupDays),
+	}
+	coarse = cutoffs{
+		free: now.AddDate(0, -c.free.RollupMonths, 0),
+		paid: now.AddDate(0, -c.paid.RollupMonths, 0),
+	}
+	return raw, minute, coarse
+}
+
+// drain runs deleteBatch till a batch deletes less than batchSize rows, or ctx is done,
+// returns total rows deleted
+func (c *Cleaner) drain(deleteBatch func(ctx context.Context) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		n, err := deleteBatch(c.ctx)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(c.batchSize) {
+			return total, nil
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return total, nil
+		case <-time.After(c.batchPause):
+		}
+	}
+}
+
+func (c *Cleaner) logResult(table string, deleted int64, err error) {
+	if err != nil {
+		c.logger.Error().Err(err).Str("table", table).Int64("deleted", deleted).Msg("error in deleting expired rows")
+		return
+	}
+	if deleted > 0 {
+		c.logger.Info().Str("table", table).Int64("deleted", deleted).Msg("deleted expired rows")
+	}
+}
@@ -0,0 +1,80 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"project-k/config"
+)
+
+func TestCutoffsAt(t *testing.T) {
+	c := &Cleaner{
+		free: config.RetentionPolicyConfig{RawDays: 7, MinuteRollupDays: 7, RollupMonths: 3},
+		paid: config.RetentionPolicyConfig{RawDays: 30, MinuteRollupDays: 14, RollupMonths: 24},
+	}
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 12, 0, 0, 0, time.UTC) }
+
+	raw, minute, coarse := c.cutoffsAt(date(2026, 6, 15))
+
+	tests := []struct {
+		name      string
+		got, want cutoffs
+	}{
+		{"raw", raw, cutoffs{free: date(2026, 6, 8), paid: date(2026, 5, 16)}},
+		{"minute rollups", minute, cutoffs{free: date(2026, 6, 8), paid: date(2026, 6, 1)}},
+		{"hour and day rollups", coarse, cutoffs{free: date(2026, 3, 15), paid: date(2024, 6, 15)}},
+	}
+	for _, tt := range tests {
+		if !tt.got.free.Equal(tt.want.free) || !tt.got.paid.Equal(tt.want.paid) {
+			t.Errorf("%s cutoffs = %v / %v, want %v / %v", tt.name, tt.got.free, tt.got.paid, tt.want.free, tt.want.paid)
+		}
+	}
+}
+
+func TestDrain(t *testing.T) {
+	errDB := errors.New("db down")
+
+	tests := []struct {
+		name    string
+		batches []int64 // rows deleted by each call, calls past the end delete nothing
+		err     error   // returned by the call after batches, when set
+		cancel  int     // ctx is cancelled after this many calls, 0 for never
+		calls   int
+		total   int64
+	}{
+		{name: "nothing expired", calls: 1},
+		{name: "one partial batch", batches: []int64{2}, calls: 1, total: 2},
+		{name: "full batches then partial", batches: []int64{3, 3, 1}, calls: 3, total: 7},
+		{name: "full batches then empty", batches: []int64{3, 3}, calls: 3, total: 6},
+		{name: "error stops drain", batches: []int64{3}, err: errDB, calls: 2, total: 3},
+		{name: "cancel stops drain", batches: []int64{3, 3, 3, 3}, cancel: 2, calls: 2, total: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c := &Cleaner{ctx: ctx, batchSize: 3, batchPause: time.Millisecond}
+
+			calls := 0
+			total, err := c.drain(func(context.Context) (int64, error) {
+				calls++
+				if calls == tt.cancel {
+					cancel()
+				}
+				if calls <= len(tt.batches) {
+					return tt.batches[calls-1], nil
+				}
+				return 0, tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+			if calls != tt.calls || total != tt.total {
+				t.Errorf("calls, total = %d, %d, want %d, %d", calls, total, tt.calls, tt.total)
+			}
+		})
+	}
+}
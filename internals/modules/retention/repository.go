@@ -0,0 +1,59 @@
+package retention
+
+import (
+	"context"
+	"project-k/pkg/db"
+	"project-k/pkg/utils"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// DeleteCheckResults deletes at most limit check results older than cutoff of owner's plan, returns rows deleted
+func (r *Repository) DeleteCheckResults(ctx context.Context, c cutoffs, limit int) (int64, error) {
+	const op string = "repo.retention.delete_check_results"
+
+	n, err := r.querier.DeleteExpiredCheckResults(ctx, db.DeleteExpiredCheckResultsParams{
+		FreeCutoff: utils.ToPgTimestamptz(c.free),
+		PaidCutoff: utils.ToPgTimestamptz(c.paid),
+		BatchSize:  int32(limit),
+	})
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return n, nil
+}
+
+// DeleteRollups deletes at most limit latency buckets of resolution older than cutoff of owner's plan, returns rows deleted
+func (r *Repository) DeleteRollups(ctx context.Context, resolution string, c cutoffs, limit int) (int64, error) {
+	const op string = "repo.retention.delete_rollups"
+
+	n, err := r.querier.DeleteExpiredLatencyRollups(ctx, db.DeleteExpiredLatencyRollupsParams{
+		Resolution: resolution,
+		FreeCutoff: utils.ToPgTimestamptz(c.free),
+		PaidCutoff: utils.ToPgTimestamptz(c.paid),
+		BatchSize:  int32(limit),
+	})
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, false, r.logger)
+	}
+	return n, nil
+}
+
+// cutoffs of a table, rows older than cutoff of their owner's plan are expired
+type cutoffs struct {
+	free time.Time
+	paid time.Time
+}
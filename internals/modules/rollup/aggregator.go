@@ -0,0 +1,172 @@
+package rollup
+
+import (
+	"context"
+	"project-k/config"
+	"project-k/pkg/redisstore"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// name of redis lock, held by the instance rolling up
+const lockName = "rollup"
+
+// Aggregator is a background process that rolls check latencies up into minute, hour and day buckets
+type Aggregator struct {
+	// lifecycle
+	ctx      context.Context
+	interval time.Duration
+	// raw results younger than this are not rolled up yet, they may still be in history writer's batch
+	settleDelay time.Duration
+
+	// services
+	repo     *Repository
+	redisSvc *redisstore.Client
+
+	// misc
+	owner  string // id of this instance in rollup lock
+	logger *zerolog.Logger
+}
+
+func NewAggregator(
+	ctx context.Context,
+	rollupConfig *config.RollupConfig,
+	repo *Repository,
+	redisSvc *redisstore.Client,
+	logger *zerolog.Logger,
+) *Aggregator {
+
+	return &Aggregator{
+		ctx:         ctx,
+		interval:    rollupConfig.Interval,
+		settleDelay: rollupConfig.SettleDelay,
+		repo:        repo,
+		redisSvc:    redisSvc,
+		owner:       uuid.NewString(),
+		logger:      logger,
+	}
+}
+
+// Run starts the Aggregator
+func (a *Aggregator) Run() {
+	if a.interval <= 0 {
+		panic("rollup loop interval must be > 0")
+	}
+	a.logger.Info().Msg("Rollup aggregator started")
+	ticker := time.NewTicker(a.interval)
+	defer func() {
+		ticker.Stop()
+		a.logger.Info().Msg("Rollup aggregator stopped")
+	}()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+
+		case <-ticker.C:
+			a.doWork()
+		}
+	}
+}
+
+func (a *Aggregator) doWork() {
+	// every instance runs the loop, one of them does the work
+	ok, err := a.redisSvc.AcquireLock(a.ctx, lockName, a.owner, a.interval)
+	if err != nil {
+		a.logger.Error().Err(err).Msg("error in acquiring rollup lock")
+		return
+	}
+	if !ok {
+		return
+	}
+	defer func() {
+		if err := a.redisSvc.ReleaseLock(a.ctx, lockName, a.owner); err != nil {
+			a.logger.Error().Err(err).Msg("error in releasing rollup lock")
+		}
+	}()
+
+	upTo := time.Now().UTC().Add(-a.settleDelay).Truncate(time.Minute)
+	for _, res := range resolutions {
+		done, err := a.rollup(res, upTo)
+		if err != nil {
+			a.logger.Error().Err(err).Str("resolution", res.name).Msg("error in rolling up latencies")
+			return
+		}
+		// coarser resolution is rolled up only as far as its source is
+		upTo = done
+	}
+}
+
+// rollup rolls up buckets of res from its watermark to upTo (at most a chunk),
+// returns the time its buckets are complete up to
+func (a *Aggregator) rollup(res resolution, upTo time.Time) (time.Time, error) {
+	wm, err := a.redisSvc.RollupWatermark(a.ctx, res.name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if oldest := upTo.Add(-res.lookback).Truncate(res.size); wm.Before(oldest) {
+		wm = oldest
+	}
+
+	end := upTo
+	if chunkEnd := wm.Add(res.chunk); chunkEnd.Before(end) {
+		end = chunkEnd
+	}
+	if !wm.Before(end) {
+		return upTo, nil
+	}
+
+	var hists map[bucketKey]*Histogram
+	if res.source == "" {
+		if hists, err = a.repo.RawLatencies(a.ctx, wm, end); err != nil {
+			return time.Time{}, err
+		}
+	} else {
+		src, err := a.repo.Rollups(a.ctx, res.source, wm, end)
+		if err != nil {
+			return time.Time{}, err
+		}
+		hists = make(map[bucketKey]*Histogram)
+		for i := range src {
+			key := bucketKey{monitorID: src[i].MonitorID, start: src[i].Start.Truncate(res.size)}
+			h, ok := hists[key]
+			if !ok {
+				h = NewHistogram()
+				hists[key] = h
+			}
+			h.Merge(src[i].Histogram)
+		}
+	}
+
+	buckets := make([]Bucket, 0, len(hists))
+	for key, h := range hists {
+		buckets = append(buckets, Bucket{
+			MonitorID: key.monitorID,
+			Start:     key.start,
+			Histogram: h,
+			P50Ms:     h.Quantile(0.50),
+			P95Ms:     h.Quantile(0.95),
+			P99Ms:     h.Quantile(0.99),
+		})
+	}
+	if err := a.repo.Upsert(a.ctx, res.name, buckets); err != nil {
+		return time.Time{}, err
+	}
+
+	// last bucket may be partial (ex: current hour), it is rolled up again on next tick
+	if err := a.redisSvc.SetRollupWatermark(a.ctx, res.name, end.Truncate(res.size)); err != nil {
+		return time.Time{}, err
+	}
+
+	a.logger.Debug().
+		Str("resolution", res.name).
+		Time("from", wm).
+		Time("to", end).
+		Int("buckets", len(buckets)).
+		Msg("latencies rolled up")
+
+	return end, nil
+}
@@ -0,0 +1,40 @@
+package rollup
+
+import "time"
+
+type LatencyPointResponse struct {
+	Start  time.Time `json:"start"`
+	Count  int64     `json:"count"`
+	MinMs  int64     `json:"min_ms"`
+	MaxMs  int64     `json:"max_ms"`
+	MeanMs float64   `json:"mean_ms"`
+	P50Ms  int64     `json:"p50_ms"`
+	P95Ms  int64     `json:"p95_ms"`
+	P99Ms  int64     `json:"p99_ms"`
+}
+
+type LatencySeriesResponse struct {
+	MonitorID  string                 `json:"monitor_id"`
+	Resolution string                 `json:"resolution"`
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"`
+	Points     []LatencyPointResponse `json:"points"` // buckets with no checks are left out
+}
+
+func toLatencyPoints(buckets []Bucket) []LatencyPointResponse {
+	points := make([]LatencyPointResponse, 0, len(buckets))
+	for i := range buckets {
+		b := &buckets[i]
+		points = append(points, LatencyPointResponse{
+			Start:  b.Start,
+			Count:  b.Histogram.Count,
+			MinMs:  b.Histogram.Min,
+			MaxMs:  b.Histogram.Max,
+			MeanMs: b.Histogram.Mean(),
+			P50Ms:  b.P50Ms,
+			P95Ms:  b.P95Ms,
+			P99Ms:  b.P99Ms,
+		})
+	}
+	return points
+}
@@ -0,0 +1,117 @@
+package rollup
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	middle "project-k/internals/middleware"
+	"project-k/pkg/apperror"
+	"project-k/pkg/utils"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// default and max time range of a series, by resolution, max keeps a series under ~3000 points
+var seriesRanges = map[string]struct{ def, max time.Duration }{
+	ResolutionMinute: {def: 6 * time.Hour, max: 2 * 24 * time.Hour},
+	ResolutionHour:   {def: 7 * 24 * time.Hour, max: 90 * 24 * time.Hour},
+	ResolutionDay:    {def: 90 * 24 * time.Hour, max: 3 * 366 * 24 * time.Hour},
+}
+
+type Handler struct {
+	service *Service
+	logger  *zerolog.Logger
+}
+
+func NewHandler(service *Service, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// /monitors/{monitorID}/latency?resolution=hour&from=2026-09-01T00:00:00Z&to=2026-10-01T00:00:00Z
+func (h *Handler) LatencySeries(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.rollup.latency_series"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	reqClaims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user Unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	q := r.URL.Query()
+	res := q.Get("resolution")
+	if res == "" {
+		res = ResolutionHour
+	}
+	from, to, err := parseSeriesRange(res, q.Get("from"), q.Get("to"), time.Now().UTC())
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, err.Error())
+		return
+	}
+
+	buckets, err := h.service.LatencySeries(ctx, reqClaims.UserID, monitorID, res, from, to)
+	if err != nil {
+		h.logger.Error().
+			Str("op", op).
+			Str("req_id", reqID).
+			Err(err).
+			Msg("retriving latency series error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "latency series retrieved successfully", LatencySeriesResponse{
+		MonitorID:  monitorID.String(),
+		Resolution: res,
+		From:       from,
+		To:         to,
+		Points:     toLatencyPoints(buckets),
+	})
+}
+
+// parseSeriesRange resolves from, to (RFC3339) of a series, to defaults to now, from to default range of resolution
+func parseSeriesRange(res, from, to string, now time.Time) (time.Time, time.Time, error) {
+	limits, ok := seriesRanges[res]
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown resolution %q, use minute, hour or day", res)
+	}
+
+	end := now
+	if to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be RFC3339: %v", err)
+		}
+		end = t
+	}
+	start := end.Add(-limits.def)
+	if from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be RFC3339: %v", err)
+		}
+		start = t
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	if end.Sub(start) > limits.max {
+		return time.Time{}, time.Time{}, fmt.Errorf("range of %s resolution can not be longer than %s", res, limits.max)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,116 @@
+package rollup
+
+import (
+	"math"
+	"sort"
+)
+
+// growth of bucket bounds, a value is at most ~1% away from the value of its bucket
+const histogramGamma = 1.02
+
+var logGamma = math.Log(histogramGamma)
+
+// Histogram is a log bucketed (HDR style) latency histogram, bucket i > 0 holds values in (γ^(i-2), γ^(i-1)],
+// bucket 0 holds zeros. Bounds are fixed, so histograms of minute buckets merge into hour and day ones
+// with no loss, and percentiles of any bucket stay within ~1%
+type Histogram struct {
+	Count   int64
+	Min     int64
+	Max     int64
+	Sum     int64
+	Buckets map[int]int64
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{Buckets: make(map[int]int64)}
+}
+
+// AddN records n samples of value v (in ms)
+func (h *Histogram) AddN(v int64, n int64) {
+	if n <= 0 {
+		return
+	}
+	if v < 0 {
+		v = 0
+	}
+	if h.Count == 0 || v < h.Min {
+		h.Min = v
+	}
+	if h.Count == 0 || v > h.Max {
+		h.Max = v
+	}
+	h.Count += n
+	h.Sum += v * n
+	h.Buckets[bucketIndex(v)] += n
+}
+
+// Merge adds all samples of o into h
+func (h *Histogram) Merge(o *Histogram) {
+	if o.Count == 0 {
+		return
+	}
+	if h.Count == 0 || o.Min < h.Min {
+		h.Min = o.Min
+	}
+	if h.Count == 0 || o.Max > h.Max {
+		h.Max = o.Max
+	}
+	h.Count += o.Count
+	h.Sum += o.Sum
+	for i, n := range o.Buckets {
+		h.Buckets[i] += n
+	}
+}
+
+// Quantile returns the value at q (0..1), clamped to min and max seen
+func (h *Histogram) Quantile(q float64) int64 {
+	if h.Count == 0 {
+		return 0
+	}
+
+	idx := make([]int, 0, len(h.Buckets))
+	for i := range h.Buckets {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+
+	// rank of the sample at q, 0 based
+	rank := int64(math.Ceil(q*float64(h.Count))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	var seen int64
+	for _, i := range idx {
+		seen += h.Buckets[i]
+		if seen > rank {
+			v := bucketValue(i)
+			return min(max(v, h.Min), h.Max)
+		}
+	}
+	return h.Max
+}
+
+// Mean of all samples, 0 if empty
+func (h *Histogram) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return float64(h.Sum) / float64(h.Count)
+}
+
+func bucketIndex(v int64) int {
+	if v <= 0 {
+		return 0
+	}
+	return int(math.Ceil(math.Log(float64(v))/logGamma)) + 1
+}
+
+// bucketValue is the value reported for samples of bucket i, it is at most ~1% away from any of them
+func bucketValue(i int) int64 {
+	if i <= 0 {
+		return 0
+	}
+	upper := math.Pow(histogramGamma, float64(i-1))
+	return int64(math.Round(2 * upper / (histogramGamma + 1)))
+}
@@ -0,0 +1,166 @@
+package rollup
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// exactQuantile is the sample at rank ceil(q*n) of sorted values, the rank Quantile uses
+func exactQuantile(sorted []int64, q float64) int64 {
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// withinBucketError reports whether got is within ~1% bucket error of want, plus rounding to whole ms
+func withinBucketError(got, want int64) bool {
+	return math.Abs(float64(got-want)) <= 0.01*float64(want)+1
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	distributions := []struct {
+		name   string
+		sample func(rng *rand.Rand) int64
+	}{
+		{"uniform 1..10000", func(rng *rand.Rand) int64 { return rng.Int63n(10000) + 1 }},
+		{"exponential mean 200", func(rng *rand.Rand) int64 { return int64(rng.ExpFloat64() * 200) }},
+		{"lognormal long tail", func(rng *rand.Rand) int64 { return int64(math.Exp(5 + rng.NormFloat64())) }},
+		{"bimodal cache hit / miss", func(rng *rand.Rand) int64 {
+			if rng.Intn(10) < 9 {
+				return 5 + rng.Int63n(5)
+			}
+			return 800 + rng.Int63n(400)
+		}},
+	}
+
+	for _, d := range distributions {
+		t.Run(d.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			h := NewHistogram()
+			values := make([]int64, 20000)
+			var sum int64
+			for i := range values {
+				values[i] = d.sample(rng)
+				sum += values[i]
+				h.AddN(values[i], 1)
+			}
+			sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+			for _, q := range []float64{0.5, 0.95, 0.99} {
+				want := exactQuantile(values, q)
+				if got := h.Quantile(q); !withinBucketError(got, want) {
+					t.Errorf("p%v = %d, want %d within 1%%", q*100, got, want)
+				}
+			}
+
+			if h.Count != int64(len(values)) || h.Sum != sum {
+				t.Errorf("count, sum = %d, %d, want %d, %d", h.Count, h.Sum, len(values), sum)
+			}
+			if h.Min != values[0] || h.Max != values[len(values)-1] {
+				t.Errorf("min, max = %d, %d, want %d, %d", h.Min, h.Max, values[0], values[len(values)-1])
+			}
+			// quantiles are clamped to what was seen
+			if got := h.Quantile(0); got < h.Min || !withinBucketError(got, h.Min) {
+				t.Errorf("p0 = %d, want ~min %d", got, h.Min)
+			}
+			if got := h.Quantile(1); got > h.Max || !withinBucketError(got, h.Max) {
+				t.Errorf("p100 = %d, want ~max %d", got, h.Max)
+			}
+		})
+	}
+}
+
+func TestHistogramSingleValue(t *testing.T) {
+	for _, v := range []int64{0, 1, 7, 123, 45678} {
+		h := NewHistogram()
+		h.AddN(v, 50)
+		for _, q := range []float64{0, 0.5, 0.99, 1} {
+			if got := h.Quantile(q); got != v {
+				t.Errorf("value %d: quantile %v = %d, want exact %d", v, q, got, v)
+			}
+		}
+		if h.Mean() != float64(v) {
+			t.Errorf("value %d: mean = %v", v, h.Mean())
+		}
+	}
+}
+
+func TestHistogramEmpty(t *testing.T) {
+	h := NewHistogram()
+	for _, q := range []float64{0, 0.5, 0.99, 1} {
+		if got := h.Quantile(q); got != 0 {
+			t.Errorf("quantile %v of empty histogram = %d, want 0", q, got)
+		}
+	}
+	if h.Mean() != 0 {
+		t.Errorf("mean of empty histogram = %v, want 0", h.Mean())
+	}
+
+	// samples with no count are not recorded
+	h.AddN(100, 0)
+	h.AddN(100, -1)
+	if h.Count != 0 || len(h.Buckets) != 0 {
+		t.Errorf("AddN with n <= 0 recorded samples: %+v", h)
+	}
+}
+
+func TestHistogramNegativeIsZero(t *testing.T) {
+	h := NewHistogram()
+	h.AddN(-5, 2)
+	if h.Min != 0 || h.Max != 0 || h.Sum != 0 || h.Buckets[0] != 2 {
+		t.Errorf("negative samples not recorded as zero: %+v", h)
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	// minute histograms merged into an hour one equal the hour built from all samples
+	all := NewHistogram()
+	hour := NewHistogram()
+	for range 60 {
+		minute := NewHistogram()
+		for range 100 {
+			v := int64(math.Exp(4 + rng.NormFloat64()))
+			minute.AddN(v, 1)
+			all.AddN(v, 1)
+		}
+		hour.Merge(minute)
+	}
+	if !reflect.DeepEqual(hour, all) {
+		t.Fatalf("merged histogram differs from one built from all samples")
+	}
+	for _, q := range []float64{0.5, 0.95, 0.99} {
+		if hour.Quantile(q) != all.Quantile(q) {
+			t.Errorf("p%v of merged = %d, want %d", q*100, hour.Quantile(q), all.Quantile(q))
+		}
+	}
+}
+
+func TestHistogramMergeEmpty(t *testing.T) {
+	h := NewHistogram()
+	h.AddN(50, 3)
+	h.AddN(200, 1)
+	before := *h
+	before.Buckets = map[int]int64{}
+	for i, n := range h.Buckets {
+		before.Buckets[i] = n
+	}
+
+	h.Merge(NewHistogram())
+	if !reflect.DeepEqual(*h, before) {
+		t.Errorf("merging empty histogram changed it: %+v, want %+v", h, before)
+	}
+
+	// merging into an empty one takes min and max of other, not the zero values
+	empty := NewHistogram()
+	empty.Merge(h)
+	if empty.Min != 50 || empty.Max != 200 || empty.Count != 4 || empty.Sum != 350 {
+		t.Errorf("merge into empty = %+v, want min 50 max 200 count 4 sum 350", empty)
+	}
+}
@@ -0,0 +1,164 @@
+package rollup
+
+import (
+	"context"
+	"encoding/json"
+	"project-k/pkg/apperror"
+	"project-k/pkg/db"
+	"project-k/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+// max buckets upserted by one statement
+const upsertBatchSize = 1000
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// RawLatencies folds latencies of check results in [from, to) into minute buckets,
+// checks which failed before a response (timeouts, refused) are left out
+func (r *Repository) RawLatencies(ctx context.Context, from, to time.Time) (map[bucketKey]*Histogram, error) {
+	const op string = "repo.rollup.raw_latencies"
+
+	rows, err := r.querier.ListLatencyCounts(ctx, db.ListLatencyCountsParams{
+		RangeStart: utils.ToPgTimestamptz(from),
+		RangeEnd:   utils.ToPgTimestamptz(to),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, false, r.logger)
+	}
+
+	buckets := make(map[bucketKey]*Histogram)
+	for i := range rows {
+		key := bucketKey{
+			monitorID: utils.FromPgUUID(rows[i].MonitorID),
+			start:     utils.FromPgTimestamptz(rows[i].BucketStart).UTC(),
+		}
+		h, ok := buckets[key]
+		if !ok {
+			h = NewHistogram()
+			buckets[key] = h
+		}
+		h.AddN(int64(rows[i].LatencyMs), rows[i].Samples)
+	}
+	return buckets, nil
+}
+
+// Rollups returns buckets of resolution in [from, to), of all monitors, with their histograms
+func (r *Repository) Rollups(ctx context.Context, res string, from, to time.Time) ([]Bucket, error) {
+	const op string = "repo.rollup.rollups"
+
+	rows, err := r.querier.ListLatencyRollups(ctx, db.ListLatencyRollupsParams{
+		Resolution: res,
+		RangeStart: utils.ToPgTimestamptz(from),
+		RangeEnd:   utils.ToPgTimestamptz(to),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, false, r.logger)
+	}
+
+	buckets := make([]Bucket, 0, len(rows))
+	for i := range rows {
+		b := toBucket(&rows[i])
+		if err := json.Unmarshal(rows[i].Histogram, &b.Histogram.Buckets); err != nil {
+			return nil, apperror.New(apperror.Internal, op, err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
+
+// MonitorBuckets returns buckets of a monitor in [from, to), oldest first, histograms are not decoded
+func (r *Repository) MonitorBuckets(ctx context.Context, monitorID uuid.UUID, res string, from, to time.Time) ([]Bucket, error) {
+	const op string = "repo.rollup.monitor_buckets"
+
+	rows, err := r.querier.ListMonitorLatencyRollups(ctx, db.ListMonitorLatencyRollupsParams{
+		MonitorID:  utils.ToPgUUID(monitorID),
+		Resolution: res,
+		RangeStart: utils.ToPgTimestamptz(from),
+		RangeEnd:   utils.ToPgTimestamptz(to),
+	})
+	if err != nil {
+		return []Bucket{}, utils.WrapRepoError(op, err, false, r.logger)
+	}
+
+	buckets := make([]Bucket, 0, len(rows))
+	for i := range rows {
+		buckets = append(buckets, toBucket(&rows[i]))
+	}
+	return buckets, nil
+}
+
+// Upsert writes buckets of resolution, a bucket rolled up again (ex: partial hour) is overwritten
+func (r *Repository) Upsert(ctx context.Context, res string, buckets []Bucket) error {
+	const op string = "repo.rollup.upsert"
+
+	for start := 0; start < len(buckets); start += upsertBatchSize {
+		batch := buckets[start:min(start+upsertBatchSize, len(buckets))]
+
+		arg := db.UpsertLatencyRollupsParams{
+			Resolution:   res,
+			MonitorIds:   make([]pgtype.UUID, 0, len(batch)),
+			BucketStarts: make([]pgtype.Timestamptz, 0, len(batch)),
+			Counts:       make([]int64, 0, len(batch)),
+			MinMs:        make([]int32, 0, len(batch)),
+			MaxMs:        make([]int32, 0, len(batch)),
+			SumMs:        make([]int64, 0, len(batch)),
+			P50Ms:        make([]int32, 0, len(batch)),
+			P95Ms:        make([]int32, 0, len(batch)),
+			P99Ms:        make([]int32, 0, len(batch)),
+			Histograms:   make([][]byte, 0, len(batch)),
+		}
+		for i := range batch {
+			b := &batch[i]
+			hist, err := json.Marshal(b.Histogram.Buckets)
+			if err != nil {
+				return apperror.New(apperror.Internal, op, err)
+			}
+			arg.MonitorIds = append(arg.MonitorIds, utils.ToPgUUID(b.MonitorID))
+			arg.BucketStarts = append(arg.BucketStarts, utils.ToPgTimestamptz(b.Start))
+			arg.Counts = append(arg.Counts, b.Histogram.Count)
+			arg.MinMs = append(arg.MinMs, int32(b.Histogram.Min))
+			arg.MaxMs = append(arg.MaxMs, int32(b.Histogram.Max))
+			arg.SumMs = append(arg.SumMs, b.Histogram.Sum)
+			arg.P50Ms = append(arg.P50Ms, int32(b.P50Ms))
+			arg.P95Ms = append(arg.P95Ms, int32(b.P95Ms))
+			arg.P99Ms = append(arg.P99Ms, int32(b.P99Ms))
+			arg.Histograms = append(arg.Histograms, hist)
+		}
+
+		if err := r.querier.UpsertLatencyRollups(ctx, arg); err != nil {
+			return utils.WrapRepoError(op, err, false, r.logger)
+		}
+	}
+	return nil
+}
+
+func toBucket(row *db.LatencyRollup) Bucket {
+	return Bucket{
+		MonitorID: utils.FromPgUUID(row.MonitorID),
+		Start:     utils.FromPgTimestamptz(row.BucketStart).UTC(),
+		Histogram: &Histogram{
+			Count: row.Count,
+			Min:   int64(row.MinMs),
+			Max:   int64(row.MaxMs),
+			Sum:   row.SumMs,
+		},
+		P50Ms: int64(row.P50Ms),
+		P95Ms: int64(row.P95Ms),
+		P99Ms: int64(row.P99Ms),
+	}
+}
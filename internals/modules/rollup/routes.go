@@ -0,0 +1,20 @@
+package rollup
+
+import "github.com/go-chi/chi/v5"
+
+// Routes are mounted under /monitors/{monitorID}/latency
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.LatencySeries)
+
+	return r
+}
+
+/*
+- GET: /monitors/{monitorID}/latency?resolution=minute|hour|day&from={RFC3339}&to={RFC3339}
+		-> latency buckets (count, min, max, mean, p50, p95, p99) of a monitor, for charts
+	req auth : true
+	body : nil
+	resp : LatencySeriesResponse
+*/
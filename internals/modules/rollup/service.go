@@ -0,0 +1,34 @@
+package rollup
+
+import (
+	"context"
+	"project-k/internals/modules/monitor"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MonitorOwner returns NotFound if user does not own the monitor
+type MonitorOwner interface {
+	GetMonitor(context.Context, uuid.UUID, uuid.UUID) (monitor.Monitor, error)
+}
+
+type Service struct {
+	repo       *Repository
+	monitorSvc MonitorOwner
+}
+
+func NewService(repo *Repository, monitorSvc MonitorOwner) *Service {
+	return &Service{
+		repo:       repo,
+		monitorSvc: monitorSvc,
+	}
+}
+
+// LatencySeries returns latency buckets of a monitor in [from, to), oldest first
+func (s *Service) LatencySeries(ctx context.Context, userID, monitorID uuid.UUID, res string, from, to time.Time) ([]Bucket, error) {
+	if _, err := s.monitorSvc.GetMonitor(ctx, userID, monitorID); err != nil {
+		return []Bucket{}, err
+	}
+	return s.repo.MonitorBuckets(ctx, monitorID, res, from, to)
+}
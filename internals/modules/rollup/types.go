@@ -0,0 +1,49 @@
+package rollup
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// resolutions of rollup buckets
+const (
+	ResolutionMinute = "minute"
+	ResolutionHour   = "hour"
+	ResolutionDay    = "day"
+)
+
+// resolution is a bucket size, and where its buckets are rolled up from
+type resolution struct {
+	name string
+	size time.Duration
+	// finer resolution its buckets are merged from, raw check results if empty
+	source string
+	// max time rolled up in one tick, bounds memory and query time while catching up
+	chunk time.Duration
+	// how far back first run (or a run after a long stop) starts from
+	lookback time.Duration
+}
+
+// in order, a resolution is rolled up only to the watermark of its source
+var resolutions = []resolution{
+	{name: ResolutionMinute, size: time.Minute, chunk: 10 * time.Minute, lookback: 6 * time.Hour},
+	{name: ResolutionHour, size: time.Hour, source: ResolutionMinute, chunk: 2 * time.Hour, lookback: 2 * 24 * time.Hour},
+	{name: ResolutionDay, size: 24 * time.Hour, source: ResolutionHour, chunk: 2 * 24 * time.Hour, lookback: 7 * 24 * time.Hour},
+}
+
+// Bucket is latency of a monitor's checks in [Start, Start + resolution)
+type Bucket struct {
+	MonitorID uuid.UUID
+	Start     time.Time
+	Histogram *Histogram
+	P50Ms     int64
+	P95Ms     int64
+	P99Ms     int64
+}
+
+// bucketKey identifies a bucket while rolling up
+type bucketKey struct {
+	monitorID uuid.UUID
+	start     time.Time
+}
@@ -0,0 +1,72 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"project-k/config"
+	"strings"
+)
+
+// sealed values are "v1." + base64(nonce | ciphertext), the version leaves room for key rotation
+const sealedPrefix = "v1."
+
+var ErrSealed = errors.New("sealed value is malformed or was sealed with another key")
+
+// Cipher seals secrets stored at rest (monitor credentials) with AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func NewCipher(authCfg *config.AuthConfig) (*Cipher, error) {
+	key, err := base64.StdEncoding.DecodeString(authCfg.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("auth.encryption_key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("auth.encryption_key: want 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// IsSealed reports whether s is a sealed value, rather than plaintext
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, sealedPrefix)
+}
+
+func (c *Cipher) Seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Open(sealed string) ([]byte, error) {
+	raw, ok := strings.CutPrefix(sealed, sealedPrefix)
+	if !ok {
+		return nil, ErrSealed
+	}
+	data, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return nil, ErrSealed
+	}
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrSealed
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,84 @@
+package security
+
+import (
+	"bytes"
+	"encoding/base64"
+	"project-k/config"
+	"strings"
+	"testing"
+)
+
+func newTestCipher(t *testing.T, seed byte) *Cipher {
+	t.Helper()
+	key := bytes.Repeat([]byte{seed}, 32)
+	c, err := NewCipher(&config.AuthConfig{EncryptionKey: base64.StdEncoding.EncodeToString(key)})
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+	return c
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t, 1)
+	plain := []byte(`{"type":"bearer","token":"s3cret"}`)
+
+	sealed, err := c.Seal(plain)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !strings.HasPrefix(sealed, sealedPrefix) || strings.Contains(sealed, "s3cret") {
+		t.Fatalf("sealed value %q leaks plaintext or lacks prefix", sealed)
+	}
+	got, err := c.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Open = %q, want %q", got, plain)
+	}
+
+	// fresh nonce per seal
+	again, _ := c.Seal(plain)
+	if again == sealed {
+		t.Error("two seals of same plaintext are identical")
+	}
+}
+
+func TestCipherOpenRejects(t *testing.T) {
+	c := newTestCipher(t, 1)
+	sealed, _ := c.Seal([]byte("secret"))
+
+	tests := map[string]string{
+		"other key":  mustSeal(t, newTestCipher(t, 2), "secret"),
+		"no prefix":  strings.TrimPrefix(sealed, sealedPrefix),
+		"bad base64": sealedPrefix + "!!!",
+		"too short":  sealedPrefix + base64.StdEncoding.EncodeToString([]byte("abc")),
+		"tampered":   sealed[:len(sealed)-2] + "AA",
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.Open(in); err != ErrSealed {
+				t.Errorf("Open err = %v, want ErrSealed", err)
+			}
+		})
+	}
+}
+
+func TestNewCipherKeyLength(t *testing.T) {
+	short := base64.StdEncoding.EncodeToString(make([]byte, 16))
+	if _, err := NewCipher(&config.AuthConfig{EncryptionKey: short}); err == nil {
+		t.Error("16 byte key accepted")
+	}
+	if _, err := NewCipher(&config.AuthConfig{EncryptionKey: "not base64!"}); err == nil {
+		t.Error("non base64 key accepted")
+	}
+}
+
+func mustSeal(t *testing.T, c *Cipher, s string) string {
+	t.Helper()
+	sealed, err := c.Seal([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sealed
+}
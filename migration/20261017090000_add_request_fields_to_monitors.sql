@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors
+    ADD COLUMN method TEXT NOT NULL DEFAULT 'GET' CHECK (method IN ('GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE', 'OPTIONS')),
+    ADD COLUMN headers JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN body TEXT CHECK (length(body) <= 65536);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS body,
+    DROP COLUMN IF EXISTS headers,
+    DROP COLUMN IF EXISTS method;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- expected_status becomes a status spec, ex: "200,204,3xx,400-404"
+ALTER TABLE monitors
+    DROP CONSTRAINT IF EXISTS monitors_expected_status_check;
+
+ALTER TABLE monitors
+    ALTER COLUMN expected_status TYPE TEXT USING expected_status::text;
+
+ALTER TABLE monitors
+    ADD CONSTRAINT monitors_expected_status_check
+    CHECK (expected_status <> '' AND length(expected_status) <= 256);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors
+    DROP CONSTRAINT IF EXISTS monitors_expected_status_check;
+
+-- keep the first exact code of spec, specs without one fall back to 200
+ALTER TABLE monitors
+    ALTER COLUMN expected_status TYPE INT
+    USING COALESCE(substring(expected_status FROM '(?:^|,)\s*([1-5][0-9]{2})\s*(?:,|$)')::int, 200);
+
+ALTER TABLE monitors
+    ADD CONSTRAINT monitors_expected_status_check
+    CHECK (expected_status BETWEEN 100 AND 599);
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- type selects the checker (http, tcp, ...), options holds settings of non HTTP types
+ALTER TABLE monitors
+    ADD COLUMN type TEXT NOT NULL DEFAULT 'http',
+    ADD COLUMN options JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- expected status is only required for http monitors
+ALTER TABLE monitors
+    DROP CONSTRAINT IF EXISTS monitors_expected_status_check;
+
+ALTER TABLE monitors
+    ADD CONSTRAINT monitors_expected_status_check
+    CHECK (length(expected_status) <= 256 AND (type <> 'http' OR expected_status <> ''));
+
+-- checks failing before a response (tcp, dns, timeout, refused) have no status, they are stored as 0
+ALTER TABLE monitor_incidents
+    DROP CONSTRAINT IF EXISTS monitor_incidents_http_status_check,
+    ADD CONSTRAINT monitor_incidents_http_status_check CHECK (http_status = 0 OR http_status BETWEEN 100 AND 599);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- incidents without a status can not be kept under the old check, and are history, so refuse instead of deleting them
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM monitor_incidents WHERE http_status = 0) THEN
+        RAISE EXCEPTION 'monitor_incidents has incidents without http status (http_status = 0), move or delete them before rolling back';
+    END IF;
+END $$;
+
+ALTER TABLE monitor_incidents
+    DROP CONSTRAINT IF EXISTS monitor_incidents_http_status_check,
+    ADD CONSTRAINT monitor_incidents_http_status_check CHECK (http_status BETWEEN 100 AND 599);
+
+ALTER TABLE monitors
+    DROP CONSTRAINT IF EXISTS monitors_expected_status_check;
+
+UPDATE monitors SET expected_status = '200' WHERE expected_status = '';
+
+ALTER TABLE monitors
+    ADD CONSTRAINT monitors_expected_status_check
+    CHECK (expected_status <> '' AND length(expected_status) <= 256);
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS options,
+    DROP COLUMN IF EXISTS type;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- heartbeat monitors are pinged by jobs on /heartbeat/{token}, other types have no token
+ALTER TABLE monitors
+    ADD COLUMN heartbeat_token TEXT UNIQUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS heartbeat_token;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- credentials of authenticated checks (basic, bearer, mtls), kept apart from options as it holds secrets
+ALTER TABLE monitors
+    ADD COLUMN auth JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS auth;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- response (or error) of the check which opened the incident
+ALTER TABLE monitor_incidents
+    ADD COLUMN evidence JSONB;
+
+CREATE INDEX idx_monitor_incidents_monitor_id_start_time
+ON monitor_incidents (monitor_id, start_time DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_monitor_incidents_monitor_id_start_time;
+
+ALTER TABLE monitor_incidents
+    DROP COLUMN IF EXISTS evidence;
+-- +goose StatementEnd
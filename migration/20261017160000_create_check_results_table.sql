@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- outcome of every check, written in batches by result processor
+CREATE TABLE IF NOT EXISTS check_results (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    monitor_id UUID NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    checked_at TIMESTAMPTZ NOT NULL,
+    success BOOLEAN NOT NULL,
+    http_status INT NOT NULL DEFAULT 0,
+    latency_ms INT NOT NULL CHECK (latency_ms >= 0),
+    reason TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_check_results_monitor_id_checked_at
+ON check_results (monitor_id, checked_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS check_results;
+-- +goose StatementEnd
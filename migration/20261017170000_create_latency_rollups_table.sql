@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- latency of checks rolled up into minute, hour and day buckets, for charts over long windows
+CREATE TABLE IF NOT EXISTS latency_rollups (
+    monitor_id UUID NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    resolution TEXT NOT NULL CHECK (resolution IN ('minute', 'hour', 'day')),
+    bucket_start TIMESTAMPTZ NOT NULL,
+    count BIGINT NOT NULL CHECK (count > 0),
+    min_ms INT NOT NULL,
+    max_ms INT NOT NULL,
+    sum_ms BIGINT NOT NULL,
+    p50_ms INT NOT NULL,
+    p95_ms INT NOT NULL,
+    p99_ms INT NOT NULL,
+    -- log bucketed histogram of the latencies, so buckets can be merged into bigger ones
+    histogram JSONB NOT NULL,
+    PRIMARY KEY (monitor_id, resolution, bucket_start)
+);
+
+CREATE INDEX idx_latency_rollups_resolution_bucket_start
+ON latency_rollups (resolution, bucket_start);
+
+-- rollup job reads raw results by time only, BRIN is tiny for append only data
+CREATE INDEX idx_check_results_checked_at
+ON check_results USING BRIN (checked_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_check_results_checked_at;
+
+DROP TABLE IF EXISTS latency_rollups;
+-- +goose StatementEnd
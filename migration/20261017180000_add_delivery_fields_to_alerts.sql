@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- alerts not tied to an incident (cert expiry) have only a monitor
+ALTER TABLE alerts
+    ADD COLUMN monitor_id UUID REFERENCES monitors(id) ON DELETE CASCADE,
+    ADD COLUMN alert_type TEXT NOT NULL DEFAULT 'DOWN',
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT,
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ALTER COLUMN incident_id DROP NOT NULL;
+
+UPDATE alerts a
+SET monitor_id = i.monitor_id
+FROM monitor_incidents i
+WHERE i.id = a.incident_id;
+
+ALTER TABLE alerts
+    ALTER COLUMN monitor_id SET NOT NULL,
+    ADD CONSTRAINT alerts_status_check CHECK (status IN ('pending', 'sent', 'failed'));
+
+CREATE INDEX idx_alerts_monitor_id_created_at
+ON alerts (monitor_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_alerts_monitor_id_created_at;
+
+DELETE FROM alerts WHERE incident_id IS NULL;
+
+ALTER TABLE alerts
+    DROP CONSTRAINT IF EXISTS alerts_status_check,
+    ALTER COLUMN incident_id SET NOT NULL,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS alert_type,
+    DROP COLUMN IF EXISTS monitor_id;
+-- +goose StatementEnd
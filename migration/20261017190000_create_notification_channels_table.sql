@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('email', 'slack', 'discord', 'teams', 'webhook')),
+    config JSONB NOT NULL,                      -- address or webhook url of channel, secret
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notification_channels_user_id
+ON notification_channels (user_id, created_at);
+
+-- channels an alert of a monitor fans out to
+CREATE TABLE IF NOT EXISTS monitor_notification_channels (
+    monitor_id UUID NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    channel_id UUID NOT NULL REFERENCES notification_channels(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (monitor_id, channel_id)
+);
+
+CREATE INDEX idx_monitor_notification_channels_channel_id
+ON monitor_notification_channels (channel_id);
+
+-- an alert row is one delivery, to a channel or to alert_email of monitor (channel_id NULL)
+ALTER TABLE alerts
+    ADD COLUMN channel_id UUID REFERENCES notification_channels(id) ON DELETE SET NULL,
+    ADD COLUMN channel_type TEXT NOT NULL DEFAULT 'email',
+    ALTER COLUMN alert_email DROP NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM alerts WHERE alert_email IS NULL;
+
+ALTER TABLE alerts
+    ALTER COLUMN alert_email SET NOT NULL,
+    DROP COLUMN IF EXISTS channel_type,
+    DROP COLUMN IF EXISTS channel_id;
+
+DROP TABLE IF EXISTS monitor_notification_channels;
+DROP TABLE IF EXISTS notification_channels;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- notification as sent, so a delivery can be sent again
+ALTER TABLE alerts
+    ADD COLUMN payload JSONB;
+
+CREATE INDEX idx_alerts_channel_id_created_at
+ON alerts (channel_id, created_at DESC);
+
+-- every attempt of a delivery
+CREATE TABLE IF NOT EXISTS alert_attempts (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    alert_id UUID NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INT,                            -- NULL if there was no http response (email, network error)
+    latency_ms INT NOT NULL,
+    response_excerpt TEXT,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_alert_attempts_alert_id
+ON alert_attempts (alert_id, attempt);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS alert_attempts;
+
+DROP INDEX IF EXISTS idx_alerts_channel_id_created_at;
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS payload;
+-- +goose StatementEnd
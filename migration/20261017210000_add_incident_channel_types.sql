@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- pagerduty (events api v2) and opsgenie channels, they open and resolve incidents
+ALTER TABLE notification_channels
+    DROP CONSTRAINT IF EXISTS notification_channels_type_check;
+
+ALTER TABLE notification_channels
+    ADD CONSTRAINT notification_channels_type_check
+    CHECK (type IN ('email', 'slack', 'discord', 'teams', 'webhook', 'pagerduty', 'opsgenie'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notification_channels WHERE type IN ('pagerduty', 'opsgenie');
+
+ALTER TABLE notification_channels
+    DROP CONSTRAINT IF EXISTS notification_channels_type_check;
+
+ALTER TABLE notification_channels
+    ADD CONSTRAINT notification_channels_type_check
+    CHECK (type IN ('email', 'slack', 'discord', 'teams', 'webhook'));
+-- +goose StatementEnd
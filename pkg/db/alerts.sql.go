@@ -11,36 +11,227 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createAlert = `-- name: CreateAlert :exec
+const createAlert = `-- name: CreateAlert :one
 INSERT INTO
-alerts (incident_id, alert_email) 
-VALUES ($1, $2)
+alerts (incident_id, monitor_id, alert_type, alert_email, channel_id, channel_type, payload) 
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id
 `
 
 type CreateAlertParams struct {
-	IncidentID pgtype.UUID
-	AlertEmail string
+	IncidentID  pgtype.UUID
+	MonitorID   pgtype.UUID
+	AlertType   string
+	AlertEmail  pgtype.Text
+	ChannelID   pgtype.UUID
+	ChannelType string
+	Payload     []byte
 }
 
-func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) error {
-	_, err := q.db.Exec(ctx, createAlert, arg.IncidentID, arg.AlertEmail)
+func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, createAlert,
+		arg.IncidentID,
+		arg.MonitorID,
+		arg.AlertType,
+		arg.AlertEmail,
+		arg.ChannelID,
+		arg.ChannelType,
+		arg.Payload,
+	)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createAlertAttempt = `-- name: CreateAlertAttempt :exec
+INSERT INTO alert_attempts (alert_id, attempt, success, status_code, latency_ms, response_excerpt, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateAlertAttemptParams struct {
+	AlertID         pgtype.UUID
+	Attempt         int32
+	Success         bool
+	StatusCode      pgtype.Int4
+	LatencyMs       int32
+	ResponseExcerpt pgtype.Text
+	Error           pgtype.Text
+}
+
+func (q *Queries) CreateAlertAttempt(ctx context.Context, arg CreateAlertAttemptParams) error {
+	_, err := q.db.Exec(ctx, createAlertAttempt,
+		arg.AlertID,
+		arg.Attempt,
+		arg.Success,
+		arg.StatusCode,
+		arg.LatencyMs,
+		arg.ResponseExcerpt,
+		arg.Error,
+	)
 	return err
 }
 
+const getChannelAlert = `-- name: GetChannelAlert :one
+SELECT a.id, a.incident_id, a.sent_at, a.alert_email, a.status, a.created_at, a.monitor_id, a.alert_type, a.attempts, a.last_error, a.updated_at, a.channel_id, a.channel_type, a.payload
+FROM alerts a
+JOIN notification_channels c ON c.id = a.channel_id
+WHERE a.id = $1 AND a.channel_id = $2 AND c.user_id = $3
+`
+
+type GetChannelAlertParams struct {
+	ID        pgtype.UUID
+	ChannelID pgtype.UUID
+	UserID    pgtype.UUID
+}
+
+func (q *Queries) GetChannelAlert(ctx context.Context, arg GetChannelAlertParams) (Alert, error) {
+	row := q.db.QueryRow(ctx, getChannelAlert, arg.ID, arg.ChannelID, arg.UserID)
+	var i Alert
+	err := row.Scan(
+		&i.ID,
+		&i.IncidentID,
+		&i.SentAt,
+		&i.AlertEmail,
+		&i.Status,
+		&i.CreatedAt,
+		&i.MonitorID,
+		&i.AlertType,
+		&i.Attempts,
+		&i.LastError,
+		&i.UpdatedAt,
+		&i.ChannelID,
+		&i.ChannelType,
+		&i.Payload,
+	)
+	return i, err
+}
+
+const listAlertAttempts = `-- name: ListAlertAttempts :many
+SELECT id, alert_id, attempt, success, status_code, latency_ms, response_excerpt, error, created_at
+FROM alert_attempts
+WHERE alert_id = ANY($1::uuid[])
+ORDER BY alert_id, attempt
+`
+
+func (q *Queries) ListAlertAttempts(ctx context.Context, alertIds []pgtype.UUID) ([]AlertAttempt, error) {
+	rows, err := q.db.Query(ctx, listAlertAttempts, alertIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AlertAttempt
+	for rows.Next() {
+		var i AlertAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlertID,
+			&i.Attempt,
+			&i.Success,
+			&i.StatusCode,
+			&i.LatencyMs,
+			&i.ResponseExcerpt,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChannelAlerts = `-- name: ListChannelAlerts :many
+SELECT id, incident_id, sent_at, alert_email, status, created_at, monitor_id, alert_type, attempts, last_error, updated_at, channel_id, channel_type, payload
+FROM alerts
+WHERE channel_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListChannelAlertsParams struct {
+	ChannelID pgtype.UUID
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListChannelAlerts(ctx context.Context, arg ListChannelAlertsParams) ([]Alert, error) {
+	rows, err := q.db.Query(ctx, listChannelAlerts, arg.ChannelID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Alert
+	for rows.Next() {
+		var i Alert
+		if err := rows.Scan(
+			&i.ID,
+			&i.IncidentID,
+			&i.SentAt,
+			&i.AlertEmail,
+			&i.Status,
+			&i.CreatedAt,
+			&i.MonitorID,
+			&i.AlertType,
+			&i.Attempts,
+			&i.LastError,
+			&i.UpdatedAt,
+			&i.ChannelID,
+			&i.ChannelType,
+			&i.Payload,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAlertPending = `-- name: MarkAlertPending :execrows
+UPDATE alerts
+SET status = 'pending', updated_at = now()
+WHERE id = $1 AND (status <> 'pending' OR updated_at < now() - interval '10 minutes')
+`
+
+// a pending alert is being delivered, unless it was left pending (crash) long ago
+func (q *Queries) MarkAlertPending(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markAlertPending, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateAlertStatus = `-- name: UpdateAlertStatus :execrows
 UPDATE alerts
 SET
-    status = $2
+    status = $2,
+    attempts = $3,
+    last_error = $4,
+    sent_at = CASE WHEN $2 = 'sent' THEN now() ELSE sent_at END,
+    updated_at = now()
 WHERE id = $1
 `
 
 type UpdateAlertStatusParams struct {
-	ID     pgtype.UUID
-	Status string
+	ID        pgtype.UUID
+	Status    string
+	Attempts  int32
+	LastError pgtype.Text
 }
 
 func (q *Queries) UpdateAlertStatus(ctx context.Context, arg UpdateAlertStatusParams) (int64, error) {
-	result, err := q.db.Exec(ctx, updateAlertStatus, arg.ID, arg.Status)
+	result, err := q.db.Exec(ctx, updateAlertStatus,
+		arg.ID,
+		arg.Status,
+		arg.Attempts,
+		arg.LastError,
+	)
 	if err != nil {
 		return 0, err
 	}
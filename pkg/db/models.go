@@ -9,12 +9,57 @@ import (
 )
 
 type Alert struct {
-	ID         pgtype.UUID
-	IncidentID pgtype.UUID
-	SentAt     pgtype.Timestamptz
-	AlertEmail string
-	Status     string
-	CreatedAt  pgtype.Timestamptz
+	ID          pgtype.UUID
+	IncidentID  pgtype.UUID
+	SentAt      pgtype.Timestamptz
+	AlertEmail  pgtype.Text
+	Status      string
+	CreatedAt   pgtype.Timestamptz
+	MonitorID   pgtype.UUID
+	AlertType   string
+	Attempts    int32
+	LastError   pgtype.Text
+	UpdatedAt   pgtype.Timestamptz
+	ChannelID   pgtype.UUID
+	ChannelType string
+	Payload     []byte
+}
+
+type AlertAttempt struct {
+	ID              int64
+	AlertID         pgtype.UUID
+	Attempt         int32
+	Success         bool
+	StatusCode      pgtype.Int4
+	LatencyMs       int32
+	ResponseExcerpt pgtype.Text
+	Error           pgtype.Text
+	CreatedAt       pgtype.Timestamptz
+}
+
+type CheckResult struct {
+	ID         int64
+	MonitorID  pgtype.UUID
+	CheckedAt  pgtype.Timestamptz
+	Success    bool
+	HttpStatus int32
+	LatencyMs  int32
+	Reason     string
+	Region     string
+}
+
+type LatencyRollup struct {
+	MonitorID   pgtype.UUID
+	Resolution  string
+	BucketStart pgtype.Timestamptz
+	Count       int64
+	MinMs       int32
+	MaxMs       int32
+	SumMs       int64
+	P50Ms       int32
+	P95Ms       int32
+	P99Ms       int32
+	Histogram   []byte
 }
 
 type Monitor struct {
@@ -25,10 +70,18 @@ type Monitor struct {
 	IntervalSec        int32
 	TimeoutSec         int32
 	LatencyThresholdMs int32
-	ExpectedStatus     int32
+	ExpectedStatus     string
 	Enabled            bool
 	UpdatedAt          pgtype.Timestamptz
 	CreatedAt          pgtype.Timestamptz
+	Method             string
+	Headers            []byte
+	Body               pgtype.Text
+	Assertions         []byte
+	Type               string
+	Options            []byte
+	HeartbeatToken     pgtype.Text
+	Auth               []byte
 }
 
 type MonitorIncident struct {
@@ -40,6 +93,24 @@ type MonitorIncident struct {
 	HttpStatus int32
 	LatencyMs  int32
 	CreatedAt  pgtype.Timestamptz
+	Evidence   []byte
+}
+
+type MonitorNotificationChannel struct {
+	MonitorID pgtype.UUID
+	ChannelID pgtype.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+type NotificationChannel struct {
+	ID        pgtype.UUID
+	UserID    pgtype.UUID
+	Name      string
+	Type      string
+	Config    []byte
+	Enabled   bool
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
 }
 
 type User struct {
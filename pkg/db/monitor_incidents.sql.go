@@ -11,10 +11,11 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const closeMonitorIncident = `-- name: CloseMonitorIncident :execrows
+const closeMonitorIncident = `-- name: CloseMonitorIncident :many
 UPDATE monitor_incidents
-SET end_time = $2
+SET end_time = GREATEST(start_time, $2)
 WHERE monitor_id = $1 AND end_time IS NULL
+RETURNING id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence
 `
 
 type CloseMonitorIncidentParams struct {
@@ -22,17 +23,40 @@ type CloseMonitorIncidentParams struct {
 	EndTime   pgtype.Timestamptz
 }
 
-func (q *Queries) CloseMonitorIncident(ctx context.Context, arg CloseMonitorIncidentParams) (int64, error) {
-	result, err := q.db.Exec(ctx, closeMonitorIncident, arg.MonitorID, arg.EndTime)
+func (q *Queries) CloseMonitorIncident(ctx context.Context, arg CloseMonitorIncidentParams) ([]MonitorIncident, error) {
+	rows, err := q.db.Query(ctx, closeMonitorIncident, arg.MonitorID, arg.EndTime)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return result.RowsAffected(), nil
+	defer rows.Close()
+	var items []MonitorIncident
+	for rows.Next() {
+		var i MonitorIncident
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Alerted,
+			&i.HttpStatus,
+			&i.LatencyMs,
+			&i.CreatedAt,
+			&i.Evidence,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const createMonitorIncident = `-- name: CreateMonitorIncident :exec
-INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms)
-VALUES ($1, $2, $3, $4, $5)
+const createMonitorIncident = `-- name: CreateMonitorIncident :one
+INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms, evidence)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id
 `
 
 type CreateMonitorIncidentParams struct {
@@ -41,21 +65,25 @@ type CreateMonitorIncidentParams struct {
 	Alerted    bool
 	HttpStatus int32
 	LatencyMs  int32
+	Evidence   []byte
 }
 
-func (q *Queries) CreateMonitorIncident(ctx context.Context, arg CreateMonitorIncidentParams) error {
-	_, err := q.db.Exec(ctx, createMonitorIncident,
+func (q *Queries) CreateMonitorIncident(ctx context.Context, arg CreateMonitorIncidentParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, createMonitorIncident,
 		arg.MonitorID,
 		arg.StartTime,
 		arg.Alerted,
 		arg.HttpStatus,
 		arg.LatencyMs,
+		arg.Evidence,
 	)
-	return err
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
 }
 
 const getMonitorIncidentByID = `-- name: GetMonitorIncidentByID :one
-SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at
+SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence
 FROM monitor_incidents
 WHERE id = $1
 `
@@ -72,6 +100,142 @@ func (q *Queries) GetMonitorIncidentByID(ctx context.Context, id pgtype.UUID) (M
 		&i.HttpStatus,
 		&i.LatencyMs,
 		&i.CreatedAt,
+		&i.Evidence,
 	)
 	return i, err
 }
+
+const listMonitorIncidents = `-- name: ListMonitorIncidents :many
+SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence
+FROM monitor_incidents
+WHERE monitor_id = $1
+ORDER BY start_time DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListMonitorIncidentsParams struct {
+	MonitorID pgtype.UUID
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListMonitorIncidents(ctx context.Context, arg ListMonitorIncidentsParams) ([]MonitorIncident, error) {
+	rows, err := q.db.Query(ctx, listMonitorIncidents, arg.MonitorID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MonitorIncident
+	for rows.Next() {
+		var i MonitorIncident
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Alerted,
+			&i.HttpStatus,
+			&i.LatencyMs,
+			&i.CreatedAt,
+			&i.Evidence,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMonitorIncidentsInRange = `-- name: ListMonitorIncidentsInRange :many
+SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence
+FROM monitor_incidents
+WHERE monitor_id = $1
+  AND (end_time IS NULL OR end_time > $2)
+  AND start_time < $3
+ORDER BY start_time
+`
+
+type ListMonitorIncidentsInRangeParams struct {
+	MonitorID  pgtype.UUID
+	RangeStart pgtype.Timestamptz
+	RangeEnd   pgtype.Timestamptz
+}
+
+func (q *Queries) ListMonitorIncidentsInRange(ctx context.Context, arg ListMonitorIncidentsInRangeParams) ([]MonitorIncident, error) {
+	rows, err := q.db.Query(ctx, listMonitorIncidentsInRange, arg.MonitorID, arg.RangeStart, arg.RangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MonitorIncident
+	for rows.Next() {
+		var i MonitorIncident
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Alerted,
+			&i.HttpStatus,
+			&i.LatencyMs,
+			&i.CreatedAt,
+			&i.Evidence,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserIncidentsInRange = `-- name: ListUserIncidentsInRange :many
+SELECT i.id, i.monitor_id, i.start_time, i.end_time, i.alerted, i.http_status, i.latency_ms, i.created_at, i.evidence
+FROM monitor_incidents i
+JOIN monitors m ON m.id = i.monitor_id
+WHERE m.user_id = $1
+  AND (i.end_time IS NULL OR i.end_time > $2)
+  AND i.start_time < $3
+ORDER BY i.monitor_id, i.start_time
+`
+
+type ListUserIncidentsInRangeParams struct {
+	UserID     pgtype.UUID
+	RangeStart pgtype.Timestamptz
+	RangeEnd   pgtype.Timestamptz
+}
+
+func (q *Queries) ListUserIncidentsInRange(ctx context.Context, arg ListUserIncidentsInRangeParams) ([]MonitorIncident, error) {
+	rows, err := q.db.Query(ctx, listUserIncidentsInRange, arg.UserID, arg.RangeStart, arg.RangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MonitorIncident
+	for rows.Next() {
+		var i MonitorIncident
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Alerted,
+			&i.HttpStatus,
+			&i.LatencyMs,
+			&i.CreatedAt,
+			&i.Evidence,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -19,7 +19,15 @@ INSERT INTO monitors (
     timeout_sec,
     latency_threshold_ms,
     expected_status,
-    alert_email
+    alert_email,
+    method,
+    headers,
+    body,
+    assertions,
+    type,
+    options,
+    heartbeat_token,
+    auth
 ) VALUES (
     $1,
     $2,
@@ -27,7 +35,15 @@ INSERT INTO monitors (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14,
+    $15
 )
 RETURNING id
 `
@@ -38,8 +54,16 @@ type CreateMonitorParams struct {
 	IntervalSec        int32
 	TimeoutSec         int32
 	LatencyThresholdMs int32
-	ExpectedStatus     int32
+	ExpectedStatus     string
 	AlertEmail         pgtype.Text
+	Method             string
+	Headers            []byte
+	Body               pgtype.Text
+	Assertions         []byte
+	Type               string
+	Options            []byte
+	HeartbeatToken     pgtype.Text
+	Auth               []byte
 }
 
 func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (pgtype.UUID, error) {
@@ -51,6 +75,14 @@ func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (p
 		arg.LatencyThresholdMs,
 		arg.ExpectedStatus,
 		arg.AlertEmail,
+		arg.Method,
+		arg.Headers,
+		arg.Body,
+		arg.Assertions,
+		arg.Type,
+		arg.Options,
+		arg.HeartbeatToken,
+		arg.Auth,
 	)
 	var id pgtype.UUID
 	err := row.Scan(&id)
@@ -58,7 +90,7 @@ func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (p
 }
 
 const getAllMonitorByUserID = `-- name: GetAllMonitorByUserID :many
-SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled, updated_at, created_at
+SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled, updated_at, created_at, method, headers, body, assertions, type, options, heartbeat_token, auth
 FROM monitors
 WHERE user_id = $1
 ORDER BY updated_at
@@ -93,6 +125,14 @@ func (q *Queries) GetAllMonitorByUserID(ctx context.Context, arg GetAllMonitorBy
 			&i.Enabled,
 			&i.UpdatedAt,
 			&i.CreatedAt,
+			&i.Method,
+			&i.Headers,
+			&i.Body,
+			&i.Assertions,
+			&i.Type,
+			&i.Options,
+			&i.HeartbeatToken,
+			&i.Auth,
 		); err != nil {
 			return nil, err
 		}
@@ -105,7 +145,7 @@ func (q *Queries) GetAllMonitorByUserID(ctx context.Context, arg GetAllMonitorBy
 }
 
 const getMonitor = `-- name: GetMonitor :one
-SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled, updated_at, created_at, method, headers, body, assertions, type, options, heartbeat_token, auth
 FROM monitors
 WHERE id = $1 AND user_id = $2
 `
@@ -115,21 +155,42 @@ type GetMonitorParams struct {
 	UserID pgtype.UUID
 }
 
-type GetMonitorRow struct {
-	ID                 pgtype.UUID
-	UserID             pgtype.UUID
-	Url                string
-	AlertEmail         pgtype.Text
-	IntervalSec        int32
-	TimeoutSec         int32
-	LatencyThresholdMs int32
-	ExpectedStatus     int32
-	Enabled            bool
+func (q *Queries) GetMonitor(ctx context.Context, arg GetMonitorParams) (Monitor, error) {
+	row := q.db.QueryRow(ctx, getMonitor, arg.ID, arg.UserID)
+	var i Monitor
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Url,
+		&i.AlertEmail,
+		&i.IntervalSec,
+		&i.TimeoutSec,
+		&i.LatencyThresholdMs,
+		&i.ExpectedStatus,
+		&i.Enabled,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.Method,
+		&i.Headers,
+		&i.Body,
+		&i.Assertions,
+		&i.Type,
+		&i.Options,
+		&i.HeartbeatToken,
+		&i.Auth,
+	)
+	return i, err
 }
 
-func (q *Queries) GetMonitor(ctx context.Context, arg GetMonitorParams) (GetMonitorRow, error) {
-	row := q.db.QueryRow(ctx, getMonitor, arg.ID, arg.UserID)
-	var i GetMonitorRow
+const getMonitorByHeartbeatToken = `-- name: GetMonitorByHeartbeatToken :one
+SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled, updated_at, created_at, method, headers, body, assertions, type, options, heartbeat_token, auth
+FROM monitors
+WHERE heartbeat_token = $1
+`
+
+func (q *Queries) GetMonitorByHeartbeatToken(ctx context.Context, heartbeatToken pgtype.Text) (Monitor, error) {
+	row := q.db.QueryRow(ctx, getMonitorByHeartbeatToken, heartbeatToken)
+	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
@@ -140,31 +201,29 @@ func (q *Queries) GetMonitor(ctx context.Context, arg GetMonitorParams) (GetMoni
 		&i.LatencyThresholdMs,
 		&i.ExpectedStatus,
 		&i.Enabled,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.Method,
+		&i.Headers,
+		&i.Body,
+		&i.Assertions,
+		&i.Type,
+		&i.Options,
+		&i.HeartbeatToken,
+		&i.Auth,
 	)
 	return i, err
 }
 
 const getMonitorByID = `-- name: GetMonitorByID :one
-SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled, updated_at, created_at, method, headers, body, assertions, type, options, heartbeat_token, auth
 FROM monitors
 WHERE id = $1
 `
 
-type GetMonitorByIDRow struct {
-	ID                 pgtype.UUID
-	UserID             pgtype.UUID
-	Url                string
-	AlertEmail         pgtype.Text
-	IntervalSec        int32
-	TimeoutSec         int32
-	LatencyThresholdMs int32
-	ExpectedStatus     int32
-	Enabled            bool
-}
-
-func (q *Queries) GetMonitorByID(ctx context.Context, id pgtype.UUID) (GetMonitorByIDRow, error) {
+func (q *Queries) GetMonitorByID(ctx context.Context, id pgtype.UUID) (Monitor, error) {
 	row := q.db.QueryRow(ctx, getMonitorByID, id)
-	var i GetMonitorByIDRow
+	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
@@ -175,10 +234,67 @@ func (q *Queries) GetMonitorByID(ctx context.Context, id pgtype.UUID) (GetMonito
 		&i.LatencyThresholdMs,
 		&i.ExpectedStatus,
 		&i.Enabled,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+		&i.Method,
+		&i.Headers,
+		&i.Body,
+		&i.Assertions,
+		&i.Type,
+		&i.Options,
+		&i.HeartbeatToken,
+		&i.Auth,
 	)
 	return i, err
 }
 
+const listMonitorsWithPlainAuth = `-- name: ListMonitorsWithPlainAuth :many
+SELECT id, auth
+FROM monitors
+WHERE auth IS NOT NULL AND NOT jsonb_exists(auth, 'sealed')
+`
+
+type ListMonitorsWithPlainAuthRow struct {
+	ID   pgtype.UUID
+	Auth []byte
+}
+
+func (q *Queries) ListMonitorsWithPlainAuth(ctx context.Context) ([]ListMonitorsWithPlainAuthRow, error) {
+	rows, err := q.db.Query(ctx, listMonitorsWithPlainAuth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMonitorsWithPlainAuthRow
+	for rows.Next() {
+		var i ListMonitorsWithPlainAuthRow
+		if err := rows.Scan(&i.ID, &i.Auth); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateMonitorAuth = `-- name: UpdateMonitorAuth :exec
+UPDATE monitors
+SET auth = $2
+WHERE id = $1
+`
+
+type UpdateMonitorAuthParams struct {
+	ID   pgtype.UUID
+	Auth []byte
+}
+
+func (q *Queries) UpdateMonitorAuth(ctx context.Context, arg UpdateMonitorAuthParams) error {
+	_, err := q.db.Exec(ctx, updateMonitorAuth, arg.ID, arg.Auth)
+	return err
+}
+
 const updateMonitorStatus = `-- name: UpdateMonitorStatus :execrows
 UPDATE monitors
 SET enabled = $2
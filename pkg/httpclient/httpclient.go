@@ -6,17 +6,22 @@ import (
 	"time"
 )
 
-func NewHttpClient() *http.Client {
-	dialer := &net.Dialer{
+// NewDialer returns the dialer used by checks, checks needing own dial settings start from it
+func NewDialer() *net.Dialer {
+	return &net.Dialer{
 		Timeout:   5 * time.Second,
 		KeepAlive: 30 * time.Second,
 	}
+}
+
+func NewHttpClient() *http.Client {
+	dialer := NewDialer()
 
 	transport := &http.Transport{
 		DialContext:           dialer.DialContext,
 		TLSHandshakeTimeout:   5 * time.Second,
-		ResponseHeaderTimeout: 10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
+		// no ResponseHeaderTimeout here, each check is bounded by its monitor's own timeout (request context)
 
 		MaxIdleConns:        10000,
 		MaxIdleConnsPerHost: 100,
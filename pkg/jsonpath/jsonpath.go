@@ -0,0 +1,133 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+ A small subset of JSONPath, enough for health check assertions
+	$.status
+	$.data.items[0].name
+	data.items[0].name   ( "$" prefix is optional )
+*/
+
+var ErrInvalidPath = errors.New("invalid json path")
+
+type segment struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// Validate checks the syntax of path
+func Validate(path string) error {
+	_, err := parse(path)
+	return err
+}
+
+// Lookup finds the value at path in a decoded JSON document (decoded with UseNumber),
+// found is false when any part of path does not exist
+func Lookup(doc any, path string) (value any, found bool, err error) {
+	segs, err := parse(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cur := doc
+	for _, s := range segs {
+		if s.isIdx {
+			arr, ok := cur.([]any)
+			if !ok || s.index >= len(arr) {
+				return nil, false, nil
+			}
+			cur = arr[s.index]
+			continue
+		}
+
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false, nil
+		}
+		cur, ok = obj[s.key]
+		if !ok {
+			return nil, false, nil
+		}
+	}
+
+	return cur, true, nil
+}
+
+// Decode decodes body keeping numbers as json.Number, so they can be compared as written
+func Decode(body []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// String renders a looked up value for comparison, strings are returned as it is, everything else as JSON
+func String(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case nil:
+		return "null"
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(b)
+	}
+}
+
+func parse(path string) ([]segment, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimPrefix(p, "$")
+	p = strings.TrimPrefix(p, ".")
+	if p == "" {
+		return nil, nil // root
+	}
+
+	var segs []segment
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			if p == "" || p[0] == '.' || p[0] == '[' {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+			idx, err := strconv.Atoi(p[1:end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+			segs = append(segs, segment{index: idx, isIdx: true})
+			p = p[end+1:]
+		default:
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			segs = append(segs, segment{key: p[:end]})
+			p = p[end:]
+		}
+	}
+
+	return segs, nil
+}
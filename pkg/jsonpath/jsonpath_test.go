@@ -0,0 +1,107 @@
+package jsonpath
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"$", false},
+		{"", false},
+		{"$.status", false},
+		{"status", false},
+		{"$.data.items[0].name", false},
+		{"data.items[12]", false},
+		{"$[0]", false},
+		{"$.a[0][1]", false},
+		{"$.a.", true},
+		{"$.a..b", true},
+		{"$.a.[0]", true},
+		{"$.a[", true},
+		{"$.a[x]", true},
+		{"$.a[-1]", true},
+		{"$.a[]", true},
+	}
+
+	for _, tt := range tests {
+		err := Validate(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, want error %v", tt.path, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("Validate(%q) error = %v, want ErrInvalidPath", tt.path, err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	doc, err := Decode([]byte(`{
+		"status": "ok",
+		"count": 3,
+		"ratio": 1.50,
+		"ready": true,
+		"none": null,
+		"data": {"items": [{"name": "a"}, {"name": "b", "tags": ["x", "y"]}]},
+		"matrix": [[1, 2], [3, 4]]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path      string
+		want      string
+		wantFound bool
+		wantErr   bool
+	}{
+		{path: "$.status", want: "ok", wantFound: true},
+		{path: "status", want: "ok", wantFound: true},
+		{path: "$.count", want: "3", wantFound: true},
+		{path: "$.ratio", want: "1.50", wantFound: true}, // number kept as written
+		{path: "$.ready", want: "true", wantFound: true},
+		{path: "$.none", want: "null", wantFound: true},
+		{path: "$.data.items[0].name", want: "a", wantFound: true},
+		{path: "$.data.items[1].tags[1]", want: "y", wantFound: true},
+		{path: "$.data.items[1].tags", want: `["x","y"]`, wantFound: true},
+		{path: "$.matrix[1][0]", want: "3", wantFound: true},
+		{path: "$.data.items[0]", want: `{"name":"a"}`, wantFound: true},
+		{path: "$.missing"},
+		{path: "$.data.missing.name"},
+		{path: "$.data.items[2].name"}, // index out of range
+		{path: "$.status[0]"},          // index into a string
+		{path: "$.data.items.name"},    // key of an array
+		{path: "$.status.length"},      // key of a string
+		{path: "$.data.items[", wantErr: true},
+		{path: "$.data..items", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		v, found, err := Lookup(doc, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Lookup(%q) error = %v, want error %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if found != tt.wantFound {
+			t.Errorf("Lookup(%q) found = %v, want %v", tt.path, found, tt.wantFound)
+			continue
+		}
+		if found && String(v) != tt.want {
+			t.Errorf("Lookup(%q) = %s, want %s", tt.path, String(v), tt.want)
+		}
+	}
+}
+
+func TestLookupRoot(t *testing.T) {
+	doc, err := Decode([]byte(`[1, 2]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, found, err := Lookup(doc, "$")
+	if err != nil || !found || String(v) != "[1,2]" {
+		t.Errorf("Lookup($) = %s, %v, %v, want [1,2], true, nil", String(v), found, err)
+	}
+}
@@ -0,0 +1,59 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+ Schema =>
+	 monitor:cert_alert:<id> -> unix_ts of certificate expiry, which was alerted ( with TTL )
+	 monitor:cert_expiring:<id> -> unix_ts of certificate expiry, alerted and not yet renewed ( expires certExpiringKeep after the cert )
+*/
+
+// how long an expiring cert is remembered after it expired, a renewal after that is not told
+const certExpiringKeep = 30 * 24 * time.Hour
+
+// MarkCertAlertedIfNotSet returns true if certificate expiry of monitor was not alerted in last ttl,
+// the certificate is then remembered as expiring until it is renewed
+func (c *Client) MarkCertAlertedIfNotSet(ctx context.Context, monitorID uuid.UUID, notAfter time.Time, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("monitor:cert_alert:%v", monitorID.String())
+
+	ok, err := c.rdb.SetNX(ctx, key, notAfter.Unix(), ttl).Result()
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	expiringKey := fmt.Sprintf("monitor:cert_expiring:%v", monitorID.String())
+	keep := max(time.Until(notAfter), 0) + certExpiringKeep
+	return true, c.rdb.Set(ctx, expiringKey, notAfter.Unix(), keep).Err()
+}
+
+// ClearCertExpiringIfRenewed returns true if an expiring certificate of monitor was alerted and the one
+// now served expires later (notAfter), only one caller gets true for a renewal
+func (c *Client) ClearCertExpiringIfRenewed(ctx context.Context, monitorID uuid.UUID, notAfter time.Time) (bool, error) {
+	expiringKey := fmt.Sprintf("monitor:cert_expiring:%v", monitorID.String())
+
+	alerted, err := c.rdb.Get(ctx, expiringKey).Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if notAfter.Unix() <= alerted {
+		return false, nil
+	}
+
+	n, err := c.rdb.Del(ctx, expiringKey).Result()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	// new cert is alerted as soon as it is expiring, not after interval of the old one
+	key := fmt.Sprintf("monitor:cert_alert:%v", monitorID.String())
+	return true, c.rdb.Del(ctx, key).Err()
+}
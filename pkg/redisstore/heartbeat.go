@@ -0,0 +1,49 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+ Schema =>
+	 monitor:heartbeat:<id> -> unix_ms of last ping of a heartbeat monitor
+*/
+
+func (c *Client) StoreHeartbeat(ctx context.Context, monitorID uuid.UUID, at time.Time) error {
+	key := fmt.Sprintf("monitor:heartbeat:%v", monitorID.String())
+
+	return retry(ctx, 2, func() error {
+		return c.rdb.Set(ctx, key, at.UnixMilli(), 0).Err()
+	})
+}
+
+// LastHeartbeat returns time of last ping, zero time if monitor was never pinged
+func (c *Client) LastHeartbeat(ctx context.Context, monitorID uuid.UUID) (time.Time, error) {
+	key := fmt.Sprintf("monitor:heartbeat:%v", monitorID.String())
+
+	res, err := c.rdb.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ms, err := strconv.ParseInt(res, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
+func (c *Client) DelHeartbeat(ctx context.Context, monitorID uuid.UUID) error {
+	key := fmt.Sprintf("monitor:heartbeat:%v", monitorID.String())
+
+	return c.rdb.Del(ctx, key).Err()
+}
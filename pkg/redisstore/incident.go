@@ -16,8 +16,10 @@ import (
 		   failure_count: int
 		   first_failure_at: unix_ts
 		   last_failure_at: unix_ts
+		   last_reason: string
 		   alerted: bool
 		   db_incident: bool
+		   incident_id: uuid ( of DB incident, DOWN was alerted with it )
 		 }
 */
 
@@ -34,12 +36,14 @@ import (
 // 	})
 // }
 
-func (c *Client) IncrementIncident(ctx context.Context, monitorID uuid.UUID) (int64, bool, error) {
+// IncrementIncident counts a failure of monitor, reason is kept as last reason of incident,
+// it returns failure count and time of first failure of the incident (failedAt of the first call)
+func (c *Client) IncrementIncident(ctx context.Context, monitorID uuid.UUID, reason string, failedAt time.Time) (int64, time.Time, error) {
 	key := fmt.Sprintf("monitor:incident:%v", monitorID.String())
-	now := time.Now().Unix()
+	ts := failedAt.Unix()
 
 	var failureCount int64
-	var firstTime bool
+	var firstFailureAt time.Time
 
 	err := retry(ctx, 3, func() error {
 		var err error
@@ -50,23 +54,26 @@ func (c *Client) IncrementIncident(ctx context.Context, monitorID uuid.UUID) (in
 			return err
 		}
 
-		// 2. Set timestamps
-		if failureCount == 1 {
-			// first incident -> new inicident
-			c.rdb.HSet(ctx, key,
-				"first_failure_at", now,
-				"last_failure_at", now,
-			)
-			firstTime = true
-		} else {
-			// existing increment
-			c.rdb.HSet(ctx, key, "last_failure_at", now)
+		// 2. Set timestamps, first failure is only set by the failure opening the incident
+		if err := c.rdb.HSetNX(ctx, key, "first_failure_at", ts).Err(); err != nil {
+			return err
+		}
+		if err := c.rdb.HSet(ctx, key,
+			"last_failure_at", ts,
+			"last_reason", reason,
+		).Err(); err != nil {
+			return err
 		}
 
+		first, err := c.rdb.HGet(ctx, key, "first_failure_at").Int64()
+		if err != nil {
+			return err
+		}
+		firstFailureAt = time.Unix(first, 0)
 		return nil
 	})
 
-	return failureCount, firstTime, err
+	return failureCount, firstFailureAt, err
 }
 
 func (c *Client) ClearIncident(ctx context.Context, monitorID uuid.UUID) error {
@@ -110,6 +117,17 @@ func (c *Client) GetIncident(ctx context.Context, monitorID uuid.UUID) (map[stri
 	return resp, err
 }
 
+// HasIncident reports whether monitor has an open incident (failures not yet cleared by a success)
+func (c *Client) HasIncident(ctx context.Context, monitorID uuid.UUID) (bool, error) {
+	key := fmt.Sprintf("monitor:incident:%v", monitorID.String())
+
+	n, err := c.rdb.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 func (c *Client) ClearIncidentIfExists(ctx context.Context, monitorID uuid.UUID) (bool, error) {
 	key := fmt.Sprintf("monitor:incident:%v", monitorID.String())
 
@@ -132,6 +150,15 @@ func (c *Client) MarkDBIncidentCreated(ctx context.Context, monitorID uuid.UUID)
 	).Err()
 }
 
+// SetIncidentID keeps id of DB incident monitor was alerted about, RECOVERED is sent with the same id
+func (c *Client) SetIncidentID(ctx context.Context, monitorID, incidentID uuid.UUID) error {
+	key := fmt.Sprintf("monitor:incident:%v", monitorID.String())
+
+	return retry(ctx, 3, func() error {
+		return c.rdb.HSet(ctx, key, "incident_id", incidentID.String()).Err()
+	})
+}
+
 func (c *Client) MarkIncidentAlertedIfNotSet(ctx context.Context, monitorID uuid.UUID) (bool, error) {
 	key := fmt.Sprintf("monitor:incident:%v", monitorID.String())
 
@@ -0,0 +1,33 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+/*
+ Schema =>
+	 lock:<name> -> id of instance holding the lock (expires), for jobs only one instance should run
+*/
+
+// AcquireLock takes lock name for ttl, false if another instance holds it
+func (c *Client) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("lock:%s", name)
+
+	return c.rdb.SetNX(ctx, key, owner, ttl).Result()
+}
+
+// ReleaseLock releases lock name, only if owner still holds it
+func (c *Client) ReleaseLock(ctx context.Context, name, owner string) error {
+	key := fmt.Sprintf("lock:%s", name)
+
+	return c.rdb.Eval(ctx, releaseLockScript, []string{key}, owner).Err()
+}
+
+const releaseLockScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`
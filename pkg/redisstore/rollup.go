@@ -0,0 +1,42 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+ Schema =>
+	 rollup:watermark:<resolution> -> unix_ms, start of first bucket of resolution not rolled up for good
+*/
+
+// RollupWatermark returns watermark of resolution, zero time if rollup never ran
+func (c *Client) RollupWatermark(ctx context.Context, resolution string) (time.Time, error) {
+	key := fmt.Sprintf("rollup:watermark:%s", resolution)
+
+	res, err := c.rdb.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ms, err := strconv.ParseInt(res, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms).UTC(), nil
+}
+
+func (c *Client) SetRollupWatermark(ctx context.Context, resolution string, at time.Time) error {
+	key := fmt.Sprintf("rollup:watermark:%s", resolution)
+
+	return retry(ctx, 2, func() error {
+		return c.rdb.Set(ctx, key, at.UnixMilli(), 0).Err()
+	})
+}
@@ -3,21 +3,125 @@ package redisstore
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
-func (c *Client) StoreStatus(ctx context.Context, monitorID uuid.UUID, statusCode int, latencyMs int64, checkedAt time.Time) error {
+/*
+ Schema =>
+	 monitor:status:<id>
+		 {
+		   status_code: int
+		   latency_ms: int
+		   remote_ip: string
+		   checked_at: unix_ts
+		   reason: string
+		   detail: string
+		   dns_ms: int
+		   connect_ms: int
+		   tls_ms: int
+		   ttfb_ms: int
+		   download_ms: int
+		   round_trip_ms: int
+		   cert_expires_at: unix_ts   ( cert_* only for https checks, removed when a check has no cert )
+		   cert_subject: string
+		   cert_issuer: string
+		   cert_sans: comma seperated names
+		   cert_valid: bool
+		   redirects: "301 http://a -> 200 https://b"   ( empty if no redirect was followed )
+		   steps: "1 login 200 120ms, 2 me 401 30ms"    ( transaction checks )
+		   failed_step: int                              ( 0 if no step failed )
+		 }
+*/
+
+// Status is the latest check outcome of a monitor, stored in monitor:status:<id>
+type Status struct {
+	StatusCode int
+	LatencyMs  int64
+	RemoteIP   string
+	CheckedAt  time.Time
+	Reason     string
+	Detail     string
+
+	// phase timings of the check
+	DNSMs      int64
+	ConnectMs  int64
+	TLSMs      int64
+	TTFBMs     int64
+	DownloadMs int64
+	// websocket message round trip
+	RoundTripMs int64
+
+	// leaf certificate, zero CertExpiresAt means no certificate
+	CertExpiresAt time.Time
+	CertSubject   string
+	CertIssuer    string
+	CertSANs      []string
+	CertValid     bool
+
+	// followed redirects as "<status> <url>", last one is the final response
+	Redirects []string
+
+	// transaction steps as "<index> <name> <status> <latency>ms"
+	Steps      []string
+	FailedStep int
+}
+
+func (s Status) fields() map[string]any {
+	f := map[string]any{
+		"status_code": s.StatusCode,
+		"latency_ms":  s.LatencyMs,
+		"remote_ip":   s.RemoteIP,
+		"checked_at":  s.CheckedAt.Unix(),
+		"reason":      s.Reason,
+		"detail":      s.Detail,
+		"dns_ms":      s.DNSMs,
+		"connect_ms":  s.ConnectMs,
+		"tls_ms":      s.TLSMs,
+		"ttfb_ms":     s.TTFBMs,
+		"download_ms": s.DownloadMs,
+
+		"round_trip_ms": s.RoundTripMs,
+	}
+	if !s.CertExpiresAt.IsZero() {
+		f["cert_expires_at"] = s.CertExpiresAt.Unix()
+		f["cert_subject"] = s.CertSubject
+		f["cert_issuer"] = s.CertIssuer
+		f["cert_sans"] = strings.Join(s.CertSANs, ",")
+		f["cert_valid"] = s.CertValid
+	}
+	// always set, so values of older check do not stay
+	f["redirects"] = strings.Join(s.Redirects, " -> ")
+	f["steps"] = strings.Join(s.Steps, ", ")
+	f["failed_step"] = s.FailedStep
+	return f
+}
+
+var certFields = []string{"cert_expires_at", "cert_subject", "cert_issuer", "cert_sans", "cert_valid"}
+
+// staleFields are fields s has no value for, they are removed so values of older check do not stay
+func (s Status) staleFields() []string {
+	if s.CertExpiresAt.IsZero() {
+		return certFields
+	}
+	return nil
+}
+
+func (c *Client) StoreStatus(ctx context.Context, monitorID uuid.UUID, status Status) error {
 	key := fmt.Sprintf("monitor:status:%v",monitorID)
 
 	return retry(ctx, 2, func() error {
-		return c.rdb.HSet(ctx, key, map[string]any{
-			"status_code": statusCode,
-			"latency_ms": latencyMs,
-			"checked_at": checkedAt.Unix(),
-		}).Err()
+		_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, status.fields())
+			if stale := status.staleFields(); len(stale) > 0 {
+				pipe.HDel(ctx, key, stale...)
+			}
+			return nil
+		})
+		return err
 	})
 }
 
@@ -0,0 +1,37 @@
+package redisstore
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStatusCertFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		status Status
+		cert   bool
+	}{
+		{name: "https check", status: Status{CertExpiresAt: time.Unix(1800000000, 0), CertSANs: []string{"a.example", "b.example"}, CertValid: true}, cert: true},
+		{name: "plain http check", status: Status{StatusCode: 200}},
+		{name: "failed before handshake", status: Status{Reason: "TIMEOUT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, stale := tt.status.fields(), tt.status.staleFields()
+			for _, k := range certFields {
+				_, set := fields[k]
+				if set != tt.cert {
+					t.Errorf("%s set = %v, want %v", k, set, tt.cert)
+				}
+				if removed := slices.Contains(stale, k); removed == tt.cert {
+					t.Errorf("%s removed = %v, want %v", k, removed, !tt.cert)
+				}
+			}
+			if tt.cert && fields["cert_sans"] != "a.example,b.example" {
+				t.Errorf("cert_sans = %v", fields["cert_sans"])
+			}
+		})
+	}
+}
@@ -1,10 +1,44 @@
--- name: CreateAlert :exec
+-- name: CreateAlert :one
 INSERT INTO
-alerts (incident_id, alert_email) 
-VALUES ($1, $2);
+alerts (incident_id, monitor_id, alert_type, alert_email, channel_id, channel_type, payload) 
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
 
 -- name: UpdateAlertStatus :execrows
 UPDATE alerts
 SET
-    status = $2
-WHERE id = $1;
\ No newline at end of file
+    status = $2,
+    attempts = $3,
+    last_error = $4,
+    sent_at = CASE WHEN $2 = 'sent' THEN now() ELSE sent_at END,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: MarkAlertPending :execrows
+-- a pending alert is being delivered, unless it was left pending (crash) long ago
+UPDATE alerts
+SET status = 'pending', updated_at = now()
+WHERE id = $1 AND (status <> 'pending' OR updated_at < now() - interval '10 minutes');
+
+-- name: GetChannelAlert :one
+SELECT a.id, a.incident_id, a.sent_at, a.alert_email, a.status, a.created_at, a.monitor_id, a.alert_type, a.attempts, a.last_error, a.updated_at, a.channel_id, a.channel_type, a.payload
+FROM alerts a
+JOIN notification_channels c ON c.id = a.channel_id
+WHERE a.id = $1 AND a.channel_id = $2 AND c.user_id = $3;
+
+-- name: ListChannelAlerts :many
+SELECT id, incident_id, sent_at, alert_email, status, created_at, monitor_id, alert_type, attempts, last_error, updated_at, channel_id, channel_type, payload
+FROM alerts
+WHERE channel_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CreateAlertAttempt :exec
+INSERT INTO alert_attempts (alert_id, attempt, success, status_code, latency_ms, response_excerpt, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListAlertAttempts :many
+SELECT id, alert_id, attempt, success, status_code, latency_ms, response_excerpt, error, created_at
+FROM alert_attempts
+WHERE alert_id = ANY(sqlc.arg(alert_ids)::uuid[])
+ORDER BY alert_id, attempt;
@@ -0,0 +1,42 @@
+-- name: CountCheckResultsInRange :one
+SELECT count(*) AS total, count(*) FILTER (WHERE NOT success) AS failed
+FROM check_results
+WHERE monitor_id = $1
+  AND checked_at >= sqlc.arg(range_start)
+  AND checked_at < sqlc.arg(range_end);
+
+-- name: CountUserCheckResultsInRange :many
+SELECT c.monitor_id, count(*) AS total, count(*) FILTER (WHERE NOT c.success) AS failed
+FROM check_results c
+JOIN monitors m ON m.id = c.monitor_id
+WHERE m.user_id = $1
+  AND c.checked_at >= sqlc.arg(range_start)
+  AND c.checked_at < sqlc.arg(range_end)
+GROUP BY c.monitor_id;
+
+-- name: InsertCheckResult :exec
+INSERT INTO check_results (monitor_id, checked_at, success, http_status, latency_ms, reason, region)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: InsertCheckResults :copyfrom
+INSERT INTO check_results (monitor_id, checked_at, success, http_status, latency_ms, reason, region)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListCheckResults :many
+SELECT id, monitor_id, checked_at, success, http_status, latency_ms, reason, region
+FROM check_results
+WHERE monitor_id = $1
+ORDER BY checked_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: DeleteExpiredCheckResults :execrows
+DELETE FROM check_results
+WHERE id IN (
+    SELECT c.id
+    FROM check_results c
+    JOIN monitors m ON m.id = c.monitor_id
+    JOIN users u ON u.id = m.user_id
+    WHERE c.checked_at < GREATEST(sqlc.arg(free_cutoff)::timestamptz, sqlc.arg(paid_cutoff)::timestamptz)
+      AND c.checked_at < CASE WHEN COALESCE(u.is_paid_user, false) THEN sqlc.arg(paid_cutoff)::timestamptz ELSE sqlc.arg(free_cutoff)::timestamptz END
+    LIMIT sqlc.arg(batch_size)
+);
@@ -0,0 +1,61 @@
+-- name: ListLatencyCounts :many
+SELECT monitor_id, date_trunc('minute', checked_at)::timestamptz AS bucket_start, latency_ms, count(*) AS samples
+FROM check_results
+WHERE checked_at >= sqlc.arg(range_start)
+  AND checked_at < sqlc.arg(range_end)
+  AND (success OR http_status > 0)
+GROUP BY monitor_id, bucket_start, latency_ms;
+
+-- name: ListLatencyRollups :many
+SELECT monitor_id, resolution, bucket_start, count, min_ms, max_ms, sum_ms, p50_ms, p95_ms, p99_ms, histogram
+FROM latency_rollups
+WHERE resolution = $1
+  AND bucket_start >= sqlc.arg(range_start)
+  AND bucket_start < sqlc.arg(range_end);
+
+-- name: ListMonitorLatencyRollups :many
+SELECT monitor_id, resolution, bucket_start, count, min_ms, max_ms, sum_ms, p50_ms, p95_ms, p99_ms, histogram
+FROM latency_rollups
+WHERE monitor_id = $1
+  AND resolution = $2
+  AND bucket_start >= sqlc.arg(range_start)
+  AND bucket_start < sqlc.arg(range_end)
+ORDER BY bucket_start;
+
+-- name: UpsertLatencyRollups :exec
+INSERT INTO latency_rollups (monitor_id, resolution, bucket_start, count, min_ms, max_ms, sum_ms, p50_ms, p95_ms, p99_ms, histogram)
+SELECT t.monitor_id, sqlc.arg(resolution)::text, t.bucket_start, t.count, t.min_ms, t.max_ms, t.sum_ms, t.p50_ms, t.p95_ms, t.p99_ms, t.histogram
+FROM unnest(
+    sqlc.arg(monitor_ids)::uuid[],
+    sqlc.arg(bucket_starts)::timestamptz[],
+    sqlc.arg(counts)::bigint[],
+    sqlc.arg(min_ms)::int[],
+    sqlc.arg(max_ms)::int[],
+    sqlc.arg(sum_ms)::bigint[],
+    sqlc.arg(p50_ms)::int[],
+    sqlc.arg(p95_ms)::int[],
+    sqlc.arg(p99_ms)::int[],
+    sqlc.arg(histograms)::jsonb[]
+) AS t(monitor_id, bucket_start, count, min_ms, max_ms, sum_ms, p50_ms, p95_ms, p99_ms, histogram)
+ON CONFLICT (monitor_id, resolution, bucket_start) DO UPDATE
+SET count = EXCLUDED.count,
+    min_ms = EXCLUDED.min_ms,
+    max_ms = EXCLUDED.max_ms,
+    sum_ms = EXCLUDED.sum_ms,
+    p50_ms = EXCLUDED.p50_ms,
+    p95_ms = EXCLUDED.p95_ms,
+    p99_ms = EXCLUDED.p99_ms,
+    histogram = EXCLUDED.histogram;
+
+-- name: DeleteExpiredLatencyRollups :execrows
+DELETE FROM latency_rollups
+WHERE (monitor_id, resolution, bucket_start) IN (
+    SELECT r.monitor_id, r.resolution, r.bucket_start
+    FROM latency_rollups r
+    JOIN monitors m ON m.id = r.monitor_id
+    JOIN users u ON u.id = m.user_id
+    WHERE r.resolution = sqlc.arg(resolution)
+      AND r.bucket_start < GREATEST(sqlc.arg(free_cutoff)::timestamptz, sqlc.arg(paid_cutoff)::timestamptz)
+      AND r.bucket_start < CASE WHEN COALESCE(u.is_paid_user, false) THEN sqlc.arg(paid_cutoff)::timestamptz ELSE sqlc.arg(free_cutoff)::timestamptz END
+    LIMIT sqlc.arg(batch_size)
+);
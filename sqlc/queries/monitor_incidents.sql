@@ -1,13 +1,39 @@
--- name: CreateMonitorIncident :exec
-INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms)
-VALUES ($1, $2, $3, $4, $5);
+-- name: CreateMonitorIncident :one
+INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms, evidence)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
 
 -- name: GetMonitorIncidentByID :one
-SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at
+SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence
 FROM monitor_incidents
 WHERE id = $1;
 
--- name: CloseMonitorIncident :execrows
+-- name: ListMonitorIncidents :many
+SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence
+FROM monitor_incidents
+WHERE monitor_id = $1
+ORDER BY start_time DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListMonitorIncidentsInRange :many
+SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence
+FROM monitor_incidents
+WHERE monitor_id = $1
+  AND (end_time IS NULL OR end_time > sqlc.arg(range_start))
+  AND start_time < sqlc.arg(range_end)
+ORDER BY start_time;
+
+-- name: ListUserIncidentsInRange :many
+SELECT i.id, i.monitor_id, i.start_time, i.end_time, i.alerted, i.http_status, i.latency_ms, i.created_at, i.evidence
+FROM monitor_incidents i
+JOIN monitors m ON m.id = i.monitor_id
+WHERE m.user_id = $1
+  AND (i.end_time IS NULL OR i.end_time > sqlc.arg(range_start))
+  AND i.start_time < sqlc.arg(range_end)
+ORDER BY i.monitor_id, i.start_time;
+
+-- name: CloseMonitorIncident :many
 UPDATE monitor_incidents
-SET end_time = $2
-WHERE monitor_id = $1 AND end_time IS NULL;
+SET end_time = GREATEST(start_time, $2)
+WHERE monitor_id = $1 AND end_time IS NULL
+RETURNING id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, created_at, evidence;
@@ -6,7 +6,15 @@ INSERT INTO monitors (
     timeout_sec,
     latency_threshold_ms,
     expected_status,
-    alert_email
+    alert_email,
+    method,
+    headers,
+    body,
+    assertions,
+    type,
+    options,
+    heartbeat_token,
+    auth
 ) VALUES (
     $1,
     $2,
@@ -14,20 +22,33 @@ INSERT INTO monitors (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14,
+    $15
 )
 RETURNING id;
 
 -- name: GetMonitorByID :one
-SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT *
 FROM monitors
 WHERE id = $1;
 
 -- name: GetMonitor :one
-SELECT id, user_id, url, alert_email, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT *
 FROM monitors
 WHERE id = $1 AND user_id = $2;
 
+-- name: GetMonitorByHeartbeatToken :one
+SELECT *
+FROM monitors
+WHERE heartbeat_token = $1;
+
 -- name: GetAllMonitorByUserID :many
 SELECT *
 FROM monitors
@@ -40,3 +61,13 @@ OFFSET $3;
 UPDATE monitors
 SET enabled = $2
 WHERE id = $1 AND user_id = $3;
+
+-- name: ListMonitorsWithPlainAuth :many
+SELECT id, auth
+FROM monitors
+WHERE auth IS NOT NULL AND NOT jsonb_exists(auth, 'sealed');
+
+-- name: UpdateMonitorAuth :exec
+UPDATE monitors
+SET auth = $2
+WHERE id = $1;
@@ -0,0 +1,72 @@
+-- name: CreateNotificationChannel :one
+INSERT INTO notification_channels (user_id, name, type, config)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, name, type, config, enabled, created_at, updated_at;
+
+-- name: GetNotificationChannel :one
+SELECT id, user_id, name, type, config, enabled, created_at, updated_at
+FROM notification_channels
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListNotificationChannels :many
+SELECT id, user_id, name, type, config, enabled, created_at, updated_at
+FROM notification_channels
+WHERE user_id = $1
+ORDER BY created_at
+LIMIT $2 OFFSET $3;
+
+-- name: ListNotificationChannelsWithPlainConfig :many
+-- channels stored before their secrets were sealed
+SELECT id, config
+FROM notification_channels
+WHERE config->>'url' NOT LIKE 'v1.%'
+   OR config->>'secret' NOT LIKE 'v1.%'
+   OR config->>'routing_key' NOT LIKE 'v1.%'
+   OR config->>'api_key' NOT LIKE 'v1.%'
+   OR EXISTS (
+       SELECT 1 FROM jsonb_each_text(COALESCE(config->'headers', '{}'::jsonb)) h
+       WHERE h.value NOT LIKE 'v1.%'
+   );
+
+-- name: UpdateNotificationChannel :one
+UPDATE notification_channels
+SET
+    name = $3,
+    config = $4,
+    enabled = $5,
+    updated_at = now()
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, name, type, config, enabled, created_at, updated_at;
+
+-- name: DeleteNotificationChannel :execrows
+DELETE FROM notification_channels
+WHERE id = $1 AND user_id = $2;
+
+-- name: CountUserNotificationChannels :one
+SELECT COUNT(*)
+FROM notification_channels
+WHERE user_id = $1 AND id = ANY(sqlc.arg(channel_ids)::uuid[]);
+
+-- name: ListMonitorNotificationChannels :many
+SELECT c.id, c.user_id, c.name, c.type, c.config, c.enabled, c.created_at, c.updated_at
+FROM notification_channels c
+JOIN monitor_notification_channels l ON l.channel_id = c.id
+WHERE l.monitor_id = $1
+ORDER BY c.created_at;
+
+-- name: SetMonitorNotificationChannels :exec
+-- replaces channels of monitor with channel_ids, in one statement so a concurrent alert sees old or new set
+WITH removed AS (
+    DELETE FROM monitor_notification_channels
+    WHERE monitor_id = $1 AND NOT (channel_id = ANY(sqlc.arg(channel_ids)::uuid[]))
+)
+INSERT INTO monitor_notification_channels (monitor_id, channel_id)
+SELECT $1, c.id
+FROM notification_channels c
+WHERE c.user_id = $2 AND c.id = ANY(sqlc.arg(channel_ids)::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: UpdateNotificationChannelConfig :exec
+UPDATE notification_channels
+SET config = $2
+WHERE id = $1;